@@ -4,8 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"net/netip"
 
+	"github.com/Bayan2019/go-ozinshe/hls"
+	"github.com/Bayan2019/go-ozinshe/mail"
 	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/throttle"
 	// _ "github.com/mattn/go-sqlite3"
 	_ "github.com/tursodatabase/libsql-client-go/libsql"
 )
@@ -17,7 +22,21 @@ type ApiConfiguration struct {
 	Conn      *sql.DB
 	DB        *database.Queries
 	Dir       string
+	Storage   storage.Storage
 	JwtSecret string
+	// Packager packages videos for HLS, nil without ffmpeg
+	Packager *hls.Packager
+	Mailer   mail.Mailer
+	// PasswordResetURL is the frontend page of the password reset emails
+	PasswordResetURL string
+	// EmailVerification policy: off, limit or block
+	EmailVerification string
+	// EmailVerificationURL is the frontend page of the verification emails
+	EmailVerificationURL string
+	// LoginAttempts keeps the failed sign ins and the password reset requests, in memory when nil
+	LoginAttempts throttle.Store
+	// TrustedProxies whose X-Forwarded-For gives the client address, none by default
+	TrustedProxies []netip.Prefix
 }
 
 func Connect2DB(dbPath string) error {
@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -35,17 +34,6 @@ func NewAgeCategoriesHandlers(db *database.Queries) *AgeCategoriesHandlers {
 // @Router       /v1/age-categories [get]
 // @Security Bearer
 func (ach *AgeCategoriesHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.AgeCategories >= 2 {
-			can_do = true
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	genres, err := ach.DB.GetAgeCategories(r.Context())
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get genres", err)
@@ -71,18 +59,6 @@ func (ach *AgeCategoriesHandlers) GetAll(w http.ResponseWriter, r *http.Request,
 // @Router       /v1/age-categories [post]
 // @Security Bearer
 func (ach *AgeCategoriesHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.AgeCategories == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	decoder := json.NewDecoder(r.Body)
 	cacr := views.CreateAgeCategoryRequest{}
 
@@ -119,18 +95,6 @@ func (ach *AgeCategoriesHandlers) Create(w http.ResponseWriter, r *http.Request,
 // @Router       /v1/age-categories/{id} [get]
 // @Security Bearer
 func (ach *AgeCategoriesHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.AgeCategories >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -163,18 +127,6 @@ func (ach *AgeCategoriesHandlers) Get(w http.ResponseWriter, r *http.Request, us
 // @Router       /v1/age-categories/{id} [put]
 // @Security Bearer
 func (ach *AgeCategoriesHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.AgeCategories == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -218,18 +170,6 @@ func (ach *AgeCategoriesHandlers) Update(w http.ResponseWriter, r *http.Request,
 // @Router       /v1/age-categories/{id} [delete]
 // @Security Bearer
 func (ach *AgeCategoriesHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.AgeCategories >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
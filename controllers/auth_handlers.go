@@ -1,17 +1,26 @@
 package controllers
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/throttle"
 	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -21,17 +30,47 @@ const (
 	// TokenTypeAccess -
 	// Set the Issuer to "ozinshe"
 	TokenTypeAccess TokenType = "ozinshe-access"
+	// TokenTypeChallenge is the first step of the sign in with 2FA
+	TokenTypeChallenge TokenType = "ozinshe-2fa"
+)
+
+// refreshTokenTTL is the lifetime of a refresh token,
+// every refresh issues a new one
+const refreshTokenTTL = time.Hour * 24 * 60
+
+// Policies of the failed sign ins, an address is shared by many users
+var (
+	accountsPolicy = throttle.Policy{
+		Free:    5,
+		Delay:   time.Second,
+		Lockout: 15 * time.Minute,
+		Window:  time.Hour,
+	}
+	addressesPolicy = throttle.Policy{
+		Free:    20,
+		Delay:   time.Second,
+		Lockout: 15 * time.Minute,
+		Window:  time.Hour,
+	}
 )
 
 type AuthHandlers struct {
 	DB        *database.Queries
 	JwtSecret string
+	// Verification restricts the users with unverified emails
+	Verification VerificationPolicy
+	// Accounts and Addresses slow down the guessing of the passwords
+	Accounts  *throttle.Limiter
+	Addresses *throttle.Limiter
 }
 
-func NewAuthHandlers(db *database.Queries, jwtSecret string) *AuthHandlers {
+func NewAuthHandlers(db *database.Queries, jwtSecret string, verification VerificationPolicy, attempts throttle.Store) *AuthHandlers {
 	return &AuthHandlers{
-		DB:        db,
-		JwtSecret: jwtSecret,
+		DB:           db,
+		JwtSecret:    jwtSecret,
+		Verification: verification,
+		Accounts:     throttle.NewLimiter(attempts, "account:", accountsPolicy),
+		Addresses:    throttle.NewLimiter(attempts, "ip:", addressesPolicy),
 	}
 }
 
@@ -45,7 +84,7 @@ func (ah *AuthHandlers) MiddlewareAuth(handler authedHandler) http.HandlerFunc {
 			return
 		}
 
-		email, err := validateJWT(jwtToken, ah.JwtSecret)
+		email, session, err := validateJWT(jwtToken, ah.JwtSecret)
 		if err != nil {
 			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get email from token", err)
 			return
@@ -57,29 +96,67 @@ func (ah *AuthHandlers) MiddlewareAuth(handler authedHandler) http.HandlerFunc {
 			return
 		}
 
-		_, err = ah.DB.GetRefreshTokenOfUser(r.Context(), user.ID)
-		if err != nil {
-			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't find refresh_token for user", err)
-			return
+		if session != "" {
+			active, err := ah.DB.IsSessionActive(r.Context(), database.IsSessionActiveParams{
+				Family: session,
+				Now:    time.Now().UTC().Format(time.RFC3339),
+			})
+			if err != nil {
+				views.RespondWithError(w, http.StatusInternalServerError, "Couldn't check session", err)
+				return
+			}
+			if !active {
+				views.RespondWithError(w, http.StatusUnauthorized, "Session is revoked, sign in again", nil)
+				return
+			}
+		} else {
+			_, err = ah.DB.GetRefreshTokenOfUser(r.Context(), user.ID)
+			if err != nil {
+				views.RespondWithError(w, http.StatusInternalServerError, "Couldn't find refresh_token for user", err)
+				return
+			}
 		}
 
-		roles, err := ah.DB.GetRolesOfUser(r.Context(), user.ID)
+		roles, err := ah.rolesOf(r.Context(), user)
 		if err != nil {
 			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get roles", err)
 			return
 		}
 
 		handler(w, r, views.User{
-			Id:          user.ID,
-			Name:        user.Name,
-			Email:       user.Email,
-			DateOfBirth: user.DateOfBirth,
-			Phone:       user.Phone,
-			Roles:       roles,
+			Id:            user.ID,
+			Name:          user.Name,
+			Email:         user.Email,
+			DateOfBirth:   user.DateOfBirth,
+			Phone:         user.Phone,
+			Roles:         roles,
+			EmailVerified: user.EmailVerifiedAt.Valid,
+			TwoFactor:     user.TotpEnabledAt.Valid,
+			Session:       session,
 		})
 	}
 }
 
+// rolesOf returns no roles for the unverified users unless the verification is off,
+// so they can manage only their profile,
+// and the roles requiring 2FA only for the users with 2FA on
+func (ah *AuthHandlers) rolesOf(ctx context.Context, user database.User) ([]database.Role, error) {
+	if ah.Verification != VerificationOff && ah.Verification != "" && !user.EmailVerifiedAt.Valid {
+		return []database.Role{}, nil
+	}
+	roles, err := ah.DB.GetRolesOfUser(ctx, user.ID)
+	if err != nil || user.TotpEnabledAt.Valid {
+		return roles, err
+	}
+	granted := []database.Role{}
+	for _, role := range roles {
+		if !role.RequireTwoFactor {
+			granted = append(granted, role)
+		}
+	}
+	return granted, nil
+}
+
 // SignIn godoc
 // @Tags Auth
 // @Summary      Sign In
@@ -87,9 +164,11 @@ func (ah *AuthHandlers) MiddlewareAuth(handler authedHandler) http.HandlerFunc {
 // @Produce      json
 // @Param request body views.SignInRequest true "Authentication"
 // @Success      200  {object} views.TokensResponse "OK"
+// @Success      202  {object} views.TwoFactorChallengeResponse "2FA code is required, see /v1/auth/sign-in/2fa"
 // @Failure   	 400  {object} views.ErrorResponse "Invalid Data"
 // @Failure   	 401  {object} views.ErrorResponse "Incorrect email or password"
-// @Failure      404  {object} views.ErrorResponse "Email not found"
+// @Failure   	 403  {object} views.ErrorResponse "Email isn't verified"
+// @Failure   	 429  {object} views.ErrorResponse "Too many attempts, see Retry-After"
 // @Failure   	 500  {object} views.ErrorResponse "Couldn't create tokens"
 // @Router       /v1/auth/sign-in [post]
 func (ah *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
@@ -101,20 +180,73 @@ func (ah *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := ah.DB.GetUserByEmail(r.Context(), signInReq.Email)
+	// the attempt is counted as a failure before the password is checked,
+	// so the concurrent guesses can't pass the limit
+	account := normalizeEmail(signInReq.Email)
+	ip := clientIP(r)
+	wait, err := throttleAttempt(r.Context(), ah.Addresses, ip, ah.Accounts, account)
 	if err != nil {
-		views.RespondWithError(w, http.StatusNotFound, "Couldn't find the user with such email", err)
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save sign in attempt", err)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		views.RespondWithError(w, http.StatusTooManyRequests, "Too many sign in attempts, try again later", nil)
 		return
 	}
 
-	err = checkPasswordHash(signInReq.Password, user.PasswordHash)
+	// unknown emails and wrong passwords are answered alike,
+	// the dummy hash takes as long to check as a real one
+	user, err := ah.DB.GetUserBySignInEmail(r.Context(), account)
 	if err != nil {
-		views.RespondWithError(w, http.StatusUnauthorized, "Incorrect password", err)
+		checkPasswordHash(signInReq.Password, dummyPasswordHash())
+	} else {
+		err = checkPasswordHash(signInReq.Password, user.PasswordHash)
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusUnauthorized, "Incorrect email or password", err)
+		return
+	}
+
+	// the right password isn't a failure, but the failures of the address aren't forgotten,
+	// so that signing in to an own account doesn't help to guess the others,
+	// and the failures of the account are kept until the second factor is passed,
+	// so that the password doesn't help to guess the codes
+	blocked := ah.Verification == VerificationBlock && !user.EmailVerifiedAt.Valid
+	forget := ah.Accounts.Reset
+	if blocked || user.TotpEnabledAt.Valid {
+		forget = ah.Accounts.Forgive
+	}
+	err = errors.Join(
+		ah.Addresses.Forgive(r.Context(), ip),
+		forget(r.Context(), account),
+	)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't reset sign in attempts", err)
+		return
+	}
+
+	if blocked {
+		views.RespondWithError(w, http.StatusForbidden, "Email isn't verified", nil)
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		ah.respondChallenge(w, user)
 		return
 	}
 
+	ah.startSession(w, r, user)
+}
+
+// startSession issues the tokens of a new session of the user
+func (ah *AuthHandlers) startSession(w http.ResponseWriter, r *http.Request, user database.User) {
+	// the sign in starts a new session, a family of refresh tokens
+	session := uuid.NewString()
+
 	accessToken, err := makeJWT(
 		user.Email,
+		session,
 		ah.JwtSecret,
 		time.Hour*24,
 	)
@@ -129,10 +261,15 @@ func (ah *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	now := time.Now().UTC()
 	err = ah.DB.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
-		Token:     refreshToken,
-		UserID:    user.ID,
-		ExpiresAt: time.Now().UTC().Add(time.Hour * 24 * 60).Format(time.RFC3339),
+		Token:      refreshToken,
+		UserID:     user.ID,
+		ExpiresAt:  now.Add(refreshTokenTTL).Format(time.RFC3339),
+		Family:     session,
+		UserAgent:  userAgent(r),
+		Ip:         clientIP(r),
+		SignedInAt: now.Format(time.RFC3339),
 	})
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token in DataBase", err)
@@ -145,14 +282,85 @@ func (ah *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// throttleAttempt counts the attempt of both keys or of none of them,
+// then it returns the longer wait of the two
+func throttleAttempt(ctx context.Context, first *throttle.Limiter, firstKey string, second *throttle.Limiter, secondKey string) (time.Duration, error) {
+	firstWait, err := first.Attempt(ctx, firstKey)
+	if err != nil {
+		return 0, err
+	}
+	if firstWait > 0 {
+		secondWait, err := second.Wait(ctx, secondKey)
+		return max(firstWait, secondWait), err
+	}
+
+	secondWait, err := second.Attempt(ctx, secondKey)
+	if err != nil || secondWait > 0 {
+		return secondWait, errors.Join(err, first.Forgive(ctx, firstKey))
+	}
+	return 0, nil
+}
+
+// normalizeEmail is the account of the sign in attempts and of the sign in lookup,
+// so the attempts of an account can't be reset by another spelling of its email
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := hashPassword("dummy password")
+	return hash
+})
+
+// Unlock godoc
+// @Tags Users
+// @Summary      Unlock User
+// @Description  Forgets the failed sign ins of the user, so the user can sign in at once
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "user id"
+// @Success      204
+// @Failure   	 400  {object} views.ErrorResponse "Invalid id"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't unlock user"
+// @Router       /v1/users/{id}/unlock [post]
+// @Security Bearer
+func (ah *AuthHandlers) Unlock(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	locked, err := ah.DB.GetUserById(r.Context(), id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't get user", err)
+		return
+	}
+
+	err = ah.Accounts.Reset(r.Context(), normalizeEmail(locked.Email))
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't unlock user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Refresh godoc
 // @Tags Auth
 // @Summary      Refresh
+// @Description  Rotates the refresh token: the used token is revoked and a new one is returned.
+// @Description  Reusing a rotated token revokes all the tokens of the sign in, the user has to sign in again.
 // @Accept       json
 // @Produce      json
 // @Param Authorization header string true "Bearer RefreshToken"
 // @Success      200  {object} views.TokensResponse
 // @Failure      400  {object} views.ErrorResponse "Couldn't find token"
+// @Failure      401  {object} views.ErrorResponse "Invalid, expired, revoked or reused refresh token"
 // @Failure   	 404  {object} views.ErrorResponse "Couldn't find user"
 // @Failure   	 500  {object} views.ErrorResponse "Couldn't create tokens"
 // @Router       /v1/auth/refresh [post]
@@ -163,14 +371,66 @@ func (ah *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := ah.DB.GetUserFromRefreshToken(r.Context(), refreshToken)
+	dToken, err := ah.DB.GetRefreshToken(r.Context(), refreshToken)
+	if err != nil {
+		views.RespondWithError(w, http.StatusUnauthorized, "Invalid refresh token", err)
+		return
+	}
+	if dToken.RevokedAt.Valid {
+		if dToken.ReplacedBy.Valid {
+			ah.revokeReusedFamily(w, r, dToken.Family)
+			return
+		}
+		views.RespondWithError(w, http.StatusUnauthorized, "Refresh token is revoked", nil)
+		return
+	}
+	expiresAt, err := time.Parse(time.RFC3339, dToken.ExpiresAt)
+	if err != nil || !time.Now().Before(expiresAt) {
+		views.RespondWithError(w, http.StatusUnauthorized, "Refresh token has expired", err)
+		return
+	}
+
+	user, err := ah.DB.GetUserById(r.Context(), dToken.UserID)
 	if err != nil {
 		views.RespondWithError(w, http.StatusNotFound, "Couldn't get user of refresh token", err)
 		return
 	}
 
+	newToken, err := makeRefreshToken()
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create refresh token", err)
+		return
+	}
+	err = ah.DB.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
+		Token:      newToken,
+		UserID:     user.ID,
+		ExpiresAt:  time.Now().UTC().Add(refreshTokenTTL).Format(time.RFC3339),
+		Family:     dToken.Family,
+		UserAgent:  dToken.UserAgent,
+		Ip:         dToken.Ip,
+		SignedInAt: dToken.SignedInAt,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token in DataBase", err)
+		return
+	}
+	rotated, err := ah.DB.RotateRefreshToken(r.Context(), database.RotateRefreshTokenParams{
+		ReplacedBy: sql.NullString{String: newToken, Valid: true},
+		Token:      refreshToken,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't revoke refresh token", err)
+		return
+	}
+	if rotated == 0 {
+		// a concurrent request has rotated the token first
+		ah.revokeReusedFamily(w, r, dToken.Family)
+		return
+	}
+
 	accessToken, err := makeJWT(
 		user.Email,
+		dToken.Family,
 		ah.JwtSecret,
 		time.Hour,
 	)
@@ -181,10 +441,21 @@ func (ah *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
 
 	views.RespondWithJSON(w, http.StatusOK, views.TokensResponse{
 		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		RefreshToken: newToken,
 	})
 }
 
+// revokeReusedFamily handles a rotated refresh token used again,
+// it may be stolen, so all the tokens of the family are revoked
+func (ah *AuthHandlers) revokeReusedFamily(w http.ResponseWriter, r *http.Request, family string) {
+	err := ah.DB.RevokeRefreshTokenFamily(r.Context(), family)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't revoke refresh tokens", err)
+		return
+	}
+	views.RespondWithError(w, http.StatusUnauthorized, "Refresh token was reused, sign in again", nil)
+}
+
 // SignOut godoc
 // @Tags Auth
 // @Summary      Sign Out
@@ -231,8 +502,26 @@ func getBearerToken(headers http.Header) (string, error) {
 	// return authHeader, nil
 }
 
+// accessClaims are the claims of the access token
+type accessClaims struct {
+	jwt.RegisteredClaims
+	// Session is the family of the refresh tokens of the sign in
+	Session string `json:"sid,omitempty"`
+}
+
 func makeJWT(
 	email string,
+	session string,
+	tokenSecret string,
+	expiresIn time.Duration,
+) (string, error) {
+	return signJWT(TokenTypeAccess, email, session, tokenSecret, expiresIn)
+}
+
+func signJWT(
+	tokenType TokenType,
+	email string,
+	session string,
 	tokenSecret string,
 	expiresIn time.Duration,
 ) (string, error) {
@@ -241,22 +530,29 @@ func makeJWT(
 	token := jwt.NewWithClaims(
 		// Use jwt.SigningMethodHS256 as the signing method.
 		jwt.SigningMethodHS256,
-		// Use jwt.RegisteredClaims as the claims
-		jwt.RegisteredClaims{
-			Issuer: string(TokenTypeAccess),
-			// Set IssuedAt to the current time in UTC
-			IssuedAt: jwt.NewNumericDate(time.Now().UTC()),
-			// Set ExpiresAt to the current time plus the expiration time (expiresIn)
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
-			// Set the Subject to a stringified version of the user's email
-			Subject: email,
+		accessClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: string(tokenType),
+				// Set IssuedAt to the current time in UTC
+				IssuedAt: jwt.NewNumericDate(time.Now().UTC()),
+				// Set ExpiresAt to the current time plus the expiration time (expiresIn)
+				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+				// Set the Subject to a stringified version of the user's email
+				Subject: email,
+			},
+			Session: session,
 		})
 	// Use token.SignedString to sign the token with the secret key.
 	return token.SignedString(signingKey)
 }
 
-func validateJWT(tokenString, tokenSecret string) (string, error) {
-	claimsStruct := jwt.RegisteredClaims{}
+// validateJWT returns the email and the session of the access token
+func validateJWT(tokenString, tokenSecret string) (string, string, error) {
+	return parseJWT(TokenTypeAccess, tokenString, tokenSecret)
+}
+
+func parseJWT(tokenType TokenType, tokenString, tokenSecret string) (string, string, error) {
+	claimsStruct := accessClaims{}
 	// Use the jwt.ParseWithClaims function
 	// to validate the signature of the JWT
 	// and extract the claims into a *jwt.Token struct.
@@ -268,7 +564,7 @@ func validateJWT(tokenString, tokenSecret string) (string, error) {
 		},
 	)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	// If all is well with the token,
@@ -277,18 +573,18 @@ func validateJWT(tokenString, tokenSecret string) (string, error) {
 	// (which should be stored in the Subject field).
 	email, err := token.Claims.GetSubject()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	issuer, err := token.Claims.GetIssuer()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	if issuer != string(TokenTypeAccess) {
-		return "", errors.New("invalid issuer")
+	if issuer != string(tokenType) {
+		return "", "", errors.New("invalid issuer")
 	}
 
-	return email, nil
+	return email, claimsStruct.Session, nil
 }
 
 // Hash the password using the bcrypt.GenerateFromPassword function
@@ -313,3 +609,10 @@ func makeRefreshToken() (string, error) {
 	}
 	return hex.EncodeToString(token), nil
 }
+
+// hashToken is the SHA-256 of a single-use token or recovery code kept in the DataBase,
+// a leaked table doesn't reveal the valid ones
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -65,7 +65,7 @@ func TestCheckPasswordHash(t *testing.T) {
 // 6. Authentication / 6. JWTs
 func TestValidateJWT(t *testing.T) {
 	userEmail := "admin@admin.com"
-	validToken, _ := makeJWT(userEmail, "secret", time.Hour)
+	validToken, _ := makeJWT(userEmail, "", "secret", time.Hour)
 
 	tests := []struct {
 		name          string
@@ -99,7 +99,7 @@ func TestValidateJWT(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotUserEmail, err := validateJWT(tt.tokenString, tt.tokenSecret)
+			gotUserEmail, _, err := validateJWT(tt.tokenString, tt.tokenSecret)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateJWT() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+var creditRoles = map[string]bool{
+	"director": true,
+	"producer": true,
+	"writer":   true,
+	"actor":    true,
+}
+
+type CreditsHandlers struct {
+	DB   *database.Queries
+	repo *repositories.ProjectsRepository
+}
+
+func NewCreditsHandlers(db *database.Queries, repo *repositories.ProjectsRepository) *CreditsHandlers {
+	return &CreditsHandlers{
+		DB:   db,
+		repo: repo,
+	}
+}
+
+// GetAll godoc
+// @Tags Credits
+// @Summary      Get Cast and Crew of Project
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      200  {array} views.Credit "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get credits"
+// @Router       /v1/projects/{id}/credits [get]
+// @Security Bearer
+func (ch *CreditsHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	project, err := ch.repo.GetById(r.Context(), int64(project_id))
+	if errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get credits", err)
+		return
+	}
+
+	credits := project.Credits
+	if credits == nil {
+		credits = []views.Credit{}
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, credits)
+}
+
+// Add godoc
+// @Tags Credits
+// @Summary      Add Person to Cast or Crew of Project
+// @Description  role is one of director, producer, writer and actor, character is for actors only
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Param request body views.AddCreditRequest true "Credit data"
+// @Success      201  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project or Person"
+// @Failure   	 409  {object} views.ErrorResponse "Credit already exists"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't add credit"
+// @Router       /v1/projects/{id}/credits [post]
+// @Security Bearer
+func (ch *CreditsHandlers) Add(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	acr := views.AddCreditRequest{}
+
+	err = decoder.Decode(&acr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of AddCreditRequest", err)
+		return
+	}
+	if !creditRoles[acr.Role] {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid role", nil)
+		return
+	}
+	acr.Character = strings.TrimSpace(acr.Character)
+	if acr.Role != "actor" && acr.Character != "" {
+		views.RespondWithError(w, http.StatusBadRequest, "Character is for actors only", nil)
+		return
+	}
+
+	_, err = ch.DB.GetProjectById(r.Context(), int64(project_id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+
+	_, err = ch.DB.GetPersonById(r.Context(), acr.PersonID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find person", err)
+		return
+	}
+
+	_, err = ch.DB.GetCredit(r.Context(), database.GetCreditParams{
+		ProjectID: int64(project_id),
+		PersonID:  acr.PersonID,
+		Role:      acr.Role,
+		Character: acr.Character,
+	})
+	if err == nil {
+		views.RespondWithError(w, http.StatusConflict, "Credit already exists", nil)
+		return
+	}
+
+	id, err := ch.DB.AddCredit(r.Context(), database.AddCreditParams{
+		ProjectID: int64(project_id),
+		PersonID:  acr.PersonID,
+		Role:      acr.Role,
+		Character: acr.Character,
+		Position:  acr.Position,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't add credit", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(int(id)))
+}
+
+// Delete godoc
+// @Tags Credits
+// @Summary      Remove Person from Cast or Crew of Project
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "credit id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Credit"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't delete credit"
+// @Router       /v1/projects/credits/{id} [delete]
+// @Security Bearer
+func (ch *CreditsHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	_, err = ch.DB.GetCreditById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find credit", err)
+		return
+	}
+
+	err = ch.DB.DeleteCredit(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete credit", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(id))
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+type FavouritesHandlers struct {
+	repo *repositories.ProjectsRepository
+}
+
+func NewFavouritesHandlers(repo *repositories.ProjectsRepository) *FavouritesHandlers {
+	return &FavouritesHandlers{
+		repo: repo,
+	}
+}
+
+// GetAll godoc
+// @Tags Favourites
+// @Summary      Get Favourite Projects of User profile
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Success      200  {array} views.Project "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't Get Favourites"
+// @Router       /v1/users/profile/favourites [get]
+// @Security Bearer
+func (fh *FavouritesHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
+	projects, err := fh.repo.GetFavouritesOfUser(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get favourites", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, projects)
+}
+
+// Add godoc
+// @Tags Favourites
+// @Summary      Add Project to Favourites of User profile
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      201  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't Add to Favourites"
+// @Router       /v1/users/profile/favourites/{id} [post]
+// @Security Bearer
+func (fh *FavouritesHandlers) Add(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	_, err = fh.repo.DB.GetProjectById(r.Context(), int64(project_id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+
+	err = fh.repo.DB.AddProject2Favourites(r.Context(), database.AddProject2FavouritesParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't add project to favourites", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(project_id))
+}
+
+// Delete godoc
+// @Tags Favourites
+// @Summary      Remove Project from Favourites of User profile
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't Remove from Favourites"
+// @Router       /v1/users/profile/favourites/{id} [delete]
+// @Security Bearer
+func (fh *FavouritesHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	err = fh.repo.DB.DeleteProjectFromFavourites(r.Context(), database.DeleteProjectFromFavouritesParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't remove project from favourites", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(project_id))
+}
@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -35,17 +34,6 @@ func NewGenresHandlers(db *database.Queries) *GenresHandlers {
 // @Router       /v1/genres [get]
 // @Security Bearer
 func (rh *GenresHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Genres >= 2 {
-			can_do = true
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	genres, err := rh.DB.GetGenres(r.Context())
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get genres", err)
@@ -71,18 +59,6 @@ func (rh *GenresHandlers) GetAll(w http.ResponseWriter, r *http.Request, user vi
 // @Router       /v1/genres [post]
 // @Security Bearer
 func (rh *GenresHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Genres == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	decoder := json.NewDecoder(r.Body)
 	cgr := views.CreateGenreRequest{}
 
@@ -119,18 +95,6 @@ func (rh *GenresHandlers) Create(w http.ResponseWriter, r *http.Request, user vi
 // @Router       /v1/genres/{id} [get]
 // @Security Bearer
 func (rh *GenresHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Genres >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -163,18 +127,6 @@ func (rh *GenresHandlers) Get(w http.ResponseWriter, r *http.Request, user views
 // @Router       /v1/genres/{id} [put]
 // @Security Bearer
 func (gh *GenresHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Genres == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -218,18 +170,6 @@ func (gh *GenresHandlers) Update(w http.ResponseWriter, r *http.Request, user vi
 // @Router       /v1/genres/{id} [delete]
 // @Security Bearer
 func (gh *GenresHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Genres >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
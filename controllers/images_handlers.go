@@ -1,112 +1,112 @@
 package controllers
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"image"
 	"io"
+	"log"
 	"mime"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/Bayan2019/go-ozinshe/imaging"
 	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
 	"github.com/Bayan2019/go-ozinshe/views"
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 )
 
+// maxImageSize of the uploaded images, 20 MB
+const maxImageSize = 20 << 20
+
 type ImagesHandlers struct {
-	DB  *database.Queries
-	Dir string
+	DB      *database.Queries
+	Storage storage.Storage
 }
 
-func NewImagesHandlers(db *database.Queries, dir string) *ImagesHandlers {
+func NewImagesHandlers(db *database.Queries, store storage.Storage) *ImagesHandlers {
 	return &ImagesHandlers{
-		DB:  db,
-		Dir: dir,
+		DB:      db,
+		Storage: store,
 	}
 }
 
 // Display godoc
 // @Tags Images
 // @Summary      Display Image
+// @Description  Streams the image, supports Range and conditional requests.
+// @Description  variant selects a resized copy, the images uploaded without variants are streamed in full size.
 // @Accept       json
-// @Produce      application/octet-stream
+// @Produce      image/jpeg
 // @Param Authorization header string true "Bearer AccessToken"
-// @Param id path int true "id"
+// @Param id path string true "file name"
+// @Param variant query string false "resized copy" Enums(thumbnail, card, backdrop)
+// @Param Range header string false "bytes=start-end"
 // @Success      200  "OK"
-// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Success      206  "Partial Content"
+// @Success      304  "Not Modified"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid variant"
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
-// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
-// @Failure   	 500  {object} views.ErrorResponse "No Permission" "Couldn't get User Middleware"
-// @Router       /v1/projects/images/files/show/{id} [get]
+// @Failure   	 404  {object} views.ErrorResponse "Not found File"
+// @Failure   	 416  "Range Not Satisfiable"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't open file"
+// @Router       /v1/projects/images/show/{id} [get]
 // @Security Bearer
 func (ih *ImagesHandlers) Display(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects >= 2 {
-			can_do = true
-			break
+	name := chi.URLParam(r, "id")
+
+	if variant := r.URL.Query().Get("variant"); variant != "" {
+		if _, ok := imaging.Lookup(variant); !ok {
+			views.RespondWithError(w, http.StatusBadRequest, "Invalid variant", nil)
+			return
+		}
+		// the images uploaded before the variants were introduced have none
+		object, err := ih.Storage.Open(r.Context(), imaging.Name(name, variant))
+		if err == nil {
+			object.Close()
+			name = imaging.Name(name, variant)
+		} else if !storage.IsNotExist(err) {
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't open file", err)
+			return
 		}
 	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-	// You can get the string value of the path parameter like in Go
-	// with the http.Request.PathValue method.
-	id := chi.URLParam(r, "id")
-	// enableCors(&w)
-	w.Header().Set("Content-Type", "image/jpeg")
-	// w.ResponseWriter.WriteHeader(http.StatusOK)
-	http.ServeFile(w, r, fmt.Sprintf("%s%s", ih.Dir, id))
+
+	serveMedia(w, r, ih.Storage, name, false)
 }
 
 // Get godoc
 // @Tags Images
-// @Summary      Get Image
+// @Summary      Download Image
+// @Description  Streams the image as an attachment, supports Range and conditional requests
 // @Accept       json
 // @Produce      application/octet-stream
 // @Param Authorization header string true "Bearer AccessToken"
-// @Param id path int true "id"
+// @Param id path string true "file name"
+// @Param Range header string false "bytes=start-end"
 // @Success      200  "OK"
-// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Success      206  "Partial Content"
+// @Success      304  "Not Modified"
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
-// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
-// @Failure   	 500  {object} views.ErrorResponse "can't read the image"
-// @Router       /v1/projects/images/files/{id} [get]
+// @Failure   	 404  {object} views.ErrorResponse "Not found File"
+// @Failure   	 416  "Range Not Satisfiable"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't open file"
+// @Router       /v1/projects/images/{id} [get]
 // @Security Bearer
 func (ih *ImagesHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-	id := chi.URLParam(r, "id")
-	byteFile, err := os.ReadFile(fmt.Sprintf("%s%s", ih.Dir, id))
-	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "can't read the image", err)
-		return
-	}
-	w.Header().Set("Content-Type", fmt.Sprintf("image/%s", mediaTypeToExt(id)))
-	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", id))
-	w.WriteHeader(http.StatusOK)
-	w.Write(byteFile)
+	serveMedia(w, r, ih.Storage, chi.URLParam(r, "id"), true)
 }
 
 // Create godoc
 // @Tags Images
 // @Summary      Create Image
+// @Description  Stores the JPEG or PNG image with its thumbnail, card and backdrop variants
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param Authorization header string true "Bearer AccessToken"
@@ -117,22 +117,11 @@ func (ih *ImagesHandlers) Get(w http.ResponseWriter, r *http.Request, user views
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
 // @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 413  {object} views.ErrorResponse "Image is too large"
 // @Failure   	 500  {object} views.ErrorResponse "can't create image"
 // @Router       /v1/projects/images [post]
 // @Security Bearer
 func (ih *ImagesHandlers) Upload(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	// Set a const maxMemory to 10MB.
 	const maxMemory = 10 << 20 // 10 MB
 	// Use (http.Request).ParseMultipartForm with the maxMemory const as an argument
@@ -158,19 +147,8 @@ func (ih *ImagesHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid file type", nil)
 		return
 	}
-	ext := mediaTypeToExt(mediaType)
-	fileName := fmt.Sprintf("%s%s", uuid.NewString(), ext)
-	fpath := fmt.Sprintf("%s%s", ih.Dir, fileName)
-	// Use os.Create to create the new file
-	dst, err := os.Create(fpath)
-	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Unable to create file on server", err)
-		return
-	}
-	defer dst.Close()
-
-	if _, err = io.Copy(dst, file); err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
+	if header.Size > maxImageSize {
+		views.RespondWithError(w, http.StatusRequestEntityTooLarge, "Image is too large", nil)
 		return
 	}
 
@@ -186,11 +164,11 @@ func (ih *ImagesHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 	// 	return
 	// }
 
-	err = ih.DB.AddImage2Movie(r.Context(), database.AddImage2MovieParams{
-		ID:        fileName,
-		ProjectID: int64(project_id),
-		Href:      href,
-	})
+	fileName, err := ih.saveImage(r.Context(), file, mediaType, int64(project_id), href)
+	if errors.Is(err, imaging.ErrInvalid) {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid image", err)
+		return
+	}
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
 		return
@@ -201,6 +179,26 @@ func (ih *ImagesHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 	})
 }
 
+// saveImage stores the image with its variants and adds it to the project
+func (ih *ImagesHandlers) saveImage(ctx context.Context, file io.Reader, mediaType string, project_id int64, href string) (string, error) {
+	fileName, size, err := storeImage(ctx, ih.Storage, file, mediaType)
+	if err != nil {
+		return "", err
+	}
+
+	err = ih.DB.AddImage2Movie(ctx, database.AddImage2MovieParams{
+		ID:        fileName,
+		ProjectID: project_id,
+		Href:      href,
+		Width:     int64(size.X),
+		Height:    int64(size.Y),
+	})
+	if err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
 // Delete godoc
 // @Tags Images
 // @Summary      Delete Image
@@ -217,22 +215,13 @@ func (ih *ImagesHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 // @Router       /v1/projects/images/{id} [delete]
 // @Security Bearer
 func (ih *ImagesHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
+	id := chi.URLParam(r, "id")
+	err := ih.Storage.Delete(r.Context(), id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error deleting file", err)
 		return
 	}
-
-	id := chi.URLParam(r, "id")
-	fpath := fmt.Sprintf("%s%s", ih.Dir, id)
-	// Use os.Create to create the new file
-	err := os.Remove(fpath)
+	err = imaging.RemoveVariants(r.Context(), ih.Storage, id)
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Error deleting file", err)
 		return
@@ -249,6 +238,58 @@ func (ih *ImagesHandlers) Delete(w http.ResponseWriter, r *http.Request, user vi
 	})
 }
 
+// storeImage decodes the JPEG or PNG image and stores it with its variants,
+// it returns the file name and the size of the image
+func storeImage(ctx context.Context, store storage.Storage, file io.Reader, mediaType string) (string, image.Point, error) {
+	data, img, err := readImage(file, mediaType)
+	if err != nil {
+		return "", image.Point{}, err
+	}
+
+	fileName := fmt.Sprintf("%s%s", uuid.NewString(), mediaTypeToExt(mediaType))
+	err = store.Put(ctx, fileName, bytes.NewReader(data), int64(len(data)), mediaType)
+	if err != nil {
+		return "", image.Point{}, err
+	}
+	err = imaging.SaveVariants(ctx, store, fileName, img, mediaType)
+	if err != nil {
+		removeImage(ctx, store, fileName)
+		return "", image.Point{}, err
+	}
+	return fileName, img.Bounds().Size(), nil
+}
+
+// readImage reads the image and checks that it is decoded
+// as the declared JPEG or PNG
+func readImage(file io.Reader, mediaType string) ([]byte, image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxImageSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, nil, fmt.Errorf("%w: larger than %d bytes", imaging.ErrInvalid, maxImageSize)
+	}
+	img, format, err := imaging.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	if "image/"+format != mediaType {
+		return nil, nil, fmt.Errorf("%w: %s image sent as %s", imaging.ErrInvalid, format, mediaType)
+	}
+	return data, img, nil
+}
+
+// removeImage removes the image with its variants, logging the failure
+func removeImage(ctx context.Context, store storage.Storage, name string) bool {
+	removed := removeMedia(ctx, store, name)
+	err := imaging.RemoveVariants(ctx, store, name)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	return removed
+}
+
 func mediaTypeToExt(mediaType string) string {
 	parts := strings.Split(mediaType, "/")
 	if len(parts) != 2 {
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+// mediaTypes of the uploaded files,
+// the rest are looked up by mime.TypeByExtension or sniffed
+var mediaTypes = map[string]string{
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".mp4":  "video/mp4",
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+	".vtt":  "text/vtt; charset=utf-8",
+}
+
+// serveMedia streams the object of the storage
+// with Range, If-Range, ETag and Last-Modified handling.
+// The object is sent as an attachment when attachment is true.
+func serveMedia(w http.ResponseWriter, r *http.Request, store storage.Storage, name string, attachment bool) {
+	object, err := store.Open(r.Context(), name)
+	if storage.IsNotExist(err) {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find file", err)
+		return
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't open file", err)
+		return
+	}
+	defer object.Close()
+
+	if mediaType := mediaTypeOf(name); mediaType != "" {
+		w.Header().Set("Content-Type", mediaType)
+	}
+	w.Header().Set("ETag", object.ETag())
+	if attachment {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(name)}))
+	}
+
+	// ServeContent answers conditional and range requests
+	http.ServeContent(w, r, name, object.ModTime(), object)
+}
+
+func mediaTypeOf(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if mediaType, ok := mediaTypes[ext]; ok {
+		return mediaType
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// removeMedia removes the object, logging the failure
+func removeMedia(ctx context.Context, store storage.Storage, name string) bool {
+	err := store.Delete(ctx, name)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	return true
+}
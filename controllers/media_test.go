@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+func TestServeMedia(t *testing.T) {
+	dir := t.TempDir() + "/"
+	content := []byte("0123456789")
+	if err := os.WriteFile(filepath.Join(dir, "video.mp4"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(dir, "video.mp4"), modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(name string, attachment bool, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		serveMedia(rec, req, storage.NewLocal(dir), name, attachment)
+		return rec
+	}
+
+	rec := serve("video.mp4", false, nil)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || rec.Body.String() != string(content) {
+		t.Fatalf("full: status %d, body %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "video/mp4" {
+		t.Errorf("Content-Type = %q, want video/mp4", got)
+	}
+	if etag == "" || rec.Header().Get("Last-Modified") != modified.Format(http.TimeFormat) {
+		t.Errorf("ETag = %q, Last-Modified = %q", etag, rec.Header().Get("Last-Modified"))
+	}
+	if rec.Header().Get("Accept-Ranges") != "bytes" {
+		t.Errorf("Accept-Ranges = %q", rec.Header().Get("Accept-Ranges"))
+	}
+	if rec.Header().Get("Content-Disposition") != "" {
+		t.Errorf("inline file has Content-Disposition %q", rec.Header().Get("Content-Disposition"))
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		headers  map[string]string
+		wantCode int
+		wantBody string
+	}{
+		{name: "Range", file: "video.mp4", headers: map[string]string{"Range": "bytes=2-5"}, wantCode: http.StatusPartialContent, wantBody: "2345"},
+		{name: "Suffix range", file: "video.mp4", headers: map[string]string{"Range": "bytes=-3"}, wantCode: http.StatusPartialContent, wantBody: "789"},
+		{name: "Unsatisfiable range", file: "video.mp4", headers: map[string]string{"Range": "bytes=20-30"}, wantCode: http.StatusRequestedRangeNotSatisfiable},
+		{name: "If-Range matches", file: "video.mp4", headers: map[string]string{"Range": "bytes=0-0", "If-Range": etag}, wantCode: http.StatusPartialContent, wantBody: "0"},
+		{name: "If-Range changed", file: "video.mp4", headers: map[string]string{"Range": "bytes=0-0", "If-Range": `"old"`}, wantCode: http.StatusOK, wantBody: string(content)},
+		{name: "If-None-Match", file: "video.mp4", headers: map[string]string{"If-None-Match": etag}, wantCode: http.StatusNotModified},
+		{name: "If-Modified-Since", file: "video.mp4", headers: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, wantCode: http.StatusNotModified},
+		{name: "If-Match changed", file: "video.mp4", headers: map[string]string{"If-Match": `"old"`}, wantCode: http.StatusPreconditionFailed},
+		{name: "Missing file", file: "missing.mp4", wantCode: http.StatusNotFound},
+		{name: "Outside of directory", file: "../video.mp4", wantCode: http.StatusNotFound},
+		{name: "Hidden file", file: ".env", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.file, false, tt.headers)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+
+	rec = serve("video.mp4", true, nil)
+	if got := rec.Header().Get("Content-Disposition"); got != "attachment; filename=video.mp4" {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+}
+
+func TestMediaTypeOf(t *testing.T) {
+	for name, want := range map[string]string{
+		"a.png":  "image/png",
+		"a.JPG":  "image/jpeg",
+		"a.jpeg": "image/jpeg",
+		"a.mp4":  "video/mp4",
+		"a":      "",
+	} {
+		if got := mediaTypeOf(name); got != want {
+			t.Errorf("mediaTypeOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
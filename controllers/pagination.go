@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// totalCountHeader holds the number of items of all the pages
+const totalCountHeader = "X-Total-Count"
+
+// getPagination reads limit and offset query parameters
+func getPagination(r *http.Request) (limit, offset int64, err error) {
+	limit = defaultLimit
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.ParseInt(l, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		if limit < 1 || limit > maxLimit {
+			return 0, 0, errors.New("limit must be between 1 and 100")
+		}
+	}
+	if o := r.URL.Query().Get("offset"); o != "" {
+		offset, err = strconv.ParseInt(o, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		if offset < 0 {
+			return 0, 0, errors.New("offset can't be negative")
+		}
+	}
+	return limit, offset, nil
+}
+
+func setTotalCount(w http.ResponseWriter, total int64) {
+	w.Header().Set(totalCountHeader, strconv.FormatInt(total, 10))
+}
+
+var projectsSorts = map[string]bool{
+	"created_at":   true,
+	"release_year": true,
+	"title":        true,
+	"rating":       true,
+}
+
+// getProjectsFilter reads pagination, sorting and filters of the projects list
+func getProjectsFilter(r *http.Request) (views.ProjectsFilter, error) {
+	filter := views.ProjectsFilter{SortBy: "created_at"}
+	var err error
+
+	filter.Limit, filter.Offset, err = getPagination(r)
+	if err != nil {
+		return filter, err
+	}
+
+	query := r.URL.Query()
+	if sort := query.Get("sort"); sort != "" {
+		if !projectsSorts[sort] {
+			return filter, fmt.Errorf("can't sort by %q", sort)
+		}
+		filter.SortBy = sort
+	}
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return filter, errors.New("order must be asc or desc")
+	}
+
+	for name, param := range map[string]*sql.NullInt64{
+		"type_id":         &filter.TypeID,
+		"genre_id":        &filter.GenreID,
+		"age_category_id": &filter.AgeCategoryID,
+		"year_from":       &filter.YearFrom,
+		"year_to":         &filter.YearTo,
+	} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		param.Int64, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("wrong %s: %w", name, err)
+		}
+		param.Valid = true
+	}
+
+	return filter, nil
+}
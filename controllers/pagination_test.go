@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int64
+		wantOffset int64
+		wantErr    bool
+	}{
+		{name: "Defaults", query: "", wantLimit: defaultLimit, wantOffset: 0},
+		{name: "Limit and offset", query: "?limit=5&offset=10", wantLimit: 5, wantOffset: 10},
+		{name: "Max limit", query: "?limit=100", wantLimit: maxLimit, wantOffset: 0},
+		{name: "Limit too big", query: "?limit=101", wantErr: true},
+		{name: "Zero limit", query: "?limit=0", wantErr: true},
+		{name: "Negative offset", query: "?offset=-1", wantErr: true},
+		{name: "Not a number", query: "?limit=ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset, err := getPagination(httptest.NewRequest(http.MethodGet, "/"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getPagination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("getPagination() = %d, %d, want %d, %d", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestGetProjectsFilter(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?sort=rating&order=desc&genre_id=3&year_to=2020", nil)
+	filter, err := getProjectsFilter(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.SortBy != "rating" || !filter.Descending {
+		t.Errorf("sort = %s, descending = %v", filter.SortBy, filter.Descending)
+	}
+	if !filter.GenreID.Valid || filter.GenreID.Int64 != 3 || !filter.YearTo.Valid || filter.YearTo.Int64 != 2020 {
+		t.Errorf("filters = %+v", filter)
+	}
+	if filter.TypeID.Valid || filter.AgeCategoryID.Valid || filter.YearFrom.Valid {
+		t.Errorf("unset filters are valid: %+v", filter)
+	}
+	if filter.Limit != defaultLimit || filter.Offset != 0 {
+		t.Errorf("limit = %d, offset = %d", filter.Limit, filter.Offset)
+	}
+}
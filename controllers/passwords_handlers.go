@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/mail"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/throttle"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+// passwordResetTTL is the lifetime of a password reset token
+const passwordResetTTL = time.Hour
+
+// emailsPolicy limits the reset and verification emails asked by an address or for an email,
+// the requests of the unknown emails count alike
+var emailsPolicy = throttle.Policy{
+	Free:    5,
+	Delay:   time.Minute,
+	Lockout: time.Hour,
+	Window:  time.Hour,
+}
+
+type PasswordsHandlers struct {
+	DB     *database.Queries
+	Mailer mail.Mailer
+	// ResetURL is the page of the frontend receiving the token,
+	// the email has only the token without it
+	ResetURL string
+	// Emails and Addresses limit the reset requests
+	Emails    *throttle.Limiter
+	Addresses *throttle.Limiter
+	now       func() time.Time
+}
+
+func NewPasswordsHandlers(db *database.Queries, mailer mail.Mailer, resetURL string, attempts throttle.Store) *PasswordsHandlers {
+	return &PasswordsHandlers{
+		DB:        db,
+		Mailer:    mailer,
+		ResetURL:  resetURL,
+		Emails:    throttle.NewLimiter(attempts, "reset-email:", emailsPolicy),
+		Addresses: throttle.NewLimiter(attempts, "reset-ip:", emailsPolicy),
+		now:       time.Now,
+	}
+}
+
+// Change godoc
+// @Tags Users
+// @Summary      Change password of Profile
+// @Description  Requires the current password, the other sessions are signed out
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param request body views.ChangePasswordRequest true "Passwords"
+// @Success      204
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "Incorrect password"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't change password"
+// @Router       /v1/users/profile/password [put]
+// @Security Bearer
+func (ph *PasswordsHandlers) Change(w http.ResponseWriter, r *http.Request, user views.User) {
+	decoder := json.NewDecoder(r.Body)
+	cpr := views.ChangePasswordRequest{}
+	err := decoder.Decode(&cpr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of ChangePasswordRequest", err)
+		return
+	}
+	if cpr.NewPassword == "" {
+		views.RespondWithError(w, http.StatusBadRequest, "New password is required", nil)
+		return
+	}
+
+	dUser, err := ph.DB.GetUserById(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't get user", err)
+		return
+	}
+	err = checkPasswordHash(cpr.CurrentPassword, dUser.PasswordHash)
+	if err != nil {
+		views.RespondWithError(w, http.StatusUnauthorized, "Incorrect password", err)
+		return
+	}
+
+	err = ph.setPassword(r, user.Id, cpr.NewPassword)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't change password", err)
+		return
+	}
+
+	// the current session stays signed in
+	err = ph.DB.RevokeOtherSessionsOfUser(r.Context(), database.RevokeOtherSessionsOfUserParams{
+		UserID:  user.Id,
+		Current: user.Session,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Forgot godoc
+// @Tags Auth
+// @Summary      Forgot password
+// @Description  Emails a single-use token to reset the password, valid for an hour.
+// @Description  The answer is the same for unknown emails, the email is sent after it.
+// @Description  An address or an email gets 5 requests an hour, then the next one waits.
+// @Accept       json
+// @Produce      json
+// @Param request body views.ForgotPasswordRequest true "Email"
+// @Success      202
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 429  {object} views.ErrorResponse "Too many requests, see Retry-After"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't check requests"
+// @Router       /v1/auth/forgot-password [post]
+func (ph *PasswordsHandlers) Forgot(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	fpr := views.ForgotPasswordRequest{}
+	err := decoder.Decode(&fpr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of ForgotPasswordRequest", err)
+		return
+	}
+
+	email := normalizeEmail(fpr.Email)
+	ip := clientIP(r)
+	wait, err := throttleAttempt(r.Context(), ph.Addresses, ip, ph.Emails, email)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save reset request", err)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		views.RespondWithError(w, http.StatusTooManyRequests, "Too many reset requests, try again later", nil)
+		return
+	}
+	// the user is looked up and emailed after the answer,
+	// so that its time doesn't reveal the registered emails
+	go ph.sendResetToken(context.WithoutCancel(r.Context()), email)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// sendResetToken replaces the reset token of the user with the email and sends it,
+// the unknown emails are ignored
+func (ph *PasswordsHandlers) sendResetToken(ctx context.Context, email string) {
+	user, err := ph.DB.GetUserBySignInEmail(ctx, email)
+	if err != nil {
+		return
+	}
+
+	token, err := makeRefreshToken()
+	if err != nil {
+		log.Printf("Couldn't create password reset token of user %d: %s", user.ID, err)
+		return
+	}
+
+	// only the latest token is valid
+	err = ph.DB.DeletePasswordResetTokensOfUser(ctx, user.ID)
+	if err != nil {
+		log.Printf("Couldn't delete previous password reset tokens of user %d: %s", user.ID, err)
+		return
+	}
+	err = ph.DB.CreatePasswordResetToken(ctx, database.CreatePasswordResetTokenParams{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: ph.now().UTC().Add(passwordResetTTL).Format(time.RFC3339),
+	})
+	if err != nil {
+		log.Printf("Couldn't save password reset token of user %d: %s", user.ID, err)
+		return
+	}
+
+	err = ph.Mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Ozinshe password reset",
+		Body:    ph.resetBody(user.Name, token),
+	})
+	if err != nil {
+		log.Printf("Couldn't send password reset email to user %d: %s", user.ID, err)
+	}
+}
+
+// Reset godoc
+// @Tags Auth
+// @Summary      Reset password
+// @Description  Sets the password with the token from the email, all the sessions are signed out
+// @Accept       json
+// @Produce      json
+// @Param request body views.ResetPasswordRequest true "Token and password"
+// @Success      204
+// @Failure   	 400  {object} views.ErrorResponse "Invalid or expired token"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't change password"
+// @Router       /v1/auth/reset-password [post]
+func (ph *PasswordsHandlers) Reset(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	rpr := views.ResetPasswordRequest{}
+	err := decoder.Decode(&rpr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of ResetPasswordRequest", err)
+		return
+	}
+	if rpr.NewPassword == "" {
+		views.RespondWithError(w, http.StatusBadRequest, "New password is required", nil)
+		return
+	}
+
+	// the token is marked used before the password is changed,
+	// so it works once even for concurrent requests
+	userID, err := ph.DB.UsePasswordResetToken(r.Context(), database.UsePasswordResetTokenParams{
+		TokenHash: hashToken(rpr.Token),
+		Now:       ph.now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid or expired token", err)
+		return
+	}
+
+	err = ph.setPassword(r, userID, rpr.NewPassword)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't change password", err)
+		return
+	}
+
+	err = ph.DB.RevokeSessionsOfUser(r.Context(), userID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ph *PasswordsHandlers) setPassword(r *http.Request, userID int64, password string) error {
+	hashedPassword, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return ph.DB.ChangePassword(r.Context(), database.ChangePasswordParams{
+		PasswordHash: hashedPassword,
+		ID:           userID,
+	})
+}
+
+func (ph *PasswordsHandlers) resetBody(name, token string) string {
+	link := token
+	if ph.ResetURL != "" {
+		link = ph.ResetURL + "?token=" + url.QueryEscape(token)
+	}
+	return fmt.Sprintf(`Hello, %s!
+
+Somebody asked to reset the password of your Ozinshe account.
+Use this to set a new password within an hour:
+
+%s
+
+If it wasn't you, ignore this email, your password stays the same.
+`, name, link)
+}
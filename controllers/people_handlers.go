@@ -0,0 +1,349 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Bayan2019/go-ozinshe/imaging"
+	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+type PeopleHandlers struct {
+	DB      *database.Queries
+	repo    *repositories.PeopleRepository
+	Storage storage.Storage
+}
+
+func NewPeopleHandlers(db *database.Queries, repo *repositories.PeopleRepository, store storage.Storage) *PeopleHandlers {
+	return &PeopleHandlers{
+		DB:      db,
+		repo:    repo,
+		Storage: store,
+	}
+}
+
+// GetAll godoc
+// @Tags People
+// @Summary      Get People
+// @Description  Total number of people is in X-Total-Count header
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param search query string false "part of the name, case sensitive for non-latin letters"
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "number of people to skip"
+// @Success      200  {array} database.Person "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get people"
+// @Router       /v1/people [get]
+// @Security Bearer
+func (ph *PeopleHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
+	limit, offset, err := getPagination(r)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid pagination", err)
+		return
+	}
+
+	search := "%" + strings.TrimSpace(r.URL.Query().Get("search")) + "%"
+
+	total, err := ph.DB.CountPeople(r.Context(), search)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't count people", err)
+		return
+	}
+
+	people, err := ph.DB.GetPeople(r.Context(), database.GetPeopleParams{
+		Search: search,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get people", err)
+		return
+	}
+	if people == nil {
+		people = []database.Person{}
+	}
+
+	setTotalCount(w, total)
+	views.RespondWithJSON(w, http.StatusOK, people)
+}
+
+// Get godoc
+// @Tags People
+// @Summary      Get Person with Filmography
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "person id"
+// @Success      200  {object} views.Person "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Person"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get person"
+// @Router       /v1/people/{id} [get]
+// @Security Bearer
+func (ph *PeopleHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	person, err := ph.repo.GetById(r.Context(), int64(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find person", err)
+		return
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get person", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, person)
+}
+
+// Create godoc
+// @Tags People
+// @Summary      Create Person
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param request body views.CreatePersonRequest true "Person data"
+// @Success      201  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't create person"
+// @Router       /v1/people [post]
+// @Security Bearer
+func (ph *PeopleHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
+	decoder := json.NewDecoder(r.Body)
+	cpr := views.CreatePersonRequest{}
+
+	err := decoder.Decode(&cpr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of CreatePersonRequest", err)
+		return
+	}
+	cpr.Name = strings.TrimSpace(cpr.Name)
+	if cpr.Name == "" {
+		views.RespondWithError(w, http.StatusBadRequest, "Name is required", nil)
+		return
+	}
+
+	id, err := ph.DB.CreatePerson(r.Context(), database.CreatePersonParams{
+		Name: cpr.Name,
+		Bio:  cpr.Bio,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create person", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(int(id)))
+}
+
+// Update godoc
+// @Tags People
+// @Summary      Update Person
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "person id"
+// @Param request body views.UpdatePersonRequest true "Person data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Person"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't update person"
+// @Router       /v1/people/{id} [put]
+// @Security Bearer
+func (ph *PeopleHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	upr := views.UpdatePersonRequest{}
+
+	err = decoder.Decode(&upr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of UpdatePersonRequest", err)
+		return
+	}
+	upr.Name = strings.TrimSpace(upr.Name)
+	if upr.Name == "" {
+		views.RespondWithError(w, http.StatusBadRequest, "Name is required", nil)
+		return
+	}
+
+	_, err = ph.DB.GetPersonById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find person", err)
+		return
+	}
+
+	err = ph.DB.UpdatePerson(r.Context(), database.UpdatePersonParams{
+		ID:   int64(id),
+		Name: upr.Name,
+		Bio:  upr.Bio,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't update person", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Delete godoc
+// @Tags People
+// @Summary      Delete Person with the Credits
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "person id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Person"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't delete person"
+// @Router       /v1/people/{id} [delete]
+// @Security Bearer
+func (ph *PeopleHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	person, err := ph.DB.GetPersonById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find person", err)
+		return
+	}
+
+	err = ph.repo.Delete(r.Context(), person.ID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete person", err)
+		return
+	}
+
+	ph.removePhoto(r.Context(), person.Photo)
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(id))
+}
+
+// UploadPhoto godoc
+// @Tags People
+// @Summary      Set Photo of Person
+// @Description  The photo is shown by /v1/projects/images/show/{id}
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "person id"
+// @Param image formData file true "image"
+// @Success      201  {object} views.ResponseIdStr  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Person"
+// @Failure   	 413  {object} views.ErrorResponse "Image is too large"
+// @Failure   	 500  {object} views.ErrorResponse "Error saving file"
+// @Router       /v1/people/{id}/photo [post]
+// @Security Bearer
+func (ph *PeopleHandlers) UploadPhoto(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	person, err := ph.DB.GetPersonById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find person", err)
+		return
+	}
+
+	const maxMemory = 10 << 20 // 10 MB
+	r.ParseMultipartForm(maxMemory)
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Unable to parse form file", err)
+		return
+	}
+	defer file.Close()
+
+	mediaType, _, err := mime.ParseMediaType(header.Header.Get("Content-Type"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid Content-Type", err)
+		return
+	}
+	if mediaType != "image/jpeg" && mediaType != "image/png" {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid file type", nil)
+		return
+	}
+	if header.Size > maxImageSize {
+		views.RespondWithError(w, http.StatusRequestEntityTooLarge, "Image is too large", nil)
+		return
+	}
+	data, _, err := readImage(file, mediaType)
+	if errors.Is(err, imaging.ErrInvalid) {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid image", err)
+		return
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
+		return
+	}
+	fileName := fmt.Sprintf("%s%s", uuid.NewString(), mediaTypeToExt(mediaType))
+
+	err = ph.Storage.Put(r.Context(), fileName, bytes.NewReader(data), int64(len(data)), mediaType)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
+		return
+	}
+
+	err = ph.DB.SetPersonPhoto(r.Context(), database.SetPersonPhotoParams{
+		ID:    person.ID,
+		Photo: fileName,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
+		return
+	}
+
+	ph.removePhoto(r.Context(), person.Photo)
+
+	views.RespondWithJSON(w, http.StatusCreated, views.ResponseIdStr{
+		ID: fileName,
+	})
+}
+
+// removePhoto removes the previous photo, if any
+func (ph *PeopleHandlers) removePhoto(ctx context.Context, name string) {
+	if name != "" {
+		removeMedia(ctx, ph.Storage, name)
+	}
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+// Resource is the name of a column of the roles table
+// holding the permission level of the role on that resource.
+type Resource string
+
+const (
+	ResourceProjects      Resource = "projects"
+	ResourceGenres        Resource = "genres"
+	ResourceAgeCategories Resource = "age_categories"
+	ResourceTypes         Resource = "types"
+	ResourceUsers         Resource = "users"
+	ResourceRoles         Resource = "roles"
+)
+
+// PermissionLevel is the id of a row of the permissions table
+type PermissionLevel int64
+
+const (
+	// PermissionForbidden - 'tıyım salınğan'
+	PermissionForbidden PermissionLevel = 1
+	// PermissionRead - 'tek oqw'
+	PermissionRead PermissionLevel = 2
+	// PermissionWrite - 'redakcïyalaw'
+	PermissionWrite PermissionLevel = 3
+)
+
+func roleLevel(role database.Role, resource Resource) PermissionLevel {
+	switch resource {
+	case ResourceProjects:
+		return PermissionLevel(role.Projects)
+	case ResourceGenres:
+		return PermissionLevel(role.Genres)
+	case ResourceAgeCategories:
+		return PermissionLevel(role.AgeCategories)
+	case ResourceTypes:
+		return PermissionLevel(role.Types)
+	case ResourceUsers:
+		return PermissionLevel(role.Users)
+	case ResourceRoles:
+		return PermissionLevel(role.Roles)
+	}
+	return PermissionForbidden
+}
+
+// HasPermission reports whether at least one of the roles
+// has the required level (or higher) on the resource
+func HasPermission(roles []database.Role, resource Resource, level PermissionLevel) bool {
+	for _, role := range roles {
+		if roleLevel(role, resource) >= level {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission wraps the handler so that it is called
+// only for users having the level on the resource
+func RequirePermission(resource Resource, level PermissionLevel, handler authedHandler) authedHandler {
+	return func(w http.ResponseWriter, r *http.Request, user views.User) {
+		if !HasPermission(user.Roles, resource, level) {
+			views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
+			return
+		}
+		handler(w, r, user)
+	}
+}
+
+// MiddlewarePermission is MiddlewareAuth followed by RequirePermission,
+// so the required permission is declared where the route is registered
+func (ah *AuthHandlers) MiddlewarePermission(resource Resource, level PermissionLevel, handler authedHandler) http.HandlerFunc {
+	return ah.MiddlewareAuth(RequirePermission(resource, level, handler))
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+func TestHasPermission(t *testing.T) {
+	admin := database.Role{Projects: 3, Genres: 3, AgeCategories: 3, Types: 3, Users: 3, Roles: 3}
+	user := database.Role{Projects: 2, Genres: 2, AgeCategories: 2, Types: 2, Users: 1, Roles: 1}
+	editor := database.Role{Projects: 3, Genres: 1, AgeCategories: 1, Types: 1, Users: 1, Roles: 1}
+
+	tests := []struct {
+		name     string
+		roles    []database.Role
+		resource Resource
+		level    PermissionLevel
+		want     bool
+	}{
+		{
+			name:     "No roles",
+			roles:    nil,
+			resource: ResourceProjects,
+			level:    PermissionRead,
+			want:     false,
+		},
+		{
+			name:     "Admin writes roles",
+			roles:    []database.Role{admin},
+			resource: ResourceRoles,
+			level:    PermissionWrite,
+			want:     true,
+		},
+		{
+			name:     "User reads genres",
+			roles:    []database.Role{user},
+			resource: ResourceGenres,
+			level:    PermissionRead,
+			want:     true,
+		},
+		{
+			name:     "User can't write genres",
+			roles:    []database.Role{user},
+			resource: ResourceGenres,
+			level:    PermissionWrite,
+			want:     false,
+		},
+		{
+			name:     "User can't read users",
+			roles:    []database.Role{user},
+			resource: ResourceUsers,
+			level:    PermissionRead,
+			want:     false,
+		},
+		{
+			name:     "Write level includes read",
+			roles:    []database.Role{editor},
+			resource: ResourceProjects,
+			level:    PermissionRead,
+			want:     true,
+		},
+		{
+			name:     "Any of the roles is enough",
+			roles:    []database.Role{user, editor},
+			resource: ResourceProjects,
+			level:    PermissionWrite,
+			want:     true,
+		},
+		{
+			name:     "Unknown resource",
+			roles:    []database.Role{admin},
+			resource: Resource("unknown"),
+			level:    PermissionRead,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasPermission(tt.roles, tt.resource, tt.level); got != tt.want {
+				t.Errorf("HasPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		roles      []database.Role
+		wantCalled bool
+		wantStatus int
+	}{
+		{
+			name:       "Allowed",
+			roles:      []database.Role{{Projects: 3}},
+			wantCalled: true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Forbidden",
+			roles:      []database.Role{{Projects: 2}},
+			wantCalled: false,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := RequirePermission(ResourceProjects, PermissionWrite, func(w http.ResponseWriter, r *http.Request, user views.User) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/", nil), views.User{Roles: tt.roles})
+
+			if called != tt.wantCalled {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCalled)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
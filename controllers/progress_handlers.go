@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+type ProgressHandlers struct {
+	repo *repositories.ProgressRepository
+}
+
+func NewProgressHandlers(repo *repositories.ProgressRepository) *ProgressHandlers {
+	return &ProgressHandlers{
+		repo: repo,
+	}
+}
+
+// Save godoc
+// @Tags Progress
+// @Summary      Save watch progress of Video
+// @Description  Called periodically by the player
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "video id"
+// @Param request body views.SaveProgressRequest true "Progress data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Video"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't save progress"
+// @Router       /v1/users/profile/progress/{id} [put]
+// @Security Bearer
+func (ph *ProgressHandlers) Save(w http.ResponseWriter, r *http.Request, user views.User) {
+	id := chi.URLParam(r, "id")
+
+	decoder := json.NewDecoder(r.Body)
+	spr := views.SaveProgressRequest{}
+
+	err := decoder.Decode(&spr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of SaveProgressRequest", err)
+		return
+	}
+	if spr.PositionInSecs < 0 || spr.DurationInSecs < 0 {
+		views.RespondWithError(w, http.StatusBadRequest, "Position and duration can't be negative", nil)
+		return
+	}
+
+	_, err = ph.repo.DB.GetVideoById(r.Context(), id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find video", err)
+		return
+	}
+
+	err = ph.repo.Save(r.Context(), user.Id, id, spr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save progress", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Get godoc
+// @Tags Progress
+// @Summary      Get watch progress of Video
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "video id"
+// @Success      200  {object} views.WatchProgress "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get progress"
+// @Router       /v1/users/profile/progress/{id} [get]
+// @Security Bearer
+func (ph *ProgressHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
+	id := chi.URLParam(r, "id")
+
+	progress, err := ph.repo.DB.GetWatchProgress(r.Context(), database.GetWatchProgressParams{
+		UserID:  user.Id,
+		VideoID: id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithJSON(w, http.StatusOK, views.WatchProgress{VideoID: id})
+		return
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get progress", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.WatchProgress{
+		VideoID:        progress.VideoID,
+		UpdatedAt:      progress.UpdatedAt,
+		PositionInSecs: progress.PositionInSecs,
+		DurationInSecs: progress.DurationInSecs,
+		Completed:      progress.Completed,
+	})
+}
+
+// ContinueWatching godoc
+// @Tags Progress
+// @Summary      Continue watching
+// @Description  Next not watched video of every started project, the most recently watched first
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Success      200  {array} views.ContinueWatching "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get continue watching"
+// @Router       /v1/users/profile/continue-watching [get]
+// @Security Bearer
+func (ph *ProgressHandlers) ContinueWatching(w http.ResponseWriter, r *http.Request, user views.User) {
+	continueWatching, err := ph.repo.ContinueWatching(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get continue watching", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, continueWatching)
+}
@@ -4,39 +4,51 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
+	"log"
 	"mime"
 	"net/http"
-	"os"
 	"strconv"
 
+	"github.com/Bayan2019/go-ozinshe/hls"
+	"github.com/Bayan2019/go-ozinshe/imaging"
 	"github.com/Bayan2019/go-ozinshe/repositories"
 	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/subtitles"
 	"github.com/Bayan2019/go-ozinshe/views"
 	"github.com/go-chi/chi"
-	"github.com/google/uuid"
 )
 
 type ProjectsHandlers struct {
-	repo *repositories.ProjectsRepository
-	Dir  string
+	repo    *repositories.ProjectsRepository
+	Storage storage.Storage
 }
 
-func NewProjecsHandlers(repo *repositories.ProjectsRepository, dir string) *ProjectsHandlers {
+func NewProjecsHandlers(repo *repositories.ProjectsRepository, store storage.Storage) *ProjectsHandlers {
 	return &ProjectsHandlers{
-		repo: repo,
-		Dir:  dir,
+		repo:    repo,
+		Storage: store,
 	}
 }
 
 // GetAll godoc
 // @Tags Projects
 // @Summary      Get Projects List
+// @Description  The total number of matching projects is in the X-Total-Count header.
 // @Accept       json
 // @Produce      json
 // @Param Authorization header string true "Bearer AccessToken"
+// @Param limit query int false "Limit (1-100, 20 by default)"
+// @Param offset query int false "Offset"
+// @Param sort query string false "Sort by" Enums(created_at, release_year, title, rating)
+// @Param order query string false "Order" Enums(asc, desc)
+// @Param type_id query int false "Type Id"
+// @Param genre_id query int false "Genre Id"
+// @Param age_category_id query int false "Age Category Id"
+// @Param year_from query int false "Released since year"
+// @Param year_to query int false "Released till year"
 // @Success      200  {array} views.Project "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
 // @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
@@ -44,38 +56,39 @@ func NewProjecsHandlers(repo *repositories.ProjectsRepository, dir string) *Proj
 // @Router       /v1/projects [get]
 // @Security Bearer
 func (ph *ProjectsHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
+	filter, err := getProjectsFilter(r)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid query", err)
 		return
 	}
 
-	// idsArray := r.URL.Query()["genre_id"]
-	// fmt.Println(idsArray)
-
-	projects, err := ph.repo.GetAll(r.Context())
+	projects, total, err := ph.repo.GetAll(r.Context(), filter)
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get projects", err)
+		return
 	}
 
+	setTotalCount(w, total)
 	views.RespondWithJSON(w, http.StatusOK, projects)
 }
 
 // GetAllSearchTerm godoc
 // @Tags Projects
-// @Summary      Get Projects List
+// @Summary      Search Projects
+// @Description  Finds projects by the words of the search term in title, description and keywords, ordered by relevance.
+// @Description  Words match as prefixes, matched words are wrapped in <mark></mark> in title_highlight and description_snippet,
+// @Description  the rest of their text is HTML-escaped.
+// @Description  Without search term projects of the genres are listed.
+// @Description  The total number of found projects is in the X-Total-Count header.
 // @Accept       json
 // @Produce      json
 // @Param Authorization header string true "Bearer AccessToken"
 // @Param searchTerm query string false "Search Term"
 // @Param genre_id query []string false "Genre Ids" collectionFormat(multi)
-// @Success      200  {array} views.Project "OK"
+// @Param limit query int false "Limit (1-100, 20 by default)"
+// @Param offset query int false "Offset"
+// @Success      200  {array} views.ProjectSearchResult "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
 // @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
@@ -83,25 +96,12 @@ func (ph *ProjectsHandlers) GetAll(w http.ResponseWriter, r *http.Request, user
 // @Router       /v1/projects/search [get]
 // @Security Bearer
 func (ph *ProjectsHandlers) GetAllSearch(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
-	// idsArray := r.URL.Query()["genre_id"]
-	// fmt.Println(idsArray)
 	searchTerm := r.URL.Query().Get("searchTerm")
 	idsArray := r.URL.Query()["genre_id"]
 
 	if searchTerm == "" && len(idsArray) == 0 {
-		views.RespondWithJSON(w, http.StatusOK, []views.Project{})
+		setTotalCount(w, 0)
+		views.RespondWithJSON(w, http.StatusOK, []views.ProjectSearchResult{})
 		return
 	}
 
@@ -115,58 +115,35 @@ func (ph *ProjectsHandlers) GetAllSearch(w http.ResponseWriter, r *http.Request,
 		ids = append(ids, int64(id))
 	}
 
-	if searchTerm == "" && len(ids) != 0 {
-		dProjects, err := ph.repo.DB.GetProjectsOfGenrers(r.Context(), ids)
-		if err != nil {
-			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get projects of genres", err)
-			return
-		}
-		projects, err := ph.repo.DatabaseProjects2viewsProjects(r.Context(), dProjects)
-		if err != nil {
-			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't convert database projects to views projects", err)
-			return
-		}
-		views.RespondWithJSON(w, http.StatusOK, projects)
+	limit, offset, err := getPagination(r)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid pagination", err)
 		return
 	}
 
-	if searchTerm != "" && len(idsArray) == 0 {
-		// err = ph.repo.DB.
-		// searchTerm = strings.ToLower(searchTerm)
-		searchTerm = "%" + searchTerm + "%"
-		dProjects, err := ph.repo.DB.GetProjectsSearch(r.Context(), searchTerm)
+	if searchTerm == "" {
+		projects, total, err := ph.repo.GetAllOfGenres(r.Context(), ids, limit, offset)
 		if err != nil {
-			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get projects of search term", err)
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get projects of genres", err)
 			return
 		}
-		projects, err := ph.repo.DatabaseProjects2viewsProjects(r.Context(), dProjects)
-		if err != nil {
-			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't convert database projects to views projects", err)
-			return
+		results := []views.ProjectSearchResult{}
+		for _, project := range projects {
+			results = append(results, views.ProjectSearchResult{Project: project})
 		}
-		views.RespondWithJSON(w, http.StatusOK, projects)
+		setTotalCount(w, total)
+		views.RespondWithJSON(w, http.StatusOK, results)
 		return
 	}
 
-	// var projects []views.RProject
-	searchTerm = "%" + searchTerm + "%"
-
-	dProjects, err := ph.repo.DB.GetProjectsOfGenresAndSearch(r.Context(), database.GetProjectsOfGenresAndSearchParams{
-		Ids:    ids,
-		Search: searchTerm,
-	})
-	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get projects of search term and genres", err)
-		return
-	}
-
-	projects, err := ph.repo.DatabaseProjects2viewsProjects(r.Context(), dProjects)
+	results, total, err := ph.repo.Search(r.Context(), searchTerm, ids, limit, offset)
 	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't convert database projects to views projects", err)
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get projects of search term", err)
 		return
 	}
 
-	views.RespondWithJSON(w, http.StatusOK, projects)
+	setTotalCount(w, total)
+	views.RespondWithJSON(w, http.StatusOK, results)
 }
 
 // GetProject godoc
@@ -185,18 +162,6 @@ func (ph *ProjectsHandlers) GetAllSearch(w http.ResponseWriter, r *http.Request,
 // @Router       /v1/projects/{id} [get]
 // @Security Bearer
 func (ph *ProjectsHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -228,18 +193,6 @@ func (ph *ProjectsHandlers) Get(w http.ResponseWriter, r *http.Request, user vie
 // @Router       /v1/projects [post]
 // @Security Bearer
 func (ph *ProjectsHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	decoder := json.NewDecoder(r.Body)
 	cpr := views.CreateProjectRequest{}
 
@@ -277,18 +230,6 @@ func (ph *ProjectsHandlers) Create(w http.ResponseWriter, r *http.Request, user
 // @Router       /v1/projects/{id} [put]
 // @Security Bearer
 func (ph *ProjectsHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -313,6 +254,71 @@ func (ph *ProjectsHandlers) Update(w http.ResponseWriter, r *http.Request, user
 	w.WriteHeader(http.StatusOK)
 }
 
+// Delete godoc
+// @Tags Projects
+// @Summary      Delete Project
+// @Description  Deletes the project with its images, videos, ratings and the media files
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "id"
+// @Success      200  {object} views.DeletedProject "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't Delete Project"
+// @Router       /v1/projects/{id} [delete]
+// @Security Bearer
+func (ph *ProjectsHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	images, videos, err := ph.repo.Delete(r.Context(), int64(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't Delete Project", err)
+		return
+	}
+
+	deleted := views.DeletedProject{
+		ID:          int64(id),
+		Images:      []string{},
+		Videos:      []string{},
+		FailedFiles: []string{},
+	}
+	// the rows are already gone, so a file that can't be removed
+	// is reported instead of failing the request
+	for _, image := range images {
+		deleted.Images = append(deleted.Images, image.ID)
+		if !removeImage(r.Context(), ph.Storage, image.ID) {
+			deleted.FailedFiles = append(deleted.FailedFiles, image.ID)
+		}
+	}
+	for _, video := range videos {
+		deleted.Videos = append(deleted.Videos, video.ID)
+		if !removeMedia(r.Context(), ph.Storage, video.ID) {
+			deleted.FailedFiles = append(deleted.FailedFiles, video.ID)
+		}
+		if err := hls.Remove(r.Context(), ph.Storage, video.ID); err != nil {
+			log.Println(err)
+			deleted.FailedFiles = append(deleted.FailedFiles, hls.Prefix(video.ID))
+		}
+		if err := subtitles.Remove(r.Context(), ph.Storage, video.ID); err != nil {
+			log.Println(err)
+			deleted.FailedFiles = append(deleted.FailedFiles, subtitles.Prefix(video.ID))
+		}
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, deleted)
+}
+
 // Update godoc
 // @Tags Projects
 // @Summary      Set Cover for Project
@@ -330,18 +336,6 @@ func (ph *ProjectsHandlers) Update(w http.ResponseWriter, r *http.Request, user
 // @Router       /v1/projects/{id}/cover [patch]
 // @Security Bearer
 func (ph *ProjectsHandlers) SetCover(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -375,6 +369,7 @@ func (ph *ProjectsHandlers) SetCover(w http.ResponseWriter, r *http.Request, use
 // Upload godoc
 // @Tags Projects
 // @Summary      Upload Cover
+// @Description  Stores the JPEG or PNG cover with its thumbnail, card and backdrop variants
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param Authorization header string true "Bearer AccessToken"
@@ -385,22 +380,11 @@ func (ph *ProjectsHandlers) SetCover(w http.ResponseWriter, r *http.Request, use
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
 // @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 413  {object} views.ErrorResponse "Image is too large"
 // @Failure   	 500  {object} views.ErrorResponse "can't create image"
 // @Router       /v1/projects/{id}/cover [post]
 // @Security Bearer
 func (ph *ProjectsHandlers) UploadCover(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	// Set a const maxMemory to 10MB.
 	const maxMemory = 10 << 20 // 10 MB
 	// Use (http.Request).ParseMultipartForm with the maxMemory const as an argument
@@ -426,29 +410,28 @@ func (ph *ProjectsHandlers) UploadCover(w http.ResponseWriter, r *http.Request,
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid file type", nil)
 		return
 	}
-	ext := mediaTypeToExt(mediaType)
-	fileName := fmt.Sprintf("%s%s", uuid.NewString(), ext)
-	fpath := fmt.Sprintf("%s%s", ph.Dir, fileName)
-	// Use os.Create to create the new file
-	dst, err := os.Create(fpath)
-	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Unable to create file on server", err)
+	if header.Size > maxImageSize {
+		views.RespondWithError(w, http.StatusRequestEntityTooLarge, "Image is too large", nil)
 		return
 	}
-	defer dst.Close()
 
-	if _, err = io.Copy(dst, file); err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
 		return
 	}
 
-	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	fileName, size, err := storeImage(r.Context(), ph.Storage, file, mediaType)
+	if errors.Is(err, imaging.ErrInvalid) {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid image", err)
+		return
+	}
 	if err != nil {
-		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
 		return
 	}
 
-	err = ph.repo.UploadCover(r.Context(), int64(project_id), fileName)
+	err = ph.repo.UploadCover(r.Context(), int64(project_id), fileName, int64(size.X), int64(size.Y))
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
 		return
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies are the reverse proxies whose X-Forwarded-For is believed
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses the comma-separated addresses and CIDR ranges
+// like "10.0.0.0/8, 127.0.0.1", the empty string trusts no proxy
+func ParseTrustedProxies(s string) (TrustedProxies, error) {
+	proxies := TrustedProxies{}
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			addr, err := netip.ParseAddr(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", field, err)
+			}
+			addr = addr.Unmap()
+			proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", field, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+func (tp TrustedProxies) trusted(addr netip.Addr) bool {
+	for _, prefix := range tp {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddr is the rightmost hop of X-Forwarded-For which isn't a trusted proxy,
+// the header is ignored unless the connection comes from a trusted proxy
+func (tp TrustedProxies) clientAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	client, err := netip.ParseAddr(host)
+	if err != nil || !tp.trusted(client.Unmap()) {
+		return netip.Addr{}, false
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// the hops left of a malformed one can't be believed
+			break
+		}
+		client = hop.Unmap()
+		if !tp.trusted(client) {
+			break
+		}
+	}
+	return client, true
+}
+
+// MiddlewareClientIP replaces the RemoteAddr of the requests forwarded by the trusted proxies
+// with the address of the client, so the throttling and sessions see the client
+func MiddlewareClientIP(proxies TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if client, ok := proxies.clientAddr(r); ok {
+				r.RemoteAddr = net.JoinHostPort(client.String(), "0")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientAddr(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 192.0.2.1,2001:db8::/32")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{name: "Untrusted peer", remoteAddr: "198.51.100.1:1234", forwarded: []string{"203.0.113.9"}},
+		{name: "No header", remoteAddr: "10.0.0.1:1234", want: "10.0.0.1"},
+		{name: "Proxy", remoteAddr: "10.0.0.1:1234", forwarded: []string{"203.0.113.9"}, want: "203.0.113.9"},
+		{name: "Spoofed hops", remoteAddr: "10.0.0.1:1234", forwarded: []string{"1.2.3.4, 203.0.113.9"}, want: "203.0.113.9"},
+		{name: "Chain of proxies", remoteAddr: "10.0.0.1:1234", forwarded: []string{"203.0.113.9, 10.1.1.1", "192.0.2.1"}, want: "203.0.113.9"},
+		{name: "Only proxies", remoteAddr: "10.0.0.1:1234", forwarded: []string{"10.2.2.2, 10.1.1.1"}, want: "10.2.2.2"},
+		{name: "Malformed hop", remoteAddr: "10.0.0.1:1234", forwarded: []string{"203.0.113.9, unknown, 10.1.1.1"}, want: "10.1.1.1"},
+		{name: "IPv6", remoteAddr: "[2001:db8::1]:1234", forwarded: []string{"2001:db9::7"}, want: "2001:db9::7"},
+		{name: "Mapped IPv4", remoteAddr: "[::ffff:10.0.0.1]:1234", forwarded: []string{"::ffff:203.0.113.9"}, want: "203.0.113.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			got, ok := proxies.clientAddr(r)
+			if !ok {
+				if tt.want != "" {
+					t.Fatalf("clientAddr() isn't forwarded, want %s", tt.want)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("clientAddr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	for _, s := range []string{"", " , "} {
+		if proxies, err := ParseTrustedProxies(s); err != nil || len(proxies) != 0 {
+			t.Errorf("ParseTrustedProxies(%q) = %v, %v", s, proxies, err)
+		}
+	}
+	for _, s := range []string{"proxy", "10.0.0.0/33", "10.0.0.1:80"} {
+		if _, err := ParseTrustedProxies(s); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) isn't rejected", s)
+		}
+	}
+}
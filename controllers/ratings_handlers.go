@@ -0,0 +1,351 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+const maxReviewLength = 2000
+
+type RatingsHandlers struct {
+	DB *database.Queries
+}
+
+func NewRatingsHandlers(db *database.Queries) *RatingsHandlers {
+	return &RatingsHandlers{
+		DB: db,
+	}
+}
+
+// Rate godoc
+// @Tags Ratings
+// @Summary      Rate Project
+// @Description  Creates or replaces the rating of the user
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Param request body views.RateProjectRequest true "Rating data"
+// @Success      200  {object} views.Rating "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't rate Project"
+// @Router       /v1/projects/{id}/rating [put]
+// @Security Bearer
+func (rh *RatingsHandlers) Rate(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	rpr := views.RateProjectRequest{}
+
+	err = decoder.Decode(&rpr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of RateProjectRequest", err)
+		return
+	}
+	if rpr.Stars < 1 || rpr.Stars > 5 {
+		views.RespondWithError(w, http.StatusBadRequest, "Stars must be between 1 and 5", nil)
+		return
+	}
+	if utf8.RuneCountInString(rpr.Review) > maxReviewLength {
+		views.RespondWithError(w, http.StatusBadRequest, "Review is too long", nil)
+		return
+	}
+
+	_, err = rh.DB.GetProjectById(r.Context(), int64(project_id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+
+	err = rh.DB.RateProject(r.Context(), database.RateProjectParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+		Stars:     rpr.Stars,
+		Review:    rpr.Review,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't rate project", err)
+		return
+	}
+
+	rating, err := rh.DB.GetRatingOfUser(r.Context(), database.GetRatingOfUserParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get rating", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, databaseRating2viewsRating(rating, user.Name))
+}
+
+// Get godoc
+// @Tags Ratings
+// @Summary      Get own Rating of Project
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      200  {object} views.Rating "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not rated"
+// @Router       /v1/projects/{id}/rating [get]
+// @Security Bearer
+func (rh *RatingsHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	rating, err := rh.DB.GetRatingOfUser(r.Context(), database.GetRatingOfUserParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find rating", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, databaseRating2viewsRating(rating, user.Name))
+}
+
+// Delete godoc
+// @Tags Ratings
+// @Summary      Delete own Rating of Project
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't delete rating"
+// @Router       /v1/projects/{id}/rating [delete]
+// @Security Bearer
+func (rh *RatingsHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	err = rh.DB.DeleteRatingOfUser(r.Context(), database.DeleteRatingOfUserParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete rating", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(project_id))
+}
+
+// GetAllOfProject godoc
+// @Tags Ratings
+// @Summary      Get Ratings of Project
+// @Description  Hidden reviews are listed only for users with Projects write permission.
+// @Description  The total number of ratings is in the X-Total-Count header.
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Param limit query int false "Limit (1-100, 20 by default)"
+// @Param offset query int false "Offset"
+// @Success      200  {array} views.Rating "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get ratings"
+// @Router       /v1/projects/{id}/ratings [get]
+// @Security Bearer
+func (rh *RatingsHandlers) GetAllOfProject(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	limit, offset, err := getPagination(r)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid pagination", err)
+		return
+	}
+
+	withHidden := HasPermission(user.Roles, ResourceProjects, PermissionWrite)
+
+	total, err := rh.DB.CountRatingsOfProject(r.Context(), database.CountRatingsOfProjectParams{
+		ProjectID:  int64(project_id),
+		WithHidden: withHidden,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't count ratings", err)
+		return
+	}
+
+	rows, err := rh.DB.GetRatingsOfProject(r.Context(), database.GetRatingsOfProjectParams{
+		ProjectID:  int64(project_id),
+		WithHidden: withHidden,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get ratings", err)
+		return
+	}
+
+	ratings := []views.Rating{}
+	for _, row := range rows {
+		ratings = append(ratings, ratingsRow2viewsRating(row))
+	}
+
+	setTotalCount(w, total)
+	views.RespondWithJSON(w, http.StatusOK, ratings)
+}
+
+// GetAllOfProfile godoc
+// @Tags Ratings
+// @Summary      Get Ratings of User profile
+// @Description  The total number of ratings is in the X-Total-Count header.
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param limit query int false "Limit (1-100, 20 by default)"
+// @Param offset query int false "Offset"
+// @Success      200  {array} views.Rating "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get ratings"
+// @Router       /v1/users/profile/ratings [get]
+// @Security Bearer
+func (rh *RatingsHandlers) GetAllOfProfile(w http.ResponseWriter, r *http.Request, user views.User) {
+	limit, offset, err := getPagination(r)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid pagination", err)
+		return
+	}
+
+	total, err := rh.DB.CountRatingsOfUser(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't count ratings", err)
+		return
+	}
+
+	rows, err := rh.DB.GetRatingsOfUser(r.Context(), database.GetRatingsOfUserParams{
+		UserID: user.Id,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get ratings", err)
+		return
+	}
+
+	ratings := []views.Rating{}
+	for _, row := range rows {
+		ratings = append(ratings, ratingsRow2viewsRating(database.GetRatingsOfProjectRow(row)))
+	}
+
+	setTotalCount(w, total)
+	views.RespondWithJSON(w, http.StatusOK, ratings)
+}
+
+// Hide godoc
+// @Tags Ratings
+// @Summary      Hide or show Review (moderation)
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "rating id"
+// @Param request body views.HideRatingRequest true "Moderation data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Rating"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't hide review"
+// @Router       /v1/projects/ratings/{id} [patch]
+// @Security Bearer
+func (rh *RatingsHandlers) Hide(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	hrr := views.HideRatingRequest{}
+
+	err = decoder.Decode(&hrr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of HideRatingRequest", err)
+		return
+	}
+
+	_, err = rh.DB.GetRatingById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find rating", err)
+		return
+	}
+
+	err = rh.DB.SetRatingHidden(r.Context(), database.SetRatingHiddenParams{
+		ID:     int64(id),
+		Hidden: hrr.Hidden,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't hide review", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func databaseRating2viewsRating(rating database.Rating, userName string) views.Rating {
+	return views.Rating{
+		ID:        rating.ID,
+		CreatedAt: rating.CreatedAt,
+		UpdatedAt: rating.UpdatedAt,
+		UserID:    rating.UserID,
+		UserName:  userName,
+		ProjectID: rating.ProjectID,
+		Stars:     rating.Stars,
+		Review:    rating.Review,
+		Hidden:    rating.Hidden,
+	}
+}
+
+func ratingsRow2viewsRating(row database.GetRatingsOfProjectRow) views.Rating {
+	return views.Rating{
+		ID:        row.ID,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		UserID:    row.UserID,
+		UserName:  row.UserName,
+		ProjectID: row.ProjectID,
+		Stars:     row.Stars,
+		Review:    row.Review,
+		Hidden:    row.Hidden,
+	}
+}
@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -35,17 +34,6 @@ func NewRolesHandlers(db *database.Queries) *RolesHandlers {
 // @Router       /v1/roles [get]
 // @Security Bearer
 func (rh *RolesHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Roles >= 2 {
-			can_do = true
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	roles, err := rh.DB.GetRoles(r.Context())
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get roles", err)
@@ -71,18 +59,6 @@ func (rh *RolesHandlers) GetAll(w http.ResponseWriter, r *http.Request, user vie
 // @Router       /v1/roles [post]
 // @Security Bearer
 func (rh *RolesHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Roles == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	decoder := json.NewDecoder(r.Body)
 	crr := views.CreateRoleRequest{}
 
@@ -93,13 +69,14 @@ func (rh *RolesHandlers) Create(w http.ResponseWriter, r *http.Request, user vie
 	}
 
 	id, err := rh.DB.CreateRole(r.Context(), database.CreateRoleParams{
-		Title:         crr.Title,
-		Projects:      crr.Projects,
-		Genres:        crr.Genres,
-		AgeCategories: crr.AgeCategories,
-		Types:         crr.Types,
-		Users:         crr.Users,
-		Roles:         crr.Roles,
+		Title:            crr.Title,
+		Projects:         crr.Projects,
+		Genres:           crr.Genres,
+		AgeCategories:    crr.AgeCategories,
+		Types:            crr.Types,
+		Users:            crr.Users,
+		Roles:            crr.Roles,
+		RequireTwoFactor: crr.RequireTwoFactor,
 	})
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create roles", err)
@@ -127,18 +104,6 @@ func (rh *RolesHandlers) Create(w http.ResponseWriter, r *http.Request, user vie
 // @Router       /v1/roles/{id} [get]
 // @Security Bearer
 func (rh *RolesHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Roles >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -171,18 +136,6 @@ func (rh *RolesHandlers) Get(w http.ResponseWriter, r *http.Request, user views.
 // @Router       /v1/roles/{id} [put]
 // @Security Bearer
 func (rh *RolesHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Roles == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -199,14 +152,15 @@ func (rh *RolesHandlers) Update(w http.ResponseWriter, r *http.Request, user vie
 	}
 
 	err = rh.DB.UpdateRole(r.Context(), database.UpdateRoleParams{
-		ID:            int64(id),
-		Title:         urr.Title,
-		Projects:      urr.Projects,
-		Genres:        urr.Genres,
-		AgeCategories: urr.AgeCategories,
-		Types:         urr.Types,
-		Users:         urr.Users,
-		Roles:         urr.Roles,
+		ID:               int64(id),
+		Title:            urr.Title,
+		Projects:         urr.Projects,
+		Genres:           urr.Genres,
+		AgeCategories:    urr.AgeCategories,
+		Types:            urr.Types,
+		Users:            urr.Users,
+		Roles:            urr.Roles,
+		RequireTwoFactor: urr.RequireTwoFactor,
 	})
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get roles", err)
@@ -232,18 +186,6 @@ func (rh *RolesHandlers) Update(w http.ResponseWriter, r *http.Request, user vie
 // @Router       /v1/roles/{id} [delete]
 // @Security Bearer
 func (rh *RolesHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Roles >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
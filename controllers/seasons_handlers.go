@@ -0,0 +1,482 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+const releaseDateLayout = "2006-01-02"
+
+type SeasonsHandlers struct {
+	DB   *database.Queries
+	repo *repositories.ProjectsRepository
+}
+
+func NewSeasonsHandlers(db *database.Queries, repo *repositories.ProjectsRepository) *SeasonsHandlers {
+	return &SeasonsHandlers{
+		DB:   db,
+		repo: repo,
+	}
+}
+
+// GetAll godoc
+// @Tags Seasons
+// @Summary      Get Seasons of Project with their Episodes
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      200  {array} views.Season "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get seasons"
+// @Router       /v1/projects/{id}/seasons [get]
+// @Security Bearer
+func (sh *SeasonsHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	project, err := sh.repo.GetById(r.Context(), int64(project_id))
+	if errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get seasons", err)
+		return
+	}
+
+	seasons := project.Seasons
+	if seasons == nil {
+		seasons = []views.Season{}
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, seasons)
+}
+
+// Create godoc
+// @Tags Seasons
+// @Summary      Create Season of Project
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Param request body views.CreateSeasonRequest true "Season data"
+// @Success      201  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 409  {object} views.ErrorResponse "Season number is taken"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't create season"
+// @Router       /v1/projects/{id}/seasons [post]
+// @Security Bearer
+func (sh *SeasonsHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	csr := views.CreateSeasonRequest{}
+
+	err = decoder.Decode(&csr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of CreateSeasonRequest", err)
+		return
+	}
+	if csr.Number < 0 {
+		views.RespondWithError(w, http.StatusBadRequest, "Number can't be negative", nil)
+		return
+	}
+
+	_, err = sh.DB.GetProjectById(r.Context(), int64(project_id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+
+	_, err = sh.DB.GetSeasonByNumber(r.Context(), database.GetSeasonByNumberParams{
+		ProjectID: int64(project_id),
+		Number:    csr.Number,
+	})
+	if err == nil {
+		views.RespondWithError(w, http.StatusConflict, "Season number is taken", nil)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't check season number", err)
+		return
+	}
+
+	id, err := sh.DB.CreateSeason(r.Context(), database.CreateSeasonParams{
+		ProjectID: int64(project_id),
+		Number:    csr.Number,
+		Title:     csr.Title,
+		Synopsis:  csr.Synopsis,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create season", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(int(id)))
+}
+
+// Update godoc
+// @Tags Seasons
+// @Summary      Update Season
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "season id"
+// @Param request body views.UpdateSeasonRequest true "Season data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Season"
+// @Failure   	 409  {object} views.ErrorResponse "Season number is taken"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't update season"
+// @Router       /v1/projects/seasons/{id} [put]
+// @Security Bearer
+func (sh *SeasonsHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	usr := views.UpdateSeasonRequest{}
+
+	err = decoder.Decode(&usr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of UpdateSeasonRequest", err)
+		return
+	}
+	if usr.Number < 0 {
+		views.RespondWithError(w, http.StatusBadRequest, "Number can't be negative", nil)
+		return
+	}
+
+	season, err := sh.DB.GetSeasonById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find season", err)
+		return
+	}
+
+	other, err := sh.DB.GetSeasonByNumber(r.Context(), database.GetSeasonByNumberParams{
+		ProjectID: season.ProjectID,
+		Number:    usr.Number,
+	})
+	if err == nil && other.ID != season.ID {
+		views.RespondWithError(w, http.StatusConflict, "Season number is taken", nil)
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't check season number", err)
+		return
+	}
+
+	err = sh.repo.UpdateSeason(r.Context(), season, usr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't update season", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Delete godoc
+// @Tags Seasons
+// @Summary      Delete Season with its Episodes
+// @Description  Videos of the episodes are kept
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "season id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Season"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't delete season"
+// @Router       /v1/projects/seasons/{id} [delete]
+// @Security Bearer
+func (sh *SeasonsHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	_, err = sh.DB.GetSeasonById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find season", err)
+		return
+	}
+
+	err = sh.DB.DeleteEpisodesOfSeason(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete episodes of season", err)
+		return
+	}
+
+	err = sh.DB.DeleteSeason(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete season", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(id))
+}
+
+// CreateEpisode godoc
+// @Tags Seasons
+// @Summary      Create Episode of Season
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "season id"
+// @Param request body views.CreateEpisodeRequest true "Episode data"
+// @Success      201  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Season"
+// @Failure   	 409  {object} views.ErrorResponse "Episode number is taken"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't create episode"
+// @Router       /v1/projects/seasons/{id}/episodes [post]
+// @Security Bearer
+func (sh *SeasonsHandlers) CreateEpisode(w http.ResponseWriter, r *http.Request, user views.User) {
+	season_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	cer := views.CreateEpisodeRequest{}
+
+	err = decoder.Decode(&cer)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of CreateEpisodeRequest", err)
+		return
+	}
+
+	season, err := sh.DB.GetSeasonById(r.Context(), int64(season_id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find season", err)
+		return
+	}
+
+	thumbnail, video, err := sh.validateEpisode(r.Context(), season.ProjectID, views.UpdateEpisodeRequest(cer))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	_, err = sh.DB.GetEpisodeByNumber(r.Context(), database.GetEpisodeByNumberParams{
+		SeasonID: season.ID,
+		Number:   cer.Number,
+	})
+	if err == nil {
+		views.RespondWithError(w, http.StatusConflict, "Episode number is taken", nil)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't check episode number", err)
+		return
+	}
+
+	id, err := sh.DB.CreateEpisode(r.Context(), database.CreateEpisodeParams{
+		SeasonID:       season.ID,
+		Number:         cer.Number,
+		Title:          cer.Title,
+		Synopsis:       cer.Synopsis,
+		DurationInSecs: cer.DurationInSecs,
+		ReleaseDate:    cer.ReleaseDate,
+		Thumbnail:      thumbnail,
+		VideoID:        video,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create episode", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(int(id)))
+}
+
+// UpdateEpisode godoc
+// @Tags Seasons
+// @Summary      Update Episode
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "episode id"
+// @Param request body views.UpdateEpisodeRequest true "Episode data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Episode"
+// @Failure   	 409  {object} views.ErrorResponse "Episode number is taken"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't update episode"
+// @Router       /v1/projects/episodes/{id} [put]
+// @Security Bearer
+func (sh *SeasonsHandlers) UpdateEpisode(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	uer := views.UpdateEpisodeRequest{}
+
+	err = decoder.Decode(&uer)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of UpdateEpisodeRequest", err)
+		return
+	}
+
+	episode, err := sh.DB.GetEpisodeById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find episode", err)
+		return
+	}
+
+	season, err := sh.DB.GetSeasonById(r.Context(), episode.SeasonID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get season", err)
+		return
+	}
+
+	thumbnail, video, err := sh.validateEpisode(r.Context(), season.ProjectID, uer)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	other, err := sh.DB.GetEpisodeByNumber(r.Context(), database.GetEpisodeByNumberParams{
+		SeasonID: season.ID,
+		Number:   uer.Number,
+	})
+	if err == nil && other.ID != episode.ID {
+		views.RespondWithError(w, http.StatusConflict, "Episode number is taken", nil)
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't check episode number", err)
+		return
+	}
+
+	err = sh.repo.UpdateEpisode(r.Context(), season, episode, database.UpdateEpisodeParams{
+		ID:             episode.ID,
+		Number:         uer.Number,
+		Title:          uer.Title,
+		Synopsis:       uer.Synopsis,
+		DurationInSecs: uer.DurationInSecs,
+		ReleaseDate:    uer.ReleaseDate,
+		Thumbnail:      thumbnail,
+		VideoID:        video,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't update episode", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteEpisode godoc
+// @Tags Seasons
+// @Summary      Delete Episode
+// @Description  Video of the episode is kept
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "episode id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Episode"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't delete episode"
+// @Router       /v1/projects/episodes/{id} [delete]
+// @Security Bearer
+func (sh *SeasonsHandlers) DeleteEpisode(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	_, err = sh.DB.GetEpisodeById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find episode", err)
+		return
+	}
+
+	err = sh.DB.DeleteEpisode(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete episode", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(id))
+}
+
+// validateEpisode checks the episode data,
+// the thumbnail and the video have to belong to the project of the season
+func (sh *SeasonsHandlers) validateEpisode(ctx context.Context, projectID int64, uer views.UpdateEpisodeRequest) (sql.NullString, sql.NullString, error) {
+	thumbnail := sql.NullString{}
+	video := sql.NullString{}
+
+	if uer.Number < 0 {
+		return thumbnail, video, errors.New("number can't be negative")
+	}
+	if uer.DurationInSecs < 0 {
+		return thumbnail, video, errors.New("duration can't be negative")
+	}
+	if uer.ReleaseDate != "" {
+		if _, err := time.Parse(releaseDateLayout, uer.ReleaseDate); err != nil {
+			return thumbnail, video, errors.New("release date must be YYYY-MM-DD")
+		}
+	}
+
+	if uer.ThumbnailID != "" {
+		image, err := sh.DB.GetImage(ctx, uer.ThumbnailID)
+		if err != nil || image.ProjectID != projectID {
+			return thumbnail, video, errors.New("thumbnail isn't an image of the project")
+		}
+		thumbnail = sql.NullString{String: image.ID, Valid: true}
+	}
+
+	if uer.VideoID != "" {
+		v, err := sh.DB.GetVideoById(ctx, uer.VideoID)
+		if err != nil || v.ProjectID != projectID {
+			return thumbnail, video, errors.New("video isn't a video of the project")
+		}
+		video = sql.NullString{String: v.ID, Valid: true}
+	}
+
+	return thumbnail, video, nil
+}
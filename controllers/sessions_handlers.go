@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+// maxUserAgentLength bounds the User-Agent saved with the session
+const maxUserAgentLength = 512
+
+// SessionsHandlers lists and revokes the sign ins of the users,
+// a session is a family of the rotated refresh tokens
+type SessionsHandlers struct {
+	DB *database.Queries
+}
+
+func NewSessionsHandlers(db *database.Queries) *SessionsHandlers {
+	return &SessionsHandlers{
+		DB: db,
+	}
+}
+
+// GetAll godoc
+// @Tags Users
+// @Summary      Get Sessions of Profile
+// @Description  Active sign ins of the user, current is the session of the access token
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Success      200  {array} views.Session "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get sessions"
+// @Router       /v1/users/profile/sessions [get]
+// @Security Bearer
+func (sh *SessionsHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
+	rows, err := sh.DB.GetSessionsOfUser(r.Context(), database.GetSessionsOfUserParams{
+		UserID: user.Id,
+		Now:    time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get sessions", err)
+		return
+	}
+
+	sessions := make([]views.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, views.Session{
+			Id:          row.Family,
+			UserAgent:   row.UserAgent,
+			Ip:          row.Ip,
+			CreatedAt:   row.SignedInAt,
+			RefreshedAt: row.CreatedAt,
+			ExpiresAt:   row.ExpiresAt,
+			Current:     row.Family == user.Session,
+		})
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, sessions)
+}
+
+// Revoke godoc
+// @Tags Users
+// @Summary      Revoke Session of Profile
+// @Description  Signs out the session, its access and refresh tokens stop working
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "session id"
+// @Success      204
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Session"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't revoke session"
+// @Router       /v1/users/profile/sessions/{id} [delete]
+// @Security Bearer
+func (sh *SessionsHandlers) Revoke(w http.ResponseWriter, r *http.Request, user views.User) {
+	revoked, err := sh.DB.RevokeSessionOfUser(r.Context(), database.RevokeSessionOfUserParams{
+		Family: chi.URLParam(r, "id"),
+		UserID: user.Id,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't revoke session", err)
+		return
+	}
+	if revoked == 0 {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find session", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOthers godoc
+// @Tags Users
+// @Summary      Revoke other Sessions of Profile
+// @Description  Signs out every session except the session of the access token
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Success      204
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't revoke sessions"
+// @Router       /v1/users/profile/sessions [delete]
+// @Security Bearer
+func (sh *SessionsHandlers) RevokeOthers(w http.ResponseWriter, r *http.Request, user views.User) {
+	err := sh.DB.RevokeOtherSessionsOfUser(r.Context(), database.RevokeOtherSessionsOfUserParams{
+		UserID:  user.Id,
+		Current: user.Session,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOfUser godoc
+// @Tags Users
+// @Summary      Revoke Sessions of User
+// @Description  Signs out every session of the user, e.g. when the account is compromised
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "user id"
+// @Success      204
+// @Failure   	 400  {object} views.ErrorResponse "Invalid id"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't revoke sessions"
+// @Router       /v1/users/{id}/sessions [delete]
+// @Security Bearer
+func (sh *SessionsHandlers) RevokeOfUser(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	_, err = sh.DB.GetUserById(r.Context(), id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't get user", err)
+		return
+	}
+
+	err = sh.DB.RevokeSessionsOfUser(r.Context(), id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func userAgent(r *http.Request) string {
+	ua := r.UserAgent()
+	if len(ua) > maxUserAgentLength {
+		ua = ua[:maxUserAgentLength]
+	}
+	return ua
+}
+
+// clientIP is the address of the connection without the port,
+// MiddlewareClientIP puts the client address there behind the trusted proxies
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
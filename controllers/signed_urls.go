@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/hls"
+	"github.com/Bayan2019/go-ozinshe/imaging"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+// signedURLTTL is the lifetime of the signed media URLs,
+// long enough to start the playback
+const signedURLTTL = 15 * time.Minute
+
+// Paths of the media served by signed URLs instead of the Authorization header
+const (
+	signedVideosPath = "/v1/media/videos/"
+	signedImagesPath = "/v1/media/images/"
+	// signedHLSPath is followed by the video id and the signature,
+	// so the relative URLs of the playlists keep the signature
+	signedHLSPath = "/v1/media/hls/"
+)
+
+// SignedURLsHandlers issues the URLs for <video> and <img> tags,
+// which can't send the Authorization header
+type SignedURLsHandlers struct {
+	DB        *database.Queries
+	JwtSecret string
+	now       func() time.Time
+}
+
+func NewSignedURLsHandlers(db *database.Queries, jwtSecret string) *SignedURLsHandlers {
+	return &SignedURLsHandlers{
+		DB:        db,
+		JwtSecret: jwtSecret,
+		now:       time.Now,
+	}
+}
+
+// Video godoc
+// @Tags Videos
+// @Summary      Get Signed URL of Video
+// @Description  The URL plays the video without the Authorization header for the user
+// @Description  until it expires in 15 minutes, hls_url is the master playlist of the video
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "video id"
+// @Success      200  {object} views.SignedURLResponse "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Video"
+// @Router       /v1/projects/videos/{id}/signed-url [get]
+// @Security Bearer
+func (sh *SignedURLsHandlers) Video(w http.ResponseWriter, r *http.Request, user views.User) {
+	video, err := sh.DB.GetVideoById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find video", err)
+		return
+	}
+
+	expires := sh.expires()
+	signed := sh.sign(signedVideosPath+url.PathEscape(video.ID), user.Id, expires, url.Values{})
+	signed.HlsURL = sh.signHLS(video.ID, user.Id, expires)
+	views.RespondWithJSON(w, http.StatusOK, signed)
+}
+
+// Image godoc
+// @Tags Images
+// @Summary      Get Signed URL of Image
+// @Description  The URL shows the image without the Authorization header for the user
+// @Description  until it expires in 15 minutes, variant is thumbnail, card or backdrop
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "image id"
+// @Param variant query string false "resized copy" Enums(thumbnail, card, backdrop)
+// @Success      200  {object} views.SignedURLResponse "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid variant"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Image"
+// @Router       /v1/projects/images/{id}/signed-url [get]
+// @Security Bearer
+func (sh *SignedURLsHandlers) Image(w http.ResponseWriter, r *http.Request, user views.User) {
+	image, err := sh.DB.GetImage(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find image", err)
+		return
+	}
+
+	query := url.Values{}
+	if variant := r.URL.Query().Get("variant"); variant != "" {
+		if _, ok := imaging.Lookup(variant); !ok {
+			views.RespondWithError(w, http.StatusBadRequest, "Invalid variant", nil)
+			return
+		}
+		query.Set("variant", variant)
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, sh.sign(signedImagesPath+url.PathEscape(image.ID), user.Id, sh.expires(), query))
+}
+
+func (sh *SignedURLsHandlers) expires() time.Time {
+	return sh.now().Add(signedURLTTL).Truncate(time.Second)
+}
+
+func (sh *SignedURLsHandlers) sign(path string, userID int64, expires time.Time, query url.Values) views.SignedURLResponse {
+	query.Set("user", strconv.FormatInt(userID, 10))
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", mediaSignature(sh.JwtSecret, path, userID, expires.Unix()))
+	return views.SignedURLResponse{
+		URL:       path + "?" + query.Encode(),
+		ExpiresAt: expires.UTC().Format(time.RFC3339),
+	}
+}
+
+// signHLS signs the prefix of the HLS package of the video,
+// the user, expiry and signature are the path elements after the video id
+func (sh *SignedURLsHandlers) signHLS(videoID string, userID int64, expires time.Time) string {
+	prefix := signedHLSPath + url.PathEscape(videoID) + "/"
+	signature := mediaSignature(sh.JwtSecret, prefix, userID, expires.Unix())
+	return fmt.Sprintf("%s%d/%d/%s/%s", prefix, userID, expires.Unix(), signature, hls.MasterPlaylist)
+}
+
+// mediaSignature binds the path of the media to the user and the expiry
+func mediaSignature(secret, path string, userID, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("media\n" + path + "\n" + strconv.FormatInt(userID, 10) + "\n" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+var errInvalidSignature = errors.New("invalid signature")
+
+// verifyMediaSignature returns the user of the signed URL of the request
+func verifyMediaSignature(secret string, r *http.Request, now time.Time) (int64, error) {
+	query := r.URL.Query()
+	return checkMediaSignature(secret, r.URL.EscapedPath(), query.Get("user"), query.Get("expires"), query.Get("signature"), now)
+}
+
+// verifyHLSSignature returns the user of the signed prefix of the HLS request
+func verifyHLSSignature(secret string, r *http.Request, now time.Time) (int64, error) {
+	prefix := signedHLSPath + url.PathEscape(chi.URLParam(r, "id")) + "/"
+	return checkMediaSignature(secret, prefix, chi.URLParam(r, "user"), chi.URLParam(r, "expires"), chi.URLParam(r, "signature"), now)
+}
+
+func checkMediaSignature(secret, path, user, expiresAt, signed string, now time.Time) (int64, error) {
+	userID, err := strconv.ParseInt(user, 10, 64)
+	if err != nil {
+		return 0, errInvalidSignature
+	}
+	expires, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil {
+		return 0, errInvalidSignature
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signed)
+	if err != nil {
+		return 0, errInvalidSignature
+	}
+	want, _ := base64.RawURLEncoding.DecodeString(mediaSignature(secret, path, userID, expires))
+	if !hmac.Equal(signature, want) {
+		return 0, errInvalidSignature
+	}
+	if now.Unix() > expires {
+		return 0, errors.New("signature has expired")
+	}
+	return userID, nil
+}
+
+// MiddlewareSignature authenticates the signed URL instead of the Authorization header,
+// the user must still have the level on the resource
+func (ah *AuthHandlers) MiddlewareSignature(resource Resource, level PermissionLevel, handler authedHandler) http.HandlerFunc {
+	return ah.middlewareSigned(verifyMediaSignature, resource, level, handler)
+}
+
+// MiddlewareHLSSignature authenticates the signed prefix of the HLS package
+// as MiddlewareSignature does the signed URL
+func (ah *AuthHandlers) MiddlewareHLSSignature(resource Resource, level PermissionLevel, handler authedHandler) http.HandlerFunc {
+	return ah.middlewareSigned(verifyHLSSignature, resource, level, handler)
+}
+
+func (ah *AuthHandlers) middlewareSigned(verify func(secret string, r *http.Request, now time.Time) (int64, error), resource Resource, level PermissionLevel, handler authedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := verify(ah.JwtSecret, r, time.Now())
+		if err != nil {
+			views.RespondWithError(w, http.StatusUnauthorized, "Invalid signed URL", err)
+			return
+		}
+
+		user, err := ah.DB.GetUserById(r.Context(), userID)
+		if err != nil {
+			views.RespondWithError(w, http.StatusUnauthorized, "Couldn't get user", err)
+			return
+		}
+
+		roles, err := ah.rolesOf(r.Context(), user)
+		if err != nil {
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get roles", err)
+			return
+		}
+
+		RequirePermission(resource, level, handler)(w, r, views.User{
+			Id:            user.ID,
+			Name:          user.Name,
+			Email:         user.Email,
+			DateOfBirth:   user.DateOfBirth,
+			Phone:         user.Phone,
+			Roles:         roles,
+			EmailVerified: user.EmailVerifiedAt.Valid,
+			TwoFactor:     user.TotpEnabledAt.Valid,
+		})
+	}
+}
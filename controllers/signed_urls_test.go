@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyMediaSignature(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sh := &SignedURLsHandlers{JwtSecret: "secret", now: func() time.Time { return now }}
+	signed := sh.sign(signedVideosPath+"video.mp4", 7, sh.expires(), url.Values{}).URL
+
+	tests := []struct {
+		name    string
+		url     string
+		secret  string
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "Valid", url: signed, secret: "secret", now: now},
+		{name: "Just before expiry", url: signed, secret: "secret", now: now.Add(signedURLTTL)},
+		{name: "Expired", url: signed, secret: "secret", now: now.Add(signedURLTTL + time.Second), wantErr: true},
+		{name: "Other secret", url: signed, secret: "other", now: now, wantErr: true},
+		{name: "Other video", url: strings.Replace(signed, "video.mp4", "other.mp4", 1), secret: "secret", now: now, wantErr: true},
+		{name: "Other user", url: strings.Replace(signed, "user=7", "user=8", 1), secret: "secret", now: now, wantErr: true},
+		{name: "Extended expiry", url: strings.Replace(signed, "expires=", "expires=9", 1), secret: "secret", now: now, wantErr: true},
+		{name: "No signature", url: signedVideosPath + "video.mp4?user=7", secret: "secret", now: now, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := verifyMediaSignature(tt.secret, httptest.NewRequest("GET", tt.url, nil), tt.now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("verifyMediaSignature(%s) isn't rejected", tt.url)
+				}
+				return
+			}
+			if err != nil || userID != 7 {
+				t.Errorf("verifyMediaSignature(%s) = %d, %v", tt.url, userID, err)
+			}
+		})
+	}
+}
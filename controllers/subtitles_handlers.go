@@ -0,0 +1,355 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/subtitles"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// maxSubtitlesSize of an uploaded subtitles file, 2 MB
+const maxSubtitlesSize = 2 << 20
+
+// languageTag is a BCP 47 tag like kk, ru or en-US
+var languageTag = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+type SubtitlesHandlers struct {
+	DB      *database.Queries
+	Storage storage.Storage
+}
+
+func NewSubtitlesHandlers(db *database.Queries, store storage.Storage) *SubtitlesHandlers {
+	return &SubtitlesHandlers{
+		DB:      db,
+		Storage: store,
+	}
+}
+
+// GetAll godoc
+// @Tags Subtitles
+// @Summary      Get Subtitles of Video
+// @Description  The default track is the first
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "video id"
+// @Success      200  {array} views.Subtitle "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Video"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't get subtitles"
+// @Router       /v1/projects/videos/{id}/subtitles [get]
+// @Security Bearer
+func (sh *SubtitlesHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
+	video, err := sh.DB.GetVideoById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find video", err)
+		return
+	}
+
+	dSubtitles, err := sh.DB.GetSubtitlesOfVideo(r.Context(), video.ID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get subtitles", err)
+		return
+	}
+
+	vSubtitles := []views.Subtitle{}
+	for _, s := range dSubtitles {
+		vSubtitles = append(vSubtitles, subtitle2view(s))
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, vSubtitles)
+}
+
+// Upload godoc
+// @Tags Subtitles
+// @Summary      Upload Subtitles of Video
+// @Description  Accepts SRT and WebVTT, SRT is converted to WebVTT.
+// @Description  The format is detected by the .srt or .vtt extension of the file.
+// @Description  The cues must end after they start and be ordered by the start.
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "video id"
+// @Param language formData string true "language tag like kk or en-US"
+// @Param label formData string false "label shown by the player"
+// @Param is_default formData bool false "default track of the video"
+// @Param subtitles formData file true "subtitles"
+// @Success      201  {object} views.ResponseId "Created"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data or subtitles"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Video"
+// @Failure   	 409  {object} views.ErrorResponse "Subtitles already exist"
+// @Failure   	 413  {object} views.ErrorResponse "Subtitles are too large"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't save subtitles"
+// @Router       /v1/projects/videos/{id}/subtitles [post]
+// @Security Bearer
+func (sh *SubtitlesHandlers) Upload(w http.ResponseWriter, r *http.Request, user views.User) {
+	file, header, err := r.FormFile("subtitles")
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Unable to parse form file", err)
+		return
+	}
+	defer file.Close()
+
+	language := strings.TrimSpace(r.FormValue("language"))
+	if !languageTag.MatchString(language) {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid language", nil)
+		return
+	}
+	label := strings.TrimSpace(r.FormValue("label"))
+	isDefault := false
+	if value := r.FormValue("is_default"); value != "" {
+		isDefault, err = strconv.ParseBool(value)
+		if err != nil {
+			views.RespondWithError(w, http.StatusBadRequest, "Invalid is_default", err)
+			return
+		}
+	}
+
+	if header.Size > maxSubtitlesSize {
+		views.RespondWithError(w, http.StatusRequestEntityTooLarge, "Subtitles are too large", nil)
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Unable to read file", err)
+		return
+	}
+
+	vtt, err := subtitles.ToWebVTT(data, subtitles.DetectFormat(header.Filename, data))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	video, err := sh.DB.GetVideoById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find video", err)
+		return
+	}
+
+	others, err := sh.DB.GetSubtitlesOfVideo(r.Context(), video.ID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get subtitles", err)
+		return
+	}
+	for _, other := range others {
+		if other.Language == language && other.Label == label {
+			views.RespondWithError(w, http.StatusConflict, "Subtitles already exist", nil)
+			return
+		}
+	}
+
+	fileName := subtitles.Prefix(video.ID) + uuid.NewString() + ".vtt"
+	err = sh.Storage.Put(r.Context(), fileName, bytes.NewReader(vtt), int64(len(vtt)), "text/vtt")
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save subtitles", err)
+		return
+	}
+
+	id, err := sh.DB.AddSubtitle(r.Context(), database.AddSubtitleParams{
+		VideoID:   video.ID,
+		Language:  language,
+		Label:     label,
+		IsDefault: isDefault,
+		File:      fileName,
+	})
+	if err != nil {
+		removeMedia(r.Context(), sh.Storage, fileName)
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save subtitles", err)
+		return
+	}
+
+	if isDefault {
+		err = sh.DB.SetDefaultSubtitle(r.Context(), database.SetDefaultSubtitleParams{
+			ID:      id,
+			VideoID: video.ID,
+		})
+		if err != nil {
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't set default subtitles", err)
+			return
+		}
+	}
+
+	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(int(id)))
+}
+
+// Get godoc
+// @Tags Subtitles
+// @Summary      Get Subtitles File
+// @Description  Streams the WebVTT file of the track
+// @Accept       json
+// @Produce      text/vtt
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "subtitles id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid id"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Subtitles"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't open file"
+// @Router       /v1/projects/subtitles/{id} [get]
+// @Security Bearer
+func (sh *SubtitlesHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	subtitle, err := sh.DB.GetSubtitleById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find subtitles", err)
+		return
+	}
+
+	serveMedia(w, r, sh.Storage, subtitle.File, false)
+}
+
+// Update godoc
+// @Tags Subtitles
+// @Summary      Update Subtitles
+// @Description  Setting is_default unsets it for the other tracks of the video
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "subtitles id"
+// @Param request body views.UpdateSubtitleRequest true "Subtitles data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Subtitles"
+// @Failure   	 409  {object} views.ErrorResponse "Subtitles already exist"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't update subtitles"
+// @Router       /v1/projects/subtitles/{id} [put]
+// @Security Bearer
+func (sh *SubtitlesHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	usr := views.UpdateSubtitleRequest{}
+
+	err = decoder.Decode(&usr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of UpdateSubtitleRequest", err)
+		return
+	}
+	usr.Language = strings.TrimSpace(usr.Language)
+	usr.Label = strings.TrimSpace(usr.Label)
+	if !languageTag.MatchString(usr.Language) {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid language", nil)
+		return
+	}
+
+	subtitle, err := sh.DB.GetSubtitleById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find subtitles", err)
+		return
+	}
+
+	others, err := sh.DB.GetSubtitlesOfVideo(r.Context(), subtitle.VideoID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get subtitles", err)
+		return
+	}
+	for _, other := range others {
+		if other.ID != subtitle.ID && other.Language == usr.Language && other.Label == usr.Label {
+			views.RespondWithError(w, http.StatusConflict, "Subtitles already exist", nil)
+			return
+		}
+	}
+
+	err = sh.DB.UpdateSubtitle(r.Context(), database.UpdateSubtitleParams{
+		ID:        subtitle.ID,
+		Language:  usr.Language,
+		Label:     usr.Label,
+		IsDefault: usr.IsDefault,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't update subtitles", err)
+		return
+	}
+
+	if usr.IsDefault {
+		err = sh.DB.SetDefaultSubtitle(r.Context(), database.SetDefaultSubtitleParams{
+			ID:      subtitle.ID,
+			VideoID: subtitle.VideoID,
+		})
+		if err != nil {
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't set default subtitles", err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Delete godoc
+// @Tags Subtitles
+// @Summary      Delete Subtitles
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "subtitles id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid id"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Subtitles"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't delete subtitles"
+// @Router       /v1/projects/subtitles/{id} [delete]
+// @Security Bearer
+func (sh *SubtitlesHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	subtitle, err := sh.DB.GetSubtitleById(r.Context(), int64(id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find subtitles", err)
+		return
+	}
+
+	err = sh.Storage.Delete(r.Context(), subtitle.File)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete file", err)
+		return
+	}
+
+	err = sh.DB.DeleteSubtitle(r.Context(), subtitle.ID)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete subtitles", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(id))
+}
+
+func subtitle2view(s database.Subtitle) views.Subtitle {
+	return views.Subtitle{
+		ID:        s.ID,
+		VideoID:   s.VideoID,
+		Language:  s.Language,
+		Label:     s.Label,
+		IsDefault: s.IsDefault,
+	}
+}
@@ -0,0 +1,362 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/totp"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+// challengeTTL is the time to enter the 2FA code after the password
+const challengeTTL = 5 * time.Minute
+
+// totpIssuer names the account in the authenticator apps
+const totpIssuer = "Ozinshe"
+
+// recoveryCodesCount is the number of the recovery codes of the enrollment
+const recoveryCodesCount = 10
+
+func (ah *AuthHandlers) respondChallenge(w http.ResponseWriter, user database.User) {
+	expiresAt := time.Now().UTC().Add(challengeTTL)
+	challengeToken, err := signJWT(TokenTypeChallenge, user.Email, "", ah.JwtSecret, challengeTTL)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create challenge token", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusAccepted, views.TwoFactorChallengeResponse{
+		ChallengeToken: challengeToken,
+		ExpiresAt:      expiresAt.Format(time.RFC3339),
+	})
+}
+
+// SignIn2FA godoc
+// @Tags Auth
+// @Summary      Sign In with 2FA code
+// @Description  Second step of the sign in with 2FA on: the challenge token of the sign in
+// @Description  and the code of the authenticator app or a recovery code
+// @Accept       json
+// @Produce      json
+// @Param request body views.TwoFactorSignInRequest true "Challenge and code"
+// @Success      200  {object} views.TokensResponse "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid Data"
+// @Failure   	 401  {object} views.ErrorResponse "Invalid challenge token or code"
+// @Failure   	 429  {object} views.ErrorResponse "Too many attempts, see Retry-After"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't create tokens"
+// @Router       /v1/auth/sign-in/2fa [post]
+func (ah *AuthHandlers) SignIn2FA(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	tfr := views.TwoFactorSignInRequest{}
+	err := decoder.Decode(&tfr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid Data", err)
+		return
+	}
+
+	email, _, err := parseJWT(TokenTypeChallenge, tfr.ChallengeToken, ah.JwtSecret)
+	if err != nil {
+		views.RespondWithError(w, http.StatusUnauthorized, "Invalid challenge token", err)
+		return
+	}
+
+	// the codes are guessed slower than the passwords
+	account := normalizeEmail(email)
+	ip := clientIP(r)
+	wait, err := throttleAttempt(r.Context(), ah.Addresses, ip, ah.Accounts, account)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save sign in attempt", err)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		views.RespondWithError(w, http.StatusTooManyRequests, "Too many sign in attempts, try again later", nil)
+		return
+	}
+
+	user, err := ah.DB.GetUserByEmail(r.Context(), email)
+	if err != nil || !user.TotpEnabledAt.Valid {
+		views.RespondWithError(w, http.StatusUnauthorized, "Invalid challenge token", err)
+		return
+	}
+
+	ok, err := ah.checkSecondFactor(r.Context(), user, tfr.Code)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't check code", err)
+		return
+	}
+	if !ok {
+		views.RespondWithError(w, http.StatusUnauthorized, "Invalid code", nil)
+		return
+	}
+
+	err = errors.Join(
+		ah.Accounts.Reset(r.Context(), account),
+		ah.Addresses.Forgive(r.Context(), ip),
+	)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't reset sign in attempts", err)
+		return
+	}
+
+	ah.startSession(w, r, user)
+}
+
+// checkSecondFactor accepts a code of the authenticator app or a recovery code, each of them once
+func (ah *AuthHandlers) checkSecondFactor(ctx context.Context, user database.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if counter, ok := totp.Validate(user.TotpSecret.String, code, time.Now(), user.TotpCounter); ok {
+		// a concurrent request may have used the code first
+		used, err := ah.DB.UseTotpCounter(ctx, database.UseTotpCounterParams{
+			Counter: counter,
+			ID:      user.ID,
+		})
+		return used == 1, err
+	}
+
+	used, err := ah.DB.UseRecoveryCode(ctx, database.UseRecoveryCodeParams{
+		CodeHash: hashToken(normalizeRecoveryCode(code)),
+		UserID:   user.ID,
+	})
+	return used == 1, err
+}
+
+// Enroll2FA godoc
+// @Tags Users
+// @Summary      Start 2FA enrollment of Profile
+// @Description  Returns the secret and its otpauth:// URI to show as a QR code,
+// @Description  2FA is on after the first code is confirmed
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Success      200  {object} views.TwoFactorEnrollmentResponse "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 409  {object} views.ErrorResponse "2FA is already on"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't save secret"
+// @Router       /v1/users/profile/2fa [post]
+// @Security Bearer
+func (ah *AuthHandlers) Enroll2FA(w http.ResponseWriter, r *http.Request, user views.User) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create secret", err)
+		return
+	}
+
+	// a new enrollment replaces the pending secret
+	saved, err := ah.DB.SetTotpSecret(r.Context(), database.SetTotpSecretParams{
+		TotpSecret: sql.NullString{String: secret, Valid: true},
+		ID:         user.Id,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save secret", err)
+		return
+	}
+	if saved == 0 {
+		views.RespondWithError(w, http.StatusConflict, "2FA is already on", nil)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.TwoFactorEnrollmentResponse{
+		Secret: secret,
+		URI:    totp.URI(totpIssuer, user.Email, secret),
+	})
+}
+
+// Confirm2FA godoc
+// @Tags Users
+// @Summary      Confirm 2FA enrollment of Profile
+// @Description  Turns 2FA on with the first code of the app and returns the recovery codes, they are shown once
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param request body views.TwoFactorCodeRequest true "Code of the app"
+// @Success      200  {object} views.RecoveryCodesResponse "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid code or no enrollment"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 409  {object} views.ErrorResponse "2FA is already on"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't turn 2FA on"
+// @Router       /v1/users/profile/2fa/confirm [post]
+// @Security Bearer
+func (ah *AuthHandlers) Confirm2FA(w http.ResponseWriter, r *http.Request, user views.User) {
+	decoder := json.NewDecoder(r.Body)
+	tcr := views.TwoFactorCodeRequest{}
+	err := decoder.Decode(&tcr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of TwoFactorCodeRequest", err)
+		return
+	}
+
+	dUser, err := ah.DB.GetUserById(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't get user", err)
+		return
+	}
+	if dUser.TotpEnabledAt.Valid {
+		views.RespondWithError(w, http.StatusConflict, "2FA is already on", nil)
+		return
+	}
+	if !dUser.TotpSecret.Valid {
+		views.RespondWithError(w, http.StatusBadRequest, "Start the enrollment first", nil)
+		return
+	}
+
+	counter, ok := totp.Validate(dUser.TotpSecret.String, strings.TrimSpace(tcr.Code), time.Now(), 0)
+	if !ok {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid code", nil)
+		return
+	}
+
+	enabled, err := ah.DB.EnableTotp(r.Context(), database.EnableTotpParams{
+		TotpCounter: counter,
+		ID:          user.Id,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't turn 2FA on", err)
+		return
+	}
+	if enabled == 0 {
+		views.RespondWithError(w, http.StatusConflict, "2FA is already on", nil)
+		return
+	}
+
+	codes, err := ah.createRecoveryCodes(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create recovery codes", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// Disable2FA godoc
+// @Tags Users
+// @Summary      Turn 2FA off for Profile
+// @Description  Requires the password and a code of the app or a recovery code
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param request body views.DisableTwoFactorRequest true "Password and code"
+// @Success      204
+// @Failure   	 400  {object} views.ErrorResponse "2FA is off"
+// @Failure   	 401  {object} views.ErrorResponse "Incorrect password or invalid code"
+// @Failure   	 429  {object} views.ErrorResponse "Too many attempts, see Retry-After"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't turn 2FA off"
+// @Router       /v1/users/profile/2fa [delete]
+// @Security Bearer
+func (ah *AuthHandlers) Disable2FA(w http.ResponseWriter, r *http.Request, user views.User) {
+	decoder := json.NewDecoder(r.Body)
+	dtr := views.DisableTwoFactorRequest{}
+	err := decoder.Decode(&dtr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of DisableTwoFactorRequest", err)
+		return
+	}
+
+	dUser, err := ah.DB.GetUserById(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't get user", err)
+		return
+	}
+	if !dUser.TotpEnabledAt.Valid {
+		views.RespondWithError(w, http.StatusBadRequest, "2FA is off", nil)
+		return
+	}
+
+	// a stolen access token doesn't help to guess the codes faster than the sign in
+	account := normalizeEmail(dUser.Email)
+	ip := clientIP(r)
+	wait, err := throttleAttempt(r.Context(), ah.Addresses, ip, ah.Accounts, account)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save sign in attempt", err)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		views.RespondWithError(w, http.StatusTooManyRequests, "Too many attempts, try again later", nil)
+		return
+	}
+
+	err = checkPasswordHash(dtr.CurrentPassword, dUser.PasswordHash)
+	if err != nil {
+		views.RespondWithError(w, http.StatusUnauthorized, "Incorrect password", err)
+		return
+	}
+	ok, err := ah.checkSecondFactor(r.Context(), dUser, dtr.Code)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't check code", err)
+		return
+	}
+	if !ok {
+		views.RespondWithError(w, http.StatusUnauthorized, "Invalid code", nil)
+		return
+	}
+
+	err = errors.Join(
+		ah.Accounts.Reset(r.Context(), account),
+		ah.Addresses.Forgive(r.Context(), ip),
+	)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't reset sign in attempts", err)
+		return
+	}
+
+	err = ah.DB.DisableTotp(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't turn 2FA off", err)
+		return
+	}
+	err = ah.DB.DeleteRecoveryCodesOfUser(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete recovery codes", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var recoveryEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// createRecoveryCodes replaces the recovery codes of the user,
+// a code is 10 characters like "abcde-fghij"
+func (ah *AuthHandlers) createRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	err := ah.DB.DeleteRecoveryCodesOfUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		random := make([]byte, 7)
+		_, err := rand.Read(random)
+		if err != nil {
+			return nil, err
+		}
+		code := recoveryEncoding.EncodeToString(random)[:10]
+		err = ah.DB.AddRecoveryCode(ctx, database.AddRecoveryCodeParams{
+			CodeHash: hashToken(code),
+			UserID:   userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode accepts the codes typed without the dash or in upper case
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
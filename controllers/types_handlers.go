@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -35,17 +34,6 @@ func NewTypesHandlers(db *database.Queries) *TypeHandlers {
 // @Router       /v1/types [get]
 // @Security Bearer
 func (th *TypeHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Types >= 2 {
-			can_do = true
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	types, err := th.DB.GetTypes(r.Context())
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get genres", err)
@@ -71,18 +59,6 @@ func (th *TypeHandlers) GetAll(w http.ResponseWriter, r *http.Request, user view
 // @Router       /v1/types [post]
 // @Security Bearer
 func (th *TypeHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Types == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	decoder := json.NewDecoder(r.Body)
 	ctr := views.CreateTypeRequest{}
 
@@ -119,18 +95,6 @@ func (th *TypeHandlers) Create(w http.ResponseWriter, r *http.Request, user view
 // @Router       /v1/types/{id} [get]
 // @Security Bearer
 func (th *TypeHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Types >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -163,18 +127,6 @@ func (th *TypeHandlers) Get(w http.ResponseWriter, r *http.Request, user views.U
 // @Router       /v1/types/{id} [put]
 // @Security Bearer
 func (th *TypeHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Types == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -218,18 +170,6 @@ func (th *TypeHandlers) Update(w http.ResponseWriter, r *http.Request, user view
 // @Router       /v1/types/{id} [delete]
 // @Security Bearer
 func (th *TypeHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Types >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -0,0 +1,532 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/imaging"
+	"github.com/Bayan2019/go-ozinshe/mp4"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// tus resumable upload protocol https://tus.io/protocols/resumable-upload
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,expiration,termination"
+	// tusMaxSize is the largest upload, 1 GB as the multipart upload
+	tusMaxSize = 1 << 30
+	// uploadExpiration is how long an upload can be resumed
+	uploadExpiration = 24 * time.Hour
+	// sqliteTimeFormat is the format of CURRENT_TIMESTAMP
+	sqliteTimeFormat = "2006-01-02 15:04:05"
+)
+
+// UploadsHandlers implement resumable uploads of videos and images.
+// The body of every PATCH request is kept in Storage as a part,
+// so any instance can resume the upload. On completion the parts
+// are joined and saved as by VideosHandlers.Upload or ImagesHandlers.Upload.
+type UploadsHandlers struct {
+	DB      *database.Queries
+	Storage storage.Storage
+	images  *ImagesHandlers
+	videos  *VideosHandlers
+}
+
+func NewUploadsHandlers(db *database.Queries, store storage.Storage, images *ImagesHandlers, videos *VideosHandlers) *UploadsHandlers {
+	return &UploadsHandlers{
+		DB:      db,
+		Storage: store,
+		images:  images,
+		videos:  videos,
+	}
+}
+
+// Options godoc
+// @Tags Uploads
+// @Summary      Upload Options
+// @Description  Describes the tus protocol supported by the server
+// @Success      204  "No Content"
+// @Header       204  {string} Tus-Version "1.0.0"
+// @Header       204  {string} Tus-Extension "creation,expiration,termination"
+// @Header       204  {int} Tus-Max-Size "max upload size"
+// @Router       /v1/uploads [options]
+func (uh *UploadsHandlers) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.Itoa(tusMaxSize))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Create godoc
+// @Tags Uploads
+// @Summary      Create Upload
+// @Description  Starts a resumable upload of a video (video/mp4) or an image (image/jpeg, image/png).
+// @Description  Upload-Metadata has base64 encoded filetype and project_id,
+// @Description  season and serie for a video, href for an image.
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param Upload-Length header int true "size of the file"
+// @Param Upload-Metadata header string true "filetype dmlkZW8vbXA0,project_id MQ==,season MQ==,serie MQ=="
+// @Success      201  "Created"
+// @Header       201  {string} Location "URL of the upload"
+// @Header       201  {string} Upload-Expires "when the upload expires"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 412  {object} views.ErrorResponse "Unsupported tus version"
+// @Failure   	 413  {object} views.ErrorResponse "Upload is too large"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't create upload"
+// @Router       /v1/uploads [post]
+// @Security Bearer
+func (uh *UploadsHandlers) Create(w http.ResponseWriter, r *http.Request, user views.User) {
+	if !checkTusResumable(w, r) {
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid Upload-Length", err)
+		return
+	}
+	if length > tusMaxSize {
+		views.RespondWithError(w, http.StatusRequestEntityTooLarge, "Upload is too large", nil)
+		return
+	}
+
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid Upload-Metadata", err)
+		return
+	}
+	err = validateUploadMetadata(metadata)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid Upload-Metadata: "+err.Error(), err)
+		return
+	}
+	if metadata["filetype"] != "video/mp4" && length > maxImageSize {
+		views.RespondWithError(w, http.StatusRequestEntityTooLarge, "Image is too large", nil)
+		return
+	}
+
+	project_id, _ := strconv.Atoi(metadata["project_id"])
+	_, err = uh.DB.GetProjectById(r.Context(), int64(project_id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+
+	uh.removeExpired(r)
+
+	id := uuid.NewString()
+	expires := time.Now().UTC().Add(uploadExpiration)
+	err = uh.DB.CreateUpload(r.Context(), database.CreateUploadParams{
+		ID:        id,
+		ExpiresAt: expires.Format(sqliteTimeFormat),
+		UserID:    user.Id,
+		Length:    length,
+		Metadata:  r.Header.Get("Upload-Metadata"),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create upload", err)
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Location", "/v1/uploads/"+id)
+	w.Header().Set("Upload-Expires", expires.Format(http.TimeFormat))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Head godoc
+// @Tags Uploads
+// @Summary      Upload Offset
+// @Description  Returns how many bytes of the upload are received,
+// @Description  X-Media-Id is the created video or image once the upload is complete
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param id path string true "upload id"
+// @Success      200  "OK"
+// @Header       200  {int} Upload-Offset "received bytes"
+// @Header       200  {int} Upload-Length "size of the file"
+// @Header       200  {string} X-Media-Id "id of the video or image"
+// @Failure   	 401  "No token Middleware"
+// @Failure   	 403  "No Permission"
+// @Failure   	 404  "Not found Upload"
+// @Failure   	 412  "Unsupported tus version"
+// @Router       /v1/uploads/{id} [head]
+// @Security Bearer
+func (uh *UploadsHandlers) Head(w http.ResponseWriter, r *http.Request, user views.User) {
+	if !checkTusResumable(w, r) {
+		return
+	}
+
+	upload, err := uh.DB.GetUploadOfUser(r.Context(), database.GetUploadOfUserParams{
+		ID:     chi.URLParam(r, "id"),
+		UserID: user.Id,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find upload", err)
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Received, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	w.Header().Set("Upload-Metadata", upload.Metadata)
+	if upload.MediaID != "" {
+		w.Header().Set("X-Media-Id", upload.MediaID)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Patch godoc
+// @Tags Uploads
+// @Summary      Append to Upload
+// @Description  Appends the body to the upload at Upload-Offset.
+// @Description  When all the bytes are received the video or image is created, its id is X-Media-Id.
+// @Accept       application/offset+octet-stream
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param Upload-Offset header int true "received bytes"
+// @Param id path string true "upload id"
+// @Success      204  "No Content"
+// @Header       204  {int} Upload-Offset "received bytes"
+// @Header       204  {string} X-Media-Id "id of the video or image"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data or file, the upload is terminated"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Upload"
+// @Failure   	 409  {object} views.ErrorResponse "Wrong Upload-Offset"
+// @Failure   	 412  {object} views.ErrorResponse "Unsupported tus version"
+// @Failure   	 415  {object} views.ErrorResponse "Invalid Content-Type"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't save upload"
+// @Router       /v1/uploads/{id} [patch]
+// @Security Bearer
+func (uh *UploadsHandlers) Patch(w http.ResponseWriter, r *http.Request, user views.User) {
+	if !checkTusResumable(w, r) {
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		views.RespondWithError(w, http.StatusUnsupportedMediaType, "Invalid Content-Type", nil)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid Upload-Offset", err)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	upload, err := uh.DB.GetUploadOfUser(r.Context(), database.GetUploadOfUserParams{
+		ID:     id,
+		UserID: user.Id,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find upload", err)
+		return
+	}
+	if offset != upload.Received || upload.MediaID != "" {
+		views.RespondWithError(w, http.StatusConflict, "Wrong Upload-Offset", nil)
+		return
+	}
+
+	// the received bytes are kept even if the connection drops
+	ctx := context.WithoutCancel(r.Context())
+	part, n, copyErr := uh.storePart(ctx, r.Body, upload)
+	if n > 0 {
+		// the offset guards against the concurrent requests,
+		// only one of them appends its part
+		rows, err := uh.DB.AppendUploadPart(ctx, database.AppendUploadPartParams{
+			Size:   n,
+			Part:   part,
+			ID:     id,
+			Offset: offset,
+		})
+		if err != nil {
+			uh.Storage.Delete(ctx, part)
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save upload", err)
+			return
+		}
+		if rows == 0 {
+			uh.Storage.Delete(ctx, part)
+			views.RespondWithError(w, http.StatusConflict, "Wrong Upload-Offset", nil)
+			return
+		}
+		upload.Received += n
+		upload.Parts += part + "\n"
+	}
+	if copyErr != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save upload", copyErr)
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Received, 10))
+
+	if upload.Received == upload.Length {
+		mediaID, err := uh.complete(r, upload)
+		if errors.Is(err, imaging.ErrInvalid) || errors.Is(err, mp4.ErrInvalid) {
+			// the upload can't be completed, so it is terminated
+			uh.remove(r, id)
+			views.RespondWithError(w, http.StatusBadRequest, "Invalid file", err)
+			return
+		}
+		if err != nil {
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save upload", err)
+			return
+		}
+		w.Header().Set("X-Media-Id", mediaID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete godoc
+// @Tags Uploads
+// @Summary      Terminate Upload
+// @Description  Removes the upload and its received bytes
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param id path string true "upload id"
+// @Success      204  "No Content"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Upload"
+// @Failure   	 412  {object} views.ErrorResponse "Unsupported tus version"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't delete upload"
+// @Router       /v1/uploads/{id} [delete]
+// @Security Bearer
+func (uh *UploadsHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	if !checkTusResumable(w, r) {
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	_, err := uh.DB.GetUploadOfUser(r.Context(), database.GetUploadOfUserParams{
+		ID:     id,
+		UserID: user.Id,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find upload", err)
+		return
+	}
+
+	err = uh.remove(r, id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't delete upload", err)
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// complete saves the received file as the video or image of the project
+func (uh *UploadsHandlers) complete(r *http.Request, upload database.Upload) (string, error) {
+	metadata, err := parseUploadMetadata(upload.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := uh.joinParts(r.Context(), upload)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	project_id, _ := strconv.Atoi(metadata["project_id"])
+	var mediaID string
+	if metadata["filetype"] == "video/mp4" {
+		season, _ := strconv.Atoi(metadata["season"])
+		serie, _ := strconv.Atoi(metadata["serie"])
+		mediaID, err = uh.videos.saveVideo(r.Context(), file, upload.Length, int64(project_id), int64(season), int64(serie))
+	} else {
+		mediaID, err = uh.images.saveImage(r.Context(), file, metadata["filetype"], int64(project_id), metadata["href"])
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = uh.DB.CompleteUpload(r.Context(), database.CompleteUploadParams{
+		MediaID: mediaID,
+		ID:      upload.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	uh.removeParts(r.Context(), upload.ID)
+	return mediaID, nil
+}
+
+// storePart stores the body as the next part of the upload,
+// the bytes read before an error are stored too
+func (uh *UploadsHandlers) storePart(ctx context.Context, body io.Reader, upload database.Upload) (string, int64, error) {
+	// the size of the part must be known to store it
+	file, err := os.CreateTemp("", "upload-*.part")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	n, copyErr := io.Copy(file, io.LimitReader(body, upload.Length-upload.Received))
+	if n == 0 {
+		return "", 0, copyErr
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", 0, err
+	}
+	// the random suffix keeps the part of a concurrent request at the same offset
+	part := fmt.Sprintf("%s%020d-%s", uploadPrefix(upload.ID), upload.Received, uuid.NewString())
+	err = uh.Storage.Put(ctx, part, file, n, "application/octet-stream")
+	if err != nil {
+		return "", 0, err
+	}
+	return part, n, copyErr
+}
+
+// joinParts copies the parts to a temporary file,
+// the video is probed by random reads
+func (uh *UploadsHandlers) joinParts(ctx context.Context, upload database.Upload) (*os.File, error) {
+	file, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range strings.Fields(upload.Parts) {
+		err = uh.copyPart(ctx, file, part)
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
+
+func (uh *UploadsHandlers) copyPart(ctx context.Context, w io.Writer, part string) error {
+	object, err := uh.Storage.Open(ctx, part)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+	_, err = io.Copy(w, object)
+	return err
+}
+
+// remove removes the upload with its received bytes
+func (uh *UploadsHandlers) remove(r *http.Request, id string) error {
+	err := uh.DB.DeleteUpload(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	uh.removeParts(r.Context(), id)
+	return nil
+}
+
+// removeExpired removes the uploads which can't be resumed anymore
+func (uh *UploadsHandlers) removeExpired(r *http.Request) {
+	ids, err := uh.DB.GetExpiredUploads(r.Context())
+	if err != nil {
+		log.Printf("Couldn't get expired uploads: %v", err)
+		return
+	}
+	err = uh.DB.DeleteExpiredUploads(r.Context())
+	if err != nil {
+		log.Printf("Couldn't delete expired uploads: %v", err)
+		return
+	}
+	for _, id := range ids {
+		uh.removeParts(r.Context(), id)
+	}
+}
+
+// removeParts removes the received bytes, the parts left
+// by a failure aren't referenced by any row and are collected as orphans
+func (uh *UploadsHandlers) removeParts(ctx context.Context, id string) {
+	err := uh.Storage.DeletePrefix(ctx, uploadPrefix(id))
+	if err != nil {
+		log.Printf("Couldn't delete parts of upload %s: %v", id, err)
+	}
+}
+
+// uploadPrefix is the prefix of the parts of the upload in the storage
+func uploadPrefix(id string) string {
+	return "uploads/" + id + "/"
+}
+
+// checkTusResumable responds with 412 to the clients of other protocol versions
+func checkTusResumable(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		views.RespondWithError(w, http.StatusPreconditionFailed, "Unsupported tus version", nil)
+		return false
+	}
+	return true
+}
+
+// parseUploadMetadata parses the Upload-Metadata header,
+// comma separated pairs of a key and a base64 encoded value
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("empty key")
+		}
+		if _, ok := metadata[key]; ok {
+			return nil, fmt.Errorf("duplicate key %s", key)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %w", key, err)
+		}
+		metadata[key] = string(decoded)
+	}
+	return metadata, nil
+}
+
+// validateUploadMetadata checks that the metadata describe a video or an image
+func validateUploadMetadata(metadata map[string]string) error {
+	fields := []string{"project_id"}
+	switch metadata["filetype"] {
+	case "video/mp4":
+		fields = append(fields, "season", "serie")
+	case "image/jpeg", "image/png":
+	default:
+		return errors.New("invalid filetype, only MP4, JPEG and PNG are allowed")
+	}
+	for _, field := range fields {
+		_, err := strconv.Atoi(metadata[field])
+		if err != nil {
+			return fmt.Errorf("invalid %s", field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "Empty", header: "", want: map[string]string{}},
+		{name: "Pairs", header: "filetype dmlkZW8vbXA0,project_id MQ==", want: map[string]string{"filetype": "video/mp4", "project_id": "1"}},
+		{name: "Spaces", header: "filetype dmlkZW8vbXA0, project_id MQ==", want: map[string]string{"filetype": "video/mp4", "project_id": "1"}},
+		{name: "Key without value", header: "is_confidential,project_id MQ==", want: map[string]string{"is_confidential": "", "project_id": "1"}},
+		{name: "Duplicate key", header: "project_id MQ==,project_id Mg==", wantErr: true},
+		{name: "Not base64", header: "project_id 1", wantErr: true},
+		{name: "Empty key", header: "project_id MQ==,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUploadMetadata(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseUploadMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseUploadMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -14,17 +13,21 @@ import (
 
 type UsersHandlers struct {
 	userRepo *repositories.UsersRepository
+	// verification emails the tokens of the new emails
+	verification *VerificationHandlers
 }
 
-func NewUsersHandlers(repo *repositories.UsersRepository) *UsersHandlers {
+func NewUsersHandlers(repo *repositories.UsersRepository, verification *VerificationHandlers) *UsersHandlers {
 	return &UsersHandlers{
-		userRepo: repo,
+		userRepo:     repo,
+		verification: verification,
 	}
 }
 
 // Register godoc
 // @Tags Users
 // @Summary      Create user (Register)
+// @Description  Emails the token to verify the email
 // @Accept       json
 // @Produce      json
 // @Param request body views.CreateUserRequest true "User data"
@@ -58,12 +61,19 @@ func (uh *UsersHandlers) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = uh.verification.sendToken(r.Context(), id, cur.Name, cur.Email)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create verification token", err)
+		return
+	}
+
 	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(int(id)))
 }
 
 // UpdateProfile godoc
 // @Tags Users
 // @Summary      Update user profile
+// @Description  A changed email has to be verified again, the token is emailed
 // @Accept       json
 // @Produce      json
 // @Param Authorization header string true "Bearer AccessToken"
@@ -91,6 +101,15 @@ func (uh *UsersHandlers) UpdateProfile(w http.ResponseWriter, r *http.Request, u
 		return
 	}
 
+	// the changed email isn't verified
+	if upr.Email != user.Email {
+		err = uh.verification.sendToken(r.Context(), user.Id, upr.Name, upr.Email)
+		if err != nil {
+			views.RespondWithError(w, http.StatusInternalServerError, "Couldn't create verification token", err)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -148,20 +167,6 @@ func (uh *UsersHandlers) DeleteProfile(w http.ResponseWriter, r *http.Request, u
 // @Router       /v1/users/{id} [put]
 // @Security Bearer
 func (uh *UsersHandlers) Update(w http.ResponseWriter, r *http.Request, user views.User) {
-
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Users == 3 {
-			can_do = true
-			break
-		}
-	}
-
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -202,18 +207,6 @@ func (uh *UsersHandlers) Update(w http.ResponseWriter, r *http.Request, user vie
 // @Router       /v1/users/{id} [get]
 // @Security Bearer
 func (uh *UsersHandlers) GetUser(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Users >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -233,12 +226,14 @@ func (uh *UsersHandlers) GetUser(w http.ResponseWriter, r *http.Request, user vi
 	}
 
 	views.RespondWithJSON(w, http.StatusOK, views.User{
-		Id:          user1.ID,
-		Name:        user1.Name,
-		Email:       user1.Email,
-		DateOfBirth: user1.DateOfBirth,
-		Phone:       user1.Phone,
-		Roles:       roles,
+		Id:            user1.ID,
+		Name:          user1.Name,
+		Email:         user1.Email,
+		DateOfBirth:   user1.DateOfBirth,
+		Phone:         user1.Phone,
+		Roles:         roles,
+		EmailVerified: user1.EmailVerifiedAt.Valid,
+		TwoFactor:     user1.TotpEnabledAt.Valid,
 	})
 }
 
@@ -256,18 +251,6 @@ func (uh *UsersHandlers) GetUser(w http.ResponseWriter, r *http.Request, user vi
 // @Router       /v1/users [get]
 // @Security Bearer
 func (uh *UsersHandlers) GetUsers(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Users >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	users, err := uh.userRepo.DB.GetUsers(r.Context())
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get users", err)
@@ -293,19 +276,6 @@ func (uh *UsersHandlers) GetUsers(w http.ResponseWriter, r *http.Request, user v
 // @Router       /v1/users/{id} [delete]
 // @Security Bearer
 func (uh *UsersHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Users == 3 {
-			can_do = true
-			break
-		}
-	}
-
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/mail"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/throttle"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+// VerificationPolicy restricts the users who haven't verified their email
+type VerificationPolicy string
+
+const (
+	// VerificationOff sends the verification emails without restrictions
+	VerificationOff VerificationPolicy = "off"
+	// VerificationLimit lets the unverified users sign in without their roles
+	VerificationLimit VerificationPolicy = "limit"
+	// VerificationBlock doesn't let the unverified users sign in
+	VerificationBlock VerificationPolicy = "block"
+)
+
+// ParseVerificationPolicy accepts off (the default for the empty string), limit and block
+func ParseVerificationPolicy(policy string) (VerificationPolicy, error) {
+	switch VerificationPolicy(policy) {
+	case "", VerificationOff:
+		return VerificationOff, nil
+	case VerificationLimit, VerificationBlock:
+		return VerificationPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown email verification policy %q", policy)
+	}
+}
+
+// emailVerificationTTL is the lifetime of an email verification token
+const emailVerificationTTL = 24 * time.Hour
+
+type VerificationHandlers struct {
+	DB     *database.Queries
+	Mailer mail.Mailer
+	// VerifyURL is the page of the frontend receiving the token,
+	// the email has only the token without it
+	VerifyURL string
+	// Emails and Addresses limit the resend requests
+	Emails    *throttle.Limiter
+	Addresses *throttle.Limiter
+	now       func() time.Time
+}
+
+func NewVerificationHandlers(db *database.Queries, mailer mail.Mailer, verifyURL string, attempts throttle.Store) *VerificationHandlers {
+	return &VerificationHandlers{
+		DB:        db,
+		Mailer:    mailer,
+		VerifyURL: verifyURL,
+		Emails:    throttle.NewLimiter(attempts, "verification-email:", emailsPolicy),
+		Addresses: throttle.NewLimiter(attempts, "verification-ip:", emailsPolicy),
+		now:       time.Now,
+	}
+}
+
+// Verify godoc
+// @Tags Auth
+// @Summary      Verify email
+// @Description  Confirms the email with the token from the verification email
+// @Accept       json
+// @Produce      json
+// @Param request body views.VerifyEmailRequest true "Token"
+// @Success      204
+// @Failure   	 400  {object} views.ErrorResponse "Invalid or expired token"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't verify email"
+// @Router       /v1/auth/verify-email [post]
+func (vh *VerificationHandlers) Verify(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	ver := views.VerifyEmailRequest{}
+	err := decoder.Decode(&ver)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of VerifyEmailRequest", err)
+		return
+	}
+
+	token, err := vh.DB.UseEmailVerificationToken(r.Context(), database.UseEmailVerificationTokenParams{
+		TokenHash: hashToken(ver.Token),
+		Now:       vh.now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid or expired token", err)
+		return
+	}
+
+	// the token verifies the email it was sent to, not a changed one
+	verified, err := vh.DB.VerifyEmail(r.Context(), database.VerifyEmailParams{
+		ID:    token.UserID,
+		Email: token.Email,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't verify email", err)
+		return
+	}
+	if verified == 0 {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid or expired token", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Resend godoc
+// @Tags Auth
+// @Summary      Resend verification email
+// @Description  Sends a new verification token, the previous ones stop working.
+// @Description  The answer is the same for unknown and verified emails.
+// @Accept       json
+// @Produce      json
+// @Param request body views.ResendVerificationRequest true "Email"
+// @Success      202
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 429  {object} views.ErrorResponse "Too many requests, see Retry-After"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't check requests"
+// @Router       /v1/auth/resend-verification [post]
+func (vh *VerificationHandlers) Resend(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	rvr := views.ResendVerificationRequest{}
+	err := decoder.Decode(&rvr)
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Error parsing JSON of ResendVerificationRequest", err)
+		return
+	}
+
+	email := normalizeEmail(rvr.Email)
+	ip := clientIP(r)
+	wait, err := throttleAttempt(r.Context(), vh.Addresses, ip, vh.Emails, email)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't save resend request", err)
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		views.RespondWithError(w, http.StatusTooManyRequests, "Too many resend requests, try again later", nil)
+		return
+	}
+
+	// the user is looked up and emailed after the answer,
+	// so that its time doesn't reveal the unverified emails
+	go vh.resendToken(context.WithoutCancel(r.Context()), email)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// resendToken sends a new verification token to the unverified user with the email,
+// the unknown and verified emails are ignored
+func (vh *VerificationHandlers) resendToken(ctx context.Context, email string) {
+	user, err := vh.DB.GetUserBySignInEmail(ctx, email)
+	if err != nil || user.EmailVerifiedAt.Valid {
+		return
+	}
+
+	err = vh.sendToken(ctx, user.ID, user.Name, user.Email)
+	if err != nil {
+		log.Printf("Couldn't create verification token of user %d: %s", user.ID, err)
+	}
+}
+
+// sendToken replaces the verification tokens of the user with a new one
+// and emails it, the delivery failures are only logged
+func (vh *VerificationHandlers) sendToken(ctx context.Context, userID int64, name, email string) error {
+	token, err := makeRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	err = vh.DB.DeleteEmailVerificationTokensOfUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = vh.DB.CreateEmailVerificationToken(ctx, database.CreateEmailVerificationTokenParams{
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		Email:     email,
+		ExpiresAt: vh.now().UTC().Add(emailVerificationTTL).Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := token
+	if vh.VerifyURL != "" {
+		link = vh.VerifyURL + "?token=" + url.QueryEscape(token)
+	}
+	err = vh.Mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Verify your Ozinshe email",
+		Body: fmt.Sprintf(`Hello, %s!
+
+Confirm that %s is your email within a day:
+
+%s
+
+If you haven't signed up for Ozinshe, ignore this email.
+`, name, email, link),
+	})
+	if err != nil {
+		log.Printf("Couldn't send verification email to user %d: %s", userID, err)
+	}
+	return nil
+}
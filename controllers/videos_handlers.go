@@ -1,112 +1,98 @@
 package controllers
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
-	"os"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Bayan2019/go-ozinshe/hls"
+	"github.com/Bayan2019/go-ozinshe/mp4"
+	"github.com/Bayan2019/go-ozinshe/repositories"
 	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/subtitles"
 	"github.com/Bayan2019/go-ozinshe/views"
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 )
 
 type VideosHandlers struct {
-	DB  *database.Queries
-	Dir string
+	DB       *database.Queries
+	Storage  storage.Storage
+	repo     *repositories.ProjectsRepository
+	packager *hls.Packager
 }
 
-func NewVideosHandlers(db *database.Queries, dir string) *VideosHandlers {
+// NewVideosHandlers packages the uploaded videos for HLS with packager,
+// without it the videos stay pending
+func NewVideosHandlers(db *database.Queries, repo *repositories.ProjectsRepository, store storage.Storage, packager *hls.Packager) *VideosHandlers {
 	return &VideosHandlers{
-		DB:  db,
-		Dir: dir,
+		DB:       db,
+		Storage:  store,
+		repo:     repo,
+		packager: packager,
 	}
 }
 
-// Display godoc
+// Play godoc
 // @Tags Videos
-// @Summary      Display Video
+// @Summary      Play Video
+// @Description  Streams the video, supports Range and conditional requests
 // @Accept       json
-// @Produce      application/octet-stream
+// @Produce      video/mp4
 // @Param Authorization header string true "Bearer AccessToken"
-// @Param id path int true "id"
+// @Param id path string true "file name"
+// @Param Range header string false "bytes=start-end"
 // @Success      200  "OK"
-// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Success      206  "Partial Content"
+// @Success      304  "Not Modified"
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
-// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
-// @Failure   	 500  {object} views.ErrorResponse "No Permission" "Couldn't get User Middleware"
+// @Failure   	 404  {object} views.ErrorResponse "Not found File"
+// @Failure   	 416  "Range Not Satisfiable"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't open file"
 // @Router       /v1/projects/videos/play/{id} [get]
 // @Security Bearer
 func (vh *VideosHandlers) Play(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-	// You can get the string value of the path parameter like in Go
-	// with the http.Request.PathValue method.
-	id := chi.URLParam(r, "id")
-	// enableCors(&w)
-	w.Header().Set("Content-Type", "video/mp4")
-	// w.ResponseWriter.WriteHeader(http.StatusOK)
-	http.ServeFile(w, r, fmt.Sprintf("%s%s", vh.Dir, id))
+	serveMedia(w, r, vh.Storage, chi.URLParam(r, "id"), false)
 }
 
 // Get godoc
 // @Tags Videos
-// @Summary      Get Video
+// @Summary      Download Video
+// @Description  Streams the video as an attachment, supports Range and conditional requests
 // @Accept       json
 // @Produce      application/octet-stream
 // @Param Authorization header string true "Bearer AccessToken"
-// @Param id path int true "id"
+// @Param id path string true "file name"
+// @Param Range header string false "bytes=start-end"
 // @Success      200  "OK"
-// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Success      206  "Partial Content"
+// @Success      304  "Not Modified"
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
-// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
-// @Failure   	 500  {object} views.ErrorResponse "can't read the video"
+// @Failure   	 404  {object} views.ErrorResponse "Not found File"
+// @Failure   	 416  "Range Not Satisfiable"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't open file"
 // @Router       /v1/projects/videos/{id} [get]
 // @Security Bearer
 func (vh *VideosHandlers) Get(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects >= 2 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
-	id := chi.URLParam(r, "id")
-	byteFile, err := os.ReadFile(fmt.Sprintf("%s%s", vh.Dir, id))
-	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "can't read the video", err)
-		return
-	}
-	w.Header().Set("Content-Type", "video/mp4")
-	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", id))
-	w.WriteHeader(http.StatusOK)
-	w.Write(byteFile)
+	serveMedia(w, r, vh.Storage, chi.URLParam(r, "id"), true)
 }
 
 // Create godoc
 // @Tags Videos
 // @Summary      Create Video
+// @Description  Checks that the file is an MP4 video and stores its duration and resolution,
+// @Description  the duration of the project is filled if it isn't set
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param Authorization header string true "Bearer AccessToken"
@@ -115,7 +101,7 @@ func (vh *VideosHandlers) Get(w http.ResponseWriter, r *http.Request, user views
 // @Param serie formData int true "serie"
 // @Param video formData file true "video"
 // @Success      200  {object} views.ResponseIdStr  "OK"
-// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data or video"
 // @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
 // @Failure   	 403  {object} views.ErrorResponse "No Permission"
 // @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
@@ -123,20 +109,8 @@ func (vh *VideosHandlers) Get(w http.ResponseWriter, r *http.Request, user views
 // @Router       /v1/projects/videos [post]
 // @Security Bearer
 func (vh *VideosHandlers) Upload(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
-	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
-		return
-	}
-
 	// Set an upload limit of 1 GB (1 << 30 bytes)
-	const uploadLimit = 1 << 26
+	const uploadLimit = 1 << 30
 	// using http.MaxBytesReader
 	r.Body = http.MaxBytesReader(w, r.Body, uploadLimit)
 
@@ -160,21 +134,6 @@ func (vh *VideosHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 		return
 	}
 
-	fileName := fmt.Sprintf("%s.mp4", uuid.NewString())
-	fpath := fmt.Sprintf("%s%s", vh.Dir, fileName)
-	// Use os.Create to create the new file
-	dst, err := os.Create(fpath)
-	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Unable to create file on server", err)
-		return
-	}
-	defer dst.Close()
-
-	if _, err = io.Copy(dst, file); err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
-		return
-	}
-
 	project_id, err := strconv.Atoi(r.FormValue("project_id"))
 	if err != nil {
 		views.RespondWithError(w, http.StatusBadRequest, "Invalid project_id", err)
@@ -191,12 +150,11 @@ func (vh *VideosHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 		return
 	}
 
-	err = vh.DB.AddVideo2Series(r.Context(), database.AddVideo2SeriesParams{
-		ID:        fileName,
-		ProjectID: int64(project_id),
-		Season:    int64(season),
-		Serie:     int64(serie),
-	})
+	fileName, err := vh.saveVideo(r.Context(), file, header.Size, int64(project_id), int64(season), int64(serie))
+	if errors.Is(err, mp4.ErrInvalid) {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid video", err)
+		return
+	}
 	if err != nil {
 		views.RespondWithError(w, http.StatusInternalServerError, "Error saving file", err)
 		return
@@ -207,6 +165,44 @@ func (vh *VideosHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 	})
 }
 
+// saveVideo checks that the file is an MP4 video and stores it
+// with its duration and resolution. The video becomes the episode
+// of the season, both are created if needed, and fills the duration
+// of the project if it isn't set yet.
+func (vh *VideosHandlers) saveVideo(ctx context.Context, file io.ReaderAt, size, project_id, season, serie int64) (string, error) {
+	info, err := mp4.Probe(file, size)
+	if err != nil {
+		return "", err
+	}
+	duration := int64(info.Duration.Round(time.Second) / time.Second)
+
+	fileName := fmt.Sprintf("%s.mp4", uuid.NewString())
+	err = vh.Storage.Put(ctx, fileName, io.NewSectionReader(file, 0, size), size, "video/mp4")
+	if err != nil {
+		return "", err
+	}
+
+	// the rows are saved at once, so a retried upload doesn't leave a duplicate video
+	err = vh.repo.AddVideo(ctx, database.AddVideo2SeriesParams{
+		ID:             fileName,
+		ProjectID:      project_id,
+		Season:         season,
+		Serie:          serie,
+		DurationInSecs: duration,
+		Width:          int64(info.Width),
+		Height:         int64(info.Height),
+	}, int64(info.Duration.Round(time.Minute)/time.Minute))
+	if err != nil {
+		// the file without the row would be left for the collector
+		return "", errors.Join(err, vh.Storage.Delete(ctx, fileName))
+	}
+
+	if vh.packager != nil {
+		vh.packager.Notify()
+	}
+	return fileName, nil
+}
+
 // Delete godoc
 // @Tags Videos
 // @Summary      Delete Video
@@ -223,24 +219,32 @@ func (vh *VideosHandlers) Upload(w http.ResponseWriter, r *http.Request, user vi
 // @Router       /v1/projects/videos/{id} [delete]
 // @Security Bearer
 func (vh *VideosHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
-	can_do := false
-	for _, role := range user.Roles {
-		if role.Projects == 3 {
-			can_do = true
-			break
-		}
+	id := chi.URLParam(r, "id")
+	err := vh.Storage.Delete(r.Context(), id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error deleting file", err)
+		return
 	}
-	if !can_do {
-		views.RespondWithError(w, http.StatusForbidden, "don't have permission", errors.New("no Permission"))
+	err = hls.Remove(r.Context(), vh.Storage, id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error deleting HLS files", err)
+		return
+	}
+	err = subtitles.Remove(r.Context(), vh.Storage, id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error deleting subtitles", err)
 		return
 	}
 
-	id := chi.URLParam(r, "id")
-	fpath := fmt.Sprintf("%s%s", vh.Dir, id)
-	// Use os.Create to create the new file
-	err := os.Remove(fpath)
+	err = vh.DB.UnsetVideoOfEpisodes(r.Context(), sql.NullString{String: id, Valid: true})
 	if err != nil {
-		views.RespondWithError(w, http.StatusInternalServerError, "Error deleting file", err)
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't unset video of episodes", err)
+		return
+	}
+
+	err = vh.DB.DeleteSubtitlesOfVideo(r.Context(), id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Error deleting subtitles", err)
 		return
 	}
 
@@ -254,3 +258,96 @@ func (vh *VideosHandlers) Delete(w http.ResponseWriter, r *http.Request, user vi
 		ID: id,
 	})
 }
+
+// Stream godoc
+// @Tags Videos
+// @Summary      Stream Video with HLS
+// @Description  {file} is master.m3u8 or a playlist or segment of a rendition like 720p/index.m3u8,
+// @Description  supports Range and conditional requests
+// @Accept       json
+// @Produce      application/vnd.apple.mpegurl
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "video id"
+// @Param file path string true "file of the HLS package"
+// @Success      200  "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Video or File"
+// @Failure   	 409  {object} views.ErrorResponse "Video isn't packaged"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't open file"
+// @Router       /v1/projects/videos/{id}/hls/{file} [get]
+// @Security Bearer
+func (vh *VideosHandlers) Stream(w http.ResponseWriter, r *http.Request, user views.User) {
+	video, err := vh.DB.GetVideoById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find video", err)
+		return
+	}
+	if video.HlsStatus != hls.StatusReady {
+		views.RespondWithError(w, http.StatusConflict, fmt.Sprintf("Video isn't packaged, status is %s", video.HlsStatus), nil)
+		return
+	}
+
+	// the master playlist or a file of a rendition
+	name := chi.URLParam(r, "*")
+	if dir, _ := path.Split(name); dir != "" && !isRendition(strings.TrimSuffix(dir, "/")) {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find file", nil)
+		return
+	}
+
+	serveMedia(w, r, vh.Storage, hls.Prefix(video.ID)+name, false)
+}
+
+// Repackage godoc
+// @Tags Videos
+// @Summary      Package Video for HLS again
+// @Description  Status of packaging is hls_status of the video
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path string true "video id"
+// @Success      202  {object} views.ResponseIdStr "Accepted"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Video"
+// @Failure   	 409  {object} views.ErrorResponse "Video is being packaged"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't set status"
+// @Router       /v1/projects/videos/{id}/hls [post]
+// @Security Bearer
+func (vh *VideosHandlers) Repackage(w http.ResponseWriter, r *http.Request, user views.User) {
+	video, err := vh.DB.GetVideoById(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find video", err)
+		return
+	}
+	if video.HlsStatus == hls.StatusProcessing {
+		views.RespondWithError(w, http.StatusConflict, "Video is being packaged", nil)
+		return
+	}
+
+	err = vh.DB.SetVideoHlsStatus(r.Context(), database.SetVideoHlsStatusParams{
+		ID:        video.ID,
+		HlsStatus: hls.StatusPending,
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't set status", err)
+		return
+	}
+
+	if vh.packager != nil {
+		vh.packager.Notify()
+	}
+
+	views.RespondWithJSON(w, http.StatusAccepted, views.ResponseIdStr{
+		ID: video.ID,
+	})
+}
+
+func isRendition(name string) bool {
+	for _, rendition := range hls.Renditions {
+		if rendition.Name == name {
+			return true
+		}
+	}
+	return false
+}
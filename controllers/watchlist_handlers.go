@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+)
+
+type WatchlistHandlers struct {
+	repo *repositories.ProjectsRepository
+}
+
+func NewWatchlistHandlers(repo *repositories.ProjectsRepository) *WatchlistHandlers {
+	return &WatchlistHandlers{
+		repo: repo,
+	}
+}
+
+// GetAll godoc
+// @Tags Watchlist
+// @Summary      Get Watchlist Projects of User profile
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Success      200  {array} views.Project "OK"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't Get Watchlist"
+// @Router       /v1/users/profile/watchlist [get]
+// @Security Bearer
+func (wh *WatchlistHandlers) GetAll(w http.ResponseWriter, r *http.Request, user views.User) {
+	projects, err := wh.repo.GetWatchlistOfUser(r.Context(), user.Id)
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't get watchlist", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, projects)
+}
+
+// Add godoc
+// @Tags Watchlist
+// @Summary      Add Project to Watchlist of User profile
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      201  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found Project"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't Add to Watchlist"
+// @Router       /v1/users/profile/watchlist/{id} [post]
+// @Security Bearer
+func (wh *WatchlistHandlers) Add(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	_, err = wh.repo.DB.GetProjectById(r.Context(), int64(project_id))
+	if err != nil {
+		views.RespondWithError(w, http.StatusNotFound, "Couldn't find project", err)
+		return
+	}
+
+	err = wh.repo.DB.AddProject2Watchlist(r.Context(), database.AddProject2WatchlistParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't add project to watchlist", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusCreated, views.NewResponseId(project_id))
+}
+
+// Delete godoc
+// @Tags Watchlist
+// @Summary      Remove Project from Watchlist of User profile
+// @Accept       json
+// @Produce      json
+// @Param Authorization header string true "Bearer AccessToken"
+// @Param id path int true "project id"
+// @Success      200  {object} views.ResponseId "OK"
+// @Failure   	 400  {object} views.ErrorResponse "Invalid data"
+// @Failure   	 401  {object} views.ErrorResponse "No token Middleware"
+// @Failure   	 403  {object} views.ErrorResponse "No Permission"
+// @Failure   	 404  {object} views.ErrorResponse "Not found User Middleware"
+// @Failure   	 500  {object} views.ErrorResponse "Couldn't Remove from Watchlist"
+// @Router       /v1/users/profile/watchlist/{id} [delete]
+// @Security Bearer
+func (wh *WatchlistHandlers) Delete(w http.ResponseWriter, r *http.Request, user views.User) {
+	project_id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		views.RespondWithError(w, http.StatusBadRequest, "Invalid id", err)
+		return
+	}
+
+	err = wh.repo.DB.DeleteProjectFromWatchlist(r.Context(), database.DeleteProjectFromWatchlistParams{
+		UserID:    user.Id,
+		ProjectID: int64(project_id),
+	})
+	if err != nil {
+		views.RespondWithError(w, http.StatusInternalServerError, "Couldn't remove project from watchlist", err)
+		return
+	}
+
+	views.RespondWithJSON(w, http.StatusOK, views.NewResponseId(project_id))
+}
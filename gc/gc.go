@@ -0,0 +1,237 @@
+// Package gc reconciles the media files of the storage with the rows
+// of the database: it finds the files no row refers to (orphans)
+// and the rows whose files are missing.
+package gc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/hls"
+	"github.com/Bayan2019/go-ozinshe/imaging"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+// DefaultGracePeriod protects the files of the uploads in progress,
+// they are stored before their rows are inserted
+const DefaultGracePeriod = 24 * time.Hour
+
+type Options struct {
+	// GracePeriod of the orphans, younger ones are reported but never deleted
+	GracePeriod time.Duration
+	// Delete removes the orphans older than GracePeriod
+	Delete bool
+}
+
+// Missing is a row whose file isn't in the storage
+type Missing struct {
+	Table string
+	ID    string
+	File  string
+}
+
+type Report struct {
+	Orphans []storage.ObjectInfo
+	// Deleted orphans
+	Deleted []string
+	Missing []Missing
+}
+
+// Print writes a line per orphan and missing file
+func (r Report) Print(w io.Writer) {
+	deleted := map[string]bool{}
+	for _, name := range r.Deleted {
+		deleted[name] = true
+	}
+	for _, orphan := range r.Orphans {
+		action := "orphan"
+		if deleted[orphan.Name] {
+			action = "deleted"
+		}
+		fmt.Fprintf(w, "%s %s %d bytes, modified %s\n", action, orphan.Name, orphan.Size, orphan.ModTime.UTC().Format(time.RFC3339))
+	}
+	for _, missing := range r.Missing {
+		fmt.Fprintf(w, "missing %s of %s %s\n", missing.File, missing.Table, missing.ID)
+	}
+	fmt.Fprintf(w, "%d orphans, %d deleted, %d missing\n", len(r.Orphans), len(r.Deleted), len(r.Missing))
+}
+
+// Collector runs the reconciliation on demand or on a schedule
+type Collector struct {
+	DB      *database.Queries
+	Storage storage.Storage
+	Options Options
+	now     func() time.Time
+}
+
+func NewCollector(db *database.Queries, store storage.Storage, options Options) *Collector {
+	return &Collector{
+		DB:      db,
+		Storage: store,
+		Options: options,
+		now:     time.Now,
+	}
+}
+
+// Start runs the reconciliation every interval until ctx is done
+// and logs the reports
+func (c *Collector) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			report, err := c.Run(ctx)
+			if err != nil {
+				log.Printf("Couldn't collect orphaned media: %s", err)
+				continue
+			}
+			if len(report.Orphans) > 0 || len(report.Missing) > 0 {
+				report.Print(log.Writer())
+			}
+		}
+	}()
+}
+
+// references of the rows to the files
+type references struct {
+	files map[string]bool
+	// prefixes of the HLS packages of the videos
+	// and of the parts of the resumable uploads
+	prefixes map[string]bool
+	rows     []Missing
+}
+
+func (refs *references) add(table, id, file string) {
+	refs.files[file] = true
+	refs.rows = append(refs.rows, Missing{Table: table, ID: id, File: file})
+}
+
+// referenced reports whether the object is a file of a row
+// or is under a prefix of a video
+func (refs *references) referenced(name string) bool {
+	if refs.files[name] {
+		return true
+	}
+	for i := 0; ; {
+		j := strings.Index(name[i:], "/")
+		if j < 0 {
+			return false
+		}
+		i += j + 1
+		if refs.prefixes[name[:i]] {
+			return true
+		}
+	}
+}
+
+// Run compares the objects of the storage with the rows.
+// The rows are read before the objects are listed,
+// so the file of a row inserted meanwhile is young and kept.
+func (c *Collector) Run(ctx context.Context) (Report, error) {
+	refs, err := c.references(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	objects, err := c.Storage.List(ctx, "")
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Orphans: []storage.ObjectInfo{},
+		Deleted: []string{},
+		Missing: []Missing{},
+	}
+	exists := map[string]bool{}
+	now := c.now()
+	for _, object := range objects {
+		exists[object.Name] = true
+		if refs.referenced(object.Name) {
+			continue
+		}
+		report.Orphans = append(report.Orphans, object)
+		if !c.Options.Delete || now.Sub(object.ModTime) < c.Options.GracePeriod {
+			continue
+		}
+		err := c.Storage.Delete(ctx, object.Name)
+		if err != nil {
+			log.Printf("Couldn't delete orphan %s: %s", object.Name, err)
+			continue
+		}
+		report.Deleted = append(report.Deleted, object.Name)
+	}
+
+	for _, row := range refs.rows {
+		if !exists[row.File] {
+			report.Missing = append(report.Missing, row)
+		}
+	}
+	return report, nil
+}
+
+func (c *Collector) references(ctx context.Context) (*references, error) {
+	refs := &references{
+		files:    map[string]bool{},
+		prefixes: map[string]bool{},
+		rows:     []Missing{},
+	}
+
+	images, err := c.DB.GetImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		refs.add("images", image.ID, image.ID)
+		// older images have no variants, they aren't missing
+		for _, variant := range imaging.Variants {
+			refs.files[imaging.Name(image.ID, variant.Name)] = true
+		}
+	}
+
+	videos, err := c.DB.GetVideos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, video := range videos {
+		refs.add("videos", video.ID, video.ID)
+		refs.prefixes[hls.Prefix(video.ID)] = true
+	}
+
+	rows, err := c.DB.GetSubtitles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		refs.add("subtitles", fmt.Sprint(row.ID), row.File)
+	}
+
+	photos, err := c.DB.GetPeoplePhotos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, photo := range photos {
+		refs.add("people", fmt.Sprint(photo.ID), photo.Photo)
+	}
+
+	uploads, err := c.DB.GetUploadIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range uploads {
+		// the parts are stored by controllers.UploadsHandlers
+		refs.prefixes["uploads/"+id+"/"] = true
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,57 @@
+package gc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+func TestReferenced(t *testing.T) {
+	refs := &references{
+		files:    map[string]bool{"video.mp4": true, "variants/card/cover.png": true},
+		prefixes: map[string]bool{"hls/video.mp4/": true},
+	}
+
+	for name, want := range map[string]bool{
+		"video.mp4":                     true,
+		"variants/card/cover.png":       true,
+		"hls/video.mp4/master.m3u8":     true,
+		"hls/video.mp4/720p/index.m3u8": true,
+		"hls/other.mp4/master.m3u8":     false,
+		"hls/video.mp4":                 false,
+		"hls/video.mp4.old/master.m3u8": false,
+		"variants/thumbnail/cover.png":  false,
+		"subtitles/video.mp4/kk.vtt":    false,
+	} {
+		if got := refs.referenced(name); got != want {
+			t.Errorf("referenced(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestReportPrint(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	report := Report{
+		Orphans: []storage.ObjectInfo{
+			{Name: "old.png", Size: 10, ModTime: modTime},
+			{Name: "young.png", Size: 20, ModTime: modTime},
+		},
+		Deleted: []string{"old.png"},
+		Missing: []Missing{{Table: "videos", ID: "lost.mp4", File: "lost.mp4"}},
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	want := strings.Join([]string{
+		"deleted old.png 10 bytes, modified 2024-05-01T12:00:00Z",
+		"orphan young.png 20 bytes, modified 2024-05-01T12:00:00Z",
+		"missing lost.mp4 of videos lost.mp4",
+		"2 orphans, 1 deleted, 1 missing",
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Errorf("Print() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
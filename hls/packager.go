@@ -0,0 +1,321 @@
+// Package hls packages uploaded MP4 videos into HLS renditions
+// with a master playlist using ffmpeg.
+package hls
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+// Statuses of packaging of a video
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusReady      = "ready"
+	StatusFailed     = "failed"
+)
+
+// MasterPlaylist is the name of the playlist listing the renditions
+const MasterPlaylist = "master.m3u8"
+
+type Rendition struct {
+	Name         string
+	Height       int
+	VideoBitrate int // bits per second
+	AudioBitrate int // bits per second
+	Level        int // H.264 level times 10
+}
+
+// Renditions of the videos, the renditions taller than the video are skipped
+var Renditions = []Rendition{
+	{Name: "360p", Height: 360, VideoBitrate: 800_000, AudioBitrate: 96_000, Level: 30},
+	{Name: "720p", Height: 720, VideoBitrate: 2_800_000, AudioBitrate: 128_000, Level: 31},
+	{Name: "1080p", Height: 1080, VideoBitrate: 5_000_000, AudioBitrate: 192_000, Level: 40},
+}
+
+// stream is a rendition of a video with its resolution,
+// the resolution is unknown for the videos uploaded before it was stored
+type stream struct {
+	Rendition
+	Width, Height int
+}
+
+// streamsOf returns the renditions of a width x height video, it isn't upscaled,
+// so a video lower than every rendition keeps its height in the lowest one
+func streamsOf(width, height int) []stream {
+	streams := []stream{}
+	for _, rendition := range Renditions {
+		if width <= 0 || height <= 0 {
+			streams = append(streams, stream{Rendition: rendition})
+			continue
+		}
+		if rendition.Height > height && len(streams) > 0 {
+			break
+		}
+		h := min(rendition.Height, height)
+		// the even width of scale=-2, rounded like ffmpeg does
+		w := (width*h + height) / (2 * height) * 2
+		streams = append(streams, stream{Rendition: rendition, Width: w, Height: h})
+		if h == height {
+			break
+		}
+	}
+	return streams
+}
+
+// codecs of the HLS streams: H.264 Main profile and AAC-LC
+func (s stream) codecs() string {
+	return fmt.Sprintf("avc1.4d40%02x,mp4a.40.2", s.Level)
+}
+
+// segmentDuration of HLS segments in seconds
+const segmentDuration = 6
+
+// Packager packages the pending videos one by one in the background
+type Packager struct {
+	DB      *database.Queries
+	Storage storage.Storage
+	FFmpeg  string
+	wake    chan struct{}
+}
+
+func NewPackager(db *database.Queries, store storage.Storage, ffmpeg string) *Packager {
+	return &Packager{
+		DB:      db,
+		Storage: store,
+		FFmpeg:  ffmpeg,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Prefix of the names of the packaged video in the storage
+func Prefix(videoID string) string {
+	return "hls/" + videoID + "/"
+}
+
+// Remove removes the packaged video, a missing one counts as removed
+func Remove(ctx context.Context, store storage.Storage, videoID string) error {
+	return store.DeletePrefix(ctx, Prefix(videoID))
+}
+
+// Start packages the pending videos until ctx is done.
+// Videos left processing by a previous run are packaged again.
+func (p *Packager) Start(ctx context.Context) error {
+	err := p.DB.ResetProcessingVideos(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			p.packagePending(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.wake:
+			}
+		}
+	}()
+	return nil
+}
+
+// Notify tells the packager there are new pending videos
+func (p *Packager) Notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Packager) packagePending(ctx context.Context) {
+	for ctx.Err() == nil {
+		video, err := p.DB.GetNextPendingVideo(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		status, message := StatusReady, ""
+		err = p.setStatus(ctx, video.ID, StatusProcessing, "")
+		if err == nil {
+			err = p.Package(ctx, video)
+		}
+		if err != nil {
+			log.Printf("Couldn't package video %s: %s", video.ID, err)
+			status, message = StatusFailed, err.Error()
+		}
+		if ctx.Err() != nil {
+			// interrupted videos are packaged again on the next start
+			return
+		}
+
+		err = p.setStatus(ctx, video.ID, status, message)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+	}
+}
+
+func (p *Packager) setStatus(ctx context.Context, videoID, status, message string) error {
+	return p.DB.SetVideoHlsStatus(ctx, database.SetVideoHlsStatusParams{
+		ID:        videoID,
+		HlsStatus: status,
+		HlsError:  message,
+	})
+}
+
+// Package segments the video into the renditions up to its height and writes the master playlist.
+// ffmpeg works on a local copy of the video.
+// The previous packaging of the video is replaced only on success.
+func (p *Packager) Package(ctx context.Context, video database.Video) error {
+	videoID := video.ID
+
+	tmp, err := os.MkdirTemp("", "ozinshe-hls-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	input := filepath.Join(tmp, "input.mp4")
+	err = p.download(ctx, videoID, input)
+	if err != nil {
+		return err
+	}
+
+	out := filepath.Join(tmp, "out")
+	streams := streamsOf(int(video.Width), int(video.Height))
+	for _, stream := range streams {
+		dir := filepath.Join(out, stream.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, p.FFmpeg, ffmpegArgs(input, dir, stream.Rendition)...)
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("ffmpeg %s: %w: %s", stream.Name, err, strings.TrimSpace(stderr.String()))
+		}
+	}
+
+	err = os.WriteFile(filepath.Join(out, MasterPlaylist), []byte(masterPlaylist(streams)), 0o644)
+	if err != nil {
+		return err
+	}
+
+	err = Remove(ctx, p.Storage, videoID)
+	if err != nil {
+		return err
+	}
+	return p.upload(ctx, out, Prefix(videoID))
+}
+
+func (p *Packager) download(ctx context.Context, videoID, path string) error {
+	object, err := p.Storage.Open(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, object); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// upload puts the files of the directory under the prefix,
+// the master playlist goes last
+func (p *Packager) upload(ctx context.Context, dir, prefix string) error {
+	names := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if name != MasterPlaylist {
+			names = append(names, filepath.ToSlash(name))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range append(names, MasterPlaylist) {
+		file, err := os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+		err = storage.PutFile(ctx, p.Storage, prefix+name, file, contentTypes[filepath.Ext(name)])
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var contentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+}
+
+func ffmpegArgs(input, out string, rendition Rendition) []string {
+	return []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", input,
+		"-vf", fmt.Sprintf("scale=-2:'min(%d,ih)'", rendition.Height),
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+		"-level:v", fmt.Sprintf("%d.%d", rendition.Level/10, rendition.Level%10),
+		"-b:v", fmt.Sprint(rendition.VideoBitrate),
+		"-maxrate", fmt.Sprint(rendition.VideoBitrate * 107 / 100),
+		"-bufsize", fmt.Sprint(rendition.VideoBitrate * 3 / 2),
+		// keyframes on segment boundaries
+		"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", segmentDuration),
+		"-c:a", "aac", "-ac", "2", "-b:a", fmt.Sprint(rendition.AudioBitrate),
+		"-f", "hls",
+		"-hls_time", fmt.Sprint(segmentDuration),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(out, "segment_%04d.ts"),
+		filepath.Join(out, "index.m3u8"),
+	}
+}
+
+func masterPlaylist(streams []stream) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, stream := range streams {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d", stream.VideoBitrate+stream.AudioBitrate)
+		if stream.Width > 0 {
+			fmt.Fprintf(&b, ",RESOLUTION=%dx%d", stream.Width, stream.Height)
+		}
+		fmt.Fprintf(&b, ",CODECS=\"%s\"\n%s/index.m3u8\n", stream.codecs(), stream.Name)
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package hls
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+// fakeFFmpeg writes a script that creates the playlist and a segment
+// at the paths given by the last arguments, like ffmpeg does
+func fakeFFmpeg(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPackage(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewLocal(t.TempDir())
+	if err := store.Put(ctx, "video.mp4", strings.NewReader("mp4"), 3, "video/mp4"); err != nil {
+		t.Fatal(err)
+	}
+	exists := func(name string) bool {
+		object, err := store.Open(ctx, name)
+		if err != nil {
+			return false
+		}
+		object.Close()
+		return true
+	}
+
+	p := NewPackager(nil, store, fakeFFmpeg(t, `for last; do :; done
+out=$(dirname "$last")
+echo "#EXTM3U" > "$last"
+echo "ts" > "$out/segment_0000.ts"
+`))
+	if err := p.Package(ctx, database.Video{ID: "video.mp4"}); err != nil {
+		t.Fatal(err)
+	}
+
+	object, err := store.Open(ctx, Prefix("video.mp4")+MasterPlaylist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, _ := io.ReadAll(object)
+	object.Close()
+	for _, rendition := range Renditions {
+		if !strings.Contains(string(master), rendition.Name+"/index.m3u8") {
+			t.Errorf("master playlist misses %s:\n%s", rendition.Name, master)
+		}
+		for _, file := range []string{"index.m3u8", "segment_0000.ts"} {
+			if !exists(Prefix("video.mp4") + rendition.Name + "/" + file) {
+				t.Errorf("%s/%s isn't uploaded", rendition.Name, file)
+			}
+		}
+	}
+
+	// a 480p video isn't upscaled
+	if err := p.Package(ctx, database.Video{ID: "video.mp4", Width: 854, Height: 480}); err != nil {
+		t.Fatal(err)
+	}
+	object, err = store.Open(ctx, Prefix("video.mp4")+MasterPlaylist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, _ = io.ReadAll(object)
+	object.Close()
+	if !strings.Contains(string(master), "360p/index.m3u8") || strings.Contains(string(master), "720p") || exists(Prefix("video.mp4")+"720p/index.m3u8") {
+		t.Errorf("master playlist of 480p video:\n%s", master)
+	}
+
+	// a failed packaging keeps the previous one
+	p.FFmpeg = fakeFFmpeg(t, "echo 'Invalid data found' >&2\nexit 1\n")
+	err = p.Package(ctx, database.Video{ID: "video.mp4"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid data found") {
+		t.Errorf("Package() error = %v, want ffmpeg error", err)
+	}
+	if !exists(Prefix("video.mp4") + MasterPlaylist) {
+		t.Error("previous packaging is removed")
+	}
+
+	if err := p.Package(ctx, database.Video{ID: "missing.mp4"}); !storage.IsNotExist(err) {
+		t.Errorf("Package() of missing video error = %v", err)
+	}
+
+	if err := Remove(ctx, store, "video.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if exists(Prefix("video.mp4") + MasterPlaylist) {
+		t.Error("packaged video isn't removed")
+	}
+	if !exists("video.mp4") {
+		t.Error("video is removed with its packaging")
+	}
+}
+
+func TestStreamsOf(t *testing.T) {
+	tests := []struct {
+		width, height int
+		want          string
+	}{
+		{1920, 1080, "360p 640x360, 720p 1280x720, 1080p 1920x1080"},
+		{3840, 2160, "360p 640x360, 720p 1280x720, 1080p 1920x1080"},
+		{1280, 720, "360p 640x360, 720p 1280x720"},
+		{854, 480, "360p 640x360"},
+		{640, 240, "360p 640x240"},
+		{1080, 1920, "360p 202x360, 720p 406x720, 1080p 608x1080"},
+		{0, 0, "360p 0x0, 720p 0x0, 1080p 0x0"},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+		for _, s := range streamsOf(tt.width, tt.height) {
+			got = append(got, fmt.Sprintf("%s %dx%d", s.Name, s.Width, s.Height))
+		}
+		if strings.Join(got, ", ") != tt.want {
+			t.Errorf("streamsOf(%d, %d) = %s, want %s", tt.width, tt.height, strings.Join(got, ", "), tt.want)
+		}
+	}
+}
+
+func TestMasterPlaylist(t *testing.T) {
+	got := masterPlaylist([]stream{
+		{Rendition: Renditions[0], Width: 640, Height: 360},
+		{Rendition: Renditions[1], Width: 1280, Height: 720},
+		{Rendition: Renditions[2]},
+	})
+	want := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-STREAM-INF:BANDWIDTH=896000,RESOLUTION=640x360,CODECS="avc1.4d401e,mp4a.40.2"
+360p/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=2928000,RESOLUTION=1280x720,CODECS="avc1.4d401f,mp4a.40.2"
+720p/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=5192000,CODECS="avc1.4d4028,mp4a.40.2"
+1080p/index.m3u8
+`
+	if got != want {
+		t.Errorf("masterPlaylist() =\n%s\nwant\n%s", got, want)
+	}
+}
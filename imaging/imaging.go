@@ -0,0 +1,174 @@
+// Package imaging decodes uploaded JPEG and PNG images
+// and stores their resized variants.
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+// Variant is a resized copy of an image fitting in Width x Height
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Variants of every image, smaller images aren't upscaled
+var Variants = []Variant{
+	{Name: "thumbnail", Width: 160, Height: 240},
+	{Name: "card", Width: 400, Height: 600},
+	{Name: "backdrop", Width: 1280, Height: 720},
+}
+
+// maxPixels protects from decompression bombs, 50 megapixels
+const maxPixels = 50_000_000
+
+// jpegQuality of the JPEG variants
+const jpegQuality = 85
+
+// ErrInvalid is returned for the files which aren't JPEG or PNG images
+var ErrInvalid = errors.New("invalid image")
+
+// Lookup returns the variant by its name
+func Lookup(name string) (Variant, bool) {
+	for _, variant := range Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return Variant{}, false
+}
+
+// Name of the variant of the image in the storage
+func Name(image, variant string) string {
+	return "variants/" + variant + "/" + image
+}
+
+// Decode decodes the JPEG or PNG image,
+// format is "jpeg" or "png"
+func Decode(r io.Reader) (img image.Image, format string, err error) {
+	var buf bytes.Buffer
+	config, format, err := image.DecodeConfig(io.TeeReader(r, &buf))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, "", fmt.Errorf("%w: unsupported format %s", ErrInvalid, format)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d image is too large", ErrInvalid, config.Width, config.Height)
+	}
+
+	img, _, err = image.Decode(io.MultiReader(&buf, r))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return img, format, nil
+}
+
+// Fit returns the size of the width x height image
+// scaled down to fit in maxWidth x maxHeight
+func Fit(width, height, maxWidth, maxHeight int) (int, int) {
+	if width <= maxWidth && height <= maxHeight {
+		return width, height
+	}
+	// compare maxWidth/width and maxHeight/height without rounding
+	if maxWidth*height <= maxHeight*width {
+		return maxWidth, max(1, height*maxWidth/width)
+	}
+	return max(1, width*maxHeight/height), maxHeight
+}
+
+// Resize scales the image down to width x height
+// averaging the source pixels covered by every pixel
+func Resize(src image.Image, width, height int) *image.RGBA {
+	rgba := toRGBA(src)
+	srcWidth, srcHeight := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max((y+1)*srcHeight/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max((x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[i])
+					g += uint64(rgba.Pix[i+1])
+					b += uint64(rgba.Pix[i+2])
+					a += uint64(rgba.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// toRGBA converts the image to RGBA with the origin at (0, 0)
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	if rgba, ok := src.(*image.RGBA); ok && bounds.Min == (image.Point{}) {
+		return rgba
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	return rgba
+}
+
+// Encode encodes the image as JPEG or PNG by its media type
+func Encode(w io.Writer, img image.Image, mediaType string) error {
+	if mediaType == "image/png" {
+		return png.Encode(w, img)
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+// SaveVariants stores the variants of the image saved as name
+func SaveVariants(ctx context.Context, store storage.Storage, name string, img image.Image, mediaType string) error {
+	// the image is converted once for all the variants
+	rgba := toRGBA(img)
+	bounds := rgba.Bounds()
+	for _, variant := range Variants {
+		width, height := Fit(bounds.Dx(), bounds.Dy(), variant.Width, variant.Height)
+		var buf bytes.Buffer
+		err := Encode(&buf, Resize(rgba, width, height), mediaType)
+		if err != nil {
+			return err
+		}
+		err = store.Put(ctx, Name(name, variant.Name), &buf, int64(buf.Len()), mediaType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveVariants removes the variants of the image
+func RemoveVariants(ctx context.Context, store storage.Storage, name string) error {
+	var errs []error
+	for _, variant := range Variants {
+		errs = append(errs, store.Delete(ctx, Name(name, variant.Name)))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,155 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height         int
+		maxWidth, maxHeight   int
+		wantWidth, wantHeight int
+	}{
+		{name: "Smaller", width: 100, height: 50, maxWidth: 160, maxHeight: 240, wantWidth: 100, wantHeight: 50},
+		{name: "Portrait", width: 1000, height: 1500, maxWidth: 160, maxHeight: 240, wantWidth: 160, wantHeight: 240},
+		{name: "Landscape in portrait box", width: 1920, height: 1080, maxWidth: 160, maxHeight: 240, wantWidth: 160, wantHeight: 90},
+		{name: "Portrait in landscape box", width: 1000, height: 1500, maxWidth: 1280, maxHeight: 720, wantWidth: 480, wantHeight: 720},
+		{name: "Thin", width: 10000, height: 1, maxWidth: 100, maxHeight: 100, wantWidth: 100, wantHeight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := Fit(tt.width, tt.height, tt.maxWidth, tt.maxHeight)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("Fit() = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	// black and white columns average to gray
+	src := image.NewGray(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			if x%2 == 1 {
+				src.SetGray(x, y, color.Gray{Y: 200})
+			}
+		}
+	}
+
+	dst := Resize(src, 2, 1)
+	if dst.Bounds() != image.Rect(0, 0, 2, 1) {
+		t.Fatalf("bounds %v", dst.Bounds())
+	}
+	for x := 0; x < 2; x++ {
+		if got := dst.RGBAAt(x, 0); got != (color.RGBA{R: 100, G: 100, B: 100, A: 255}) {
+			t.Errorf("pixel %d = %v", x, got)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	var pngData, jpegData bytes.Buffer
+	if err := png.Encode(&pngData, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := jpeg.Encode(&jpegData, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantFormat string
+		wantErr    bool
+	}{
+		{name: "PNG", data: pngData.Bytes(), wantFormat: "png"},
+		{name: "JPEG", data: jpegData.Bytes(), wantFormat: "jpeg"},
+		{name: "Not an image", data: []byte("not an image"), wantErr: true},
+		{name: "Truncated", data: pngData.Bytes()[:pngData.Len()/2], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, format, err := Decode(bytes.NewReader(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Decode() error = %v, want ErrInvalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.wantFormat || img.Bounds().Size() != image.Pt(3, 2) {
+				t.Errorf("Decode() = %v %s", img.Bounds(), format)
+			}
+		})
+	}
+}
+
+func TestSaveVariants(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewLocal(t.TempDir())
+	img := image.NewNRGBA(image.Rect(0, 0, 2000, 1000))
+
+	if err := SaveVariants(ctx, store, "poster.jpeg", img, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	for _, variant := range Variants {
+		object, err := store.Open(ctx, Name("poster.jpeg", variant.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := jpeg.DecodeConfig(object)
+		object.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", variant.Name, err)
+		}
+		if config.Width > variant.Width || config.Height > variant.Height || config.Width != 2*config.Height {
+			t.Errorf("%s: %dx%d", variant.Name, config.Width, config.Height)
+		}
+	}
+
+	if err := RemoveVariants(ctx, store, "poster.jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	for _, variant := range Variants {
+		if _, err := store.Open(ctx, Name("poster.jpeg", variant.Name)); !storage.IsNotExist(err) {
+			t.Errorf("%s isn't removed: %v", variant.Name, err)
+		}
+	}
+}
+
+func TestDecodeTooLarge(t *testing.T) {
+	// a PNG header of a 100000x100000 image
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// IHDR width and height are at bytes 16-24 followed by the checksum of the chunk
+	binary.BigEndian.PutUint32(data[16:], 100000)
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	_, _, err := Decode(bytes.NewReader(data))
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Decode() error = %v", err)
+	}
+}
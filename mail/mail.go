@@ -0,0 +1,89 @@
+// Package mail sends the emails of the accounts (password reset, verification)
+// over SMTP or writes them to an outbox folder for local testing.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers the messages
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config selects the driver, Driver is "outbox" (default) or "smtp"
+type Config struct {
+	Driver string
+	// From is the sender of the messages
+	From string
+	// OutboxDir of the outbox driver
+	OutboxDir string
+	SMTP      SMTPConfig
+}
+
+// DefaultFrom is the sender when From isn't configured
+const DefaultFrom = "Ozinshe <no-reply@ozinshe.local>"
+
+func New(cfg Config) (Mailer, error) {
+	from := cfg.From
+	if from == "" {
+		from = DefaultFrom
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	switch cfg.Driver {
+	case "", "outbox":
+		return NewOutbox(cfg.OutboxDir, from), nil
+	case "smtp":
+		return NewSMTP(cfg.SMTP, from)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+// format writes the message in the RFC 5322 format
+// with the quoted-printable UTF-8 body
+func format(from string, msg Message, date time.Time) ([]byte, error) {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return nil, fmt.Errorf("invalid subject %q", msg.Subject)
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(buf)
+	_, err = qp.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n")))
+	if err != nil {
+		return nil, err
+	}
+	err = qp.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,206 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readMessage(t *testing.T, data []byte) (*mail.Message, string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg, string(body)
+}
+
+func TestFormat(t *testing.T) {
+	data, err := format(DefaultFrom, Message{
+		To:      "user@test.com",
+		Subject: "Құпия сөзді қалпына келтіру",
+		Body:    "Token: 0123\nBye",
+	}, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, body := readMessage(t, data)
+	if got := msg.Header.Get("To"); got != "<user@test.com>" {
+		t.Errorf("To %q", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Құпия сөзді қалпына келтіру" {
+		t.Errorf("Subject %q", subject)
+	}
+	if date, err := msg.Header.Date(); err != nil || !date.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Date %v, %v", date, err)
+	}
+	if body != "Token: 0123\r\nBye" {
+		t.Errorf("body %q", body)
+	}
+
+	for _, msg := range []Message{
+		{To: "not an address", Subject: "Hi"},
+		{To: "user@test.com", Subject: "Hi\r\nBcc: other@test.com"},
+	} {
+		if _, err := format(DefaultFrom, msg, time.Now()); err == nil {
+			t.Errorf("format(%+v) succeeded", msg)
+		}
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	dir := t.TempDir() + "/outbox"
+	mailer, err := New(Config{OutboxDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, subject := range []string{"First", "Second"} {
+		err := mailer.Send(t.Context(), Message{To: "user@test.com", Subject: subject, Body: subject})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := mailer.(*Outbox).Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d messages, want 2", len(files))
+	}
+	for i, subject := range []string{"First", "Second"} {
+		data, err := os.ReadFile(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, _ := readMessage(t, data)
+		if got := msg.Header.Get("Subject"); got != subject {
+			t.Errorf("message %d: Subject %q, want %q", i, got, subject)
+		}
+		if got := msg.Header.Get("From"); got != DefaultFrom {
+			t.Errorf("message %d: From %q", i, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, cfg := range []Config{
+		{Driver: "pigeon"},
+		{Driver: "smtp"},
+		{From: "not an address"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("New(%+v) succeeded", cfg)
+		}
+	}
+}
+
+// fakeSMTP accepts a single message without extensions
+func fakeSMTP(t *testing.T) (port int, received chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received = make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 fake")
+		envelope := ""
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(command, "MAIL FROM"), strings.HasPrefix(command, "RCPT TO"):
+				envelope += strings.TrimSpace(line) + "\n"
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 go ahead")
+				data := ""
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data += line
+				}
+				received <- envelope + data
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.Port, received
+}
+
+func TestSMTP(t *testing.T) {
+	port, received := fakeSMTP(t)
+	mailer, err := New(Config{
+		Driver: "smtp",
+		From:   "Ozinshe <robot@ozinshe.kz>",
+		SMTP:   SMTPConfig{Host: "127.0.0.1", Port: port},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	err = mailer.Send(ctx, Message{To: "User <user@test.com>", Subject: "Hi", Body: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-received
+	for _, want := range []string{
+		"MAIL FROM:<robot@ozinshe.kz>",
+		"RCPT TO:<user@test.com>",
+		"Subject: Hi\r\n",
+		"\r\n\r\nHello",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message %q doesn't contain %q", got, want)
+		}
+	}
+	if !strings.Contains(got, "To: \"User\" <user@test.com>") {
+		t.Errorf("message %q has no To header", got)
+	}
+}
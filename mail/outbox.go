@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Outbox writes every message to a .eml file of Dir instead of sending it
+type Outbox struct {
+	Dir  string
+	From string
+
+	mu sync.Mutex
+	n  int
+}
+
+func NewOutbox(dir, from string) *Outbox {
+	return &Outbox{
+		Dir:  dir,
+		From: from,
+	}
+}
+
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	data, err := format(o.From, msg, now)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(o.Dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	o.n++
+	name := fmt.Sprintf("%s-%04d.eml", now.UTC().Format("20060102T150405.000000000"), o.n)
+	o.mu.Unlock()
+
+	// the message is renamed once written, so Messages lists only the complete ones
+	path := filepath.Join(o.Dir, name)
+	err = os.WriteFile(path+".tmp", data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Messages returns the paths of the written messages, the oldest first
+func (o *Outbox) Messages() ([]string, error) {
+	return filepath.Glob(filepath.Join(o.Dir, "*.eml"))
+}
@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host string
+	// Port defaults to 587
+	Port     int
+	Username string
+	Password string
+}
+
+// SMTP sends the messages through the server,
+// upgrading the connection with STARTTLS when the server supports it
+type SMTP struct {
+	Config SMTPConfig
+	From   string
+}
+
+func NewSMTP(cfg SMTPConfig, from string) (*SMTP, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("no SMTP host")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	return &SMTP{
+		Config: cfg,
+		From:   from,
+	}, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	data, err := format(s.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Config.Username != "" {
+		auth = smtp.PlainAuth("", s.Config.Username, s.Config.Password, s.Config.Host)
+	}
+
+	// smtp.SendMail doesn't take a context, it runs until the server answers
+	done := make(chan error, 1)
+	go func() {
+		addr := net.JoinHostPort(s.Config.Host, strconv.Itoa(s.Config.Port))
+		done <- smtp.SendMail(addr, auth, from.Address, []string{to.Address}, data)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
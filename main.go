@@ -1,15 +1,26 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/Bayan2019/go-ozinshe/configuration"
 	"github.com/Bayan2019/go-ozinshe/controllers"
+	"github.com/Bayan2019/go-ozinshe/gc"
+	"github.com/Bayan2019/go-ozinshe/hls"
+	"github.com/Bayan2019/go-ozinshe/mail"
 	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/throttle"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
@@ -66,24 +77,206 @@ func main() {
 		jwtSecret = "superozinshe"
 	}
 
+	// media files are kept in DIR or in the S3 bucket
+	store, err := storage.New(storage.Config{
+		Driver: os.Getenv("STORAGE_DRIVER"),
+		Dir:    dir,
+		S3: storage.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       os.Getenv("S3_PATH_STYLE") == "true",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// emails are sent over SMTP or written to MAIL_OUTBOX_DIR
+	outboxDir := os.Getenv("MAIL_OUTBOX_DIR")
+	if outboxDir == "" {
+		outboxDir = filepath.Join(os.TempDir(), "ozinshe-outbox")
+	}
+	smtpPort := 0
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		smtpPort, err = strconv.Atoi(port)
+		if err != nil {
+			log.Fatalf("invalid SMTP_PORT %q: %s", port, err)
+		}
+	}
+	mailer, err := mail.New(mail.Config{
+		Driver:    os.Getenv("MAIL_DRIVER"),
+		From:      os.Getenv("MAIL_FROM"),
+		OutboxDir: outboxDir,
+		SMTP: mail.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     smtpPort,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	passwordResetURL := os.Getenv("PASSWORD_RESET_URL")
+	emailVerification := os.Getenv("EMAIL_VERIFICATION")
+	_, err = controllers.ParseVerificationPolicy(emailVerification)
+	if err != nil {
+		log.Fatal(err)
+	}
+	emailVerificationURL := os.Getenv("EMAIL_VERIFICATION_URL")
+	trustedProxies, err := controllers.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	if configuration.ApiCfg != nil {
 		configuration.ApiCfg.Dir = dir
+		configuration.ApiCfg.Storage = store
 		configuration.ApiCfg.JwtSecret = jwtSecret
+		configuration.ApiCfg.Mailer = mailer
+		configuration.ApiCfg.PasswordResetURL = passwordResetURL
+		configuration.ApiCfg.EmailVerification = emailVerification
+		configuration.ApiCfg.EmailVerificationURL = emailVerificationURL
+		configuration.ApiCfg.TrustedProxies = trustedProxies
 	} else {
 		fmt.Println("No DATABASE_URL")
 		configuration.ApiCfg = &configuration.ApiConfiguration{
-			Dir:       dir,
-			JwtSecret: jwtSecret,
+			Dir:                  dir,
+			Storage:              store,
+			JwtSecret:            jwtSecret,
+			Mailer:               mailer,
+			PasswordResetURL:     passwordResetURL,
+			EmailVerification:    emailVerification,
+			EmailVerificationURL: emailVerificationURL,
+			TrustedProxies:       trustedProxies,
+		}
+	}
+
+	// the failed sign ins are shared by the instances in the DataBase
+	switch os.Getenv("LOGIN_ATTEMPTS_STORE") {
+	case "", "memory":
+	case "database":
+		if configuration.ApiCfg.DB != nil {
+			configuration.ApiCfg.LoginAttempts = throttle.NewDatabase(configuration.ApiCfg.DB)
+		}
+	default:
+		log.Fatalf("unknown LOGIN_ATTEMPTS_STORE %q", os.Getenv("LOGIN_ATTEMPTS_STORE"))
+	}
+
+	// go-ozinshe gc [-delete] [-grace 24h] reports the orphaned media and exits
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		os.Exit(runGC(configuration.ApiCfg, os.Args[2:], os.Stdout))
+	}
+
+	if configuration.ApiCfg.DB != nil {
+		ffmpeg := os.Getenv("FFMPEG")
+		if ffmpeg == "" {
+			ffmpeg = "ffmpeg"
+		}
+		ffmpeg, err = exec.LookPath(ffmpeg)
+		if err != nil {
+			log.Println("ffmpeg isn't found, videos aren't packaged for HLS")
+		} else {
+			packager := hls.NewPackager(configuration.ApiCfg.DB, store, ffmpeg)
+			err = packager.Start(context.Background())
+			if err != nil {
+				log.Fatal(err)
+			}
+			configuration.ApiCfg.Packager = packager
+		}
+	}
+
+	// GC_INTERVAL like 24h collects the orphaned media on a schedule,
+	// they are deleted with GC_DELETE=true
+	if interval := os.Getenv("GC_INTERVAL"); interval != "" && configuration.ApiCfg.DB != nil {
+		every, err := time.ParseDuration(interval)
+		if err != nil || every <= 0 {
+			log.Fatalf("invalid GC_INTERVAL %q", interval)
+		}
+		options, err := gcOptions(os.Getenv("GC_GRACE_PERIOD"), os.Getenv("GC_DELETE") == "true")
+		if err != nil {
+			log.Fatal(err)
+		}
+		gc.NewCollector(configuration.ApiCfg.DB, store, options).Start(context.Background(), every)
+	}
+
+	router := newRouter(configuration.ApiCfg)
+
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: time.Second * 10,
+	}
+
+	log.Printf("Serving on: http://localhost:%s\n", port)
+	log.Fatal(srv.ListenAndServe())
+}
+
+// runGC reconciles the media files with the rows of the database
+// and prints the report, it returns the exit code
+func runGC(cfg *configuration.ApiConfiguration, args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("gc", flag.ContinueOnError)
+	flags.SetOutput(out)
+	remove := flags.Bool("delete", false, "delete the orphans older than the grace period")
+	grace := flags.String("grace", os.Getenv("GC_GRACE_PERIOD"), "grace period of the orphans (default 24h)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if cfg.DB == nil {
+		fmt.Fprintln(out, "gc needs DATABASE_URL")
+		return 1
+	}
+	if local, ok := cfg.Storage.(*storage.Local); ok && local.Dir == "" {
+		// the working directory isn't the media storage
+		fmt.Fprintln(out, "gc needs DIR of the media files")
+		return 1
+	}
+	options, err := gcOptions(*grace, *remove)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 2
+	}
+
+	report, err := gc.NewCollector(cfg.DB, cfg.Storage, options).Run(context.Background())
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	report.Print(out)
+	return 0
+}
+
+func gcOptions(grace string, remove bool) (gc.Options, error) {
+	options := gc.Options{
+		GracePeriod: gc.DefaultGracePeriod,
+		Delete:      remove,
+	}
+	if grace != "" {
+		period, err := time.ParseDuration(grace)
+		if err != nil || period < 0 {
+			return gc.Options{}, fmt.Errorf("invalid grace period %q", grace)
 		}
+		options.GracePeriod = period
 	}
+	return options, nil
+}
 
+// newRouter registers all the routes of the API.
+// Authorized routes declare here the permission they require,
+// see controllers.MiddlewarePermission.
+func newRouter(cfg *configuration.ApiConfiguration) *chi.Mux {
 	router := chi.NewRouter()
 
+	// the client address of the requests forwarded by the reverse proxies
+	router.Use(controllers.MiddlewareClientIP(cfg.TrustedProxies))
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD"},
 		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "Location", "X-Media-Id", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size", "Upload-Offset", "Upload-Length", "Upload-Metadata", "Upload-Expires"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
@@ -97,94 +290,198 @@ func main() {
 
 	v1Router := chi.NewRouter()
 
-	if configuration.ApiCfg.DB != nil {
-		authHandlers := controllers.NewAuthHandlers(configuration.ApiCfg.DB, configuration.ApiCfg.JwtSecret)
+	if cfg.DB != nil {
+		// the policy is checked in main
+		verificationPolicy, _ := controllers.ParseVerificationPolicy(cfg.EmailVerification)
+		loginAttempts := cfg.LoginAttempts
+		if loginAttempts == nil {
+			loginAttempts = throttle.NewMemory()
+		}
+		authHandlers := controllers.NewAuthHandlers(cfg.DB, cfg.JwtSecret, verificationPolicy, loginAttempts)
 
 		v1Router.Post("/auth/sign-in", authHandlers.Login)
+		v1Router.Post("/auth/sign-in/2fa", authHandlers.SignIn2FA)
 		v1Router.Post("/auth/refresh", authHandlers.Refresh)
 		v1Router.Post("/auth/sign-out", authHandlers.Logout)
 
-		usersRepository := repositories.NewUsersRepository(configuration.ApiCfg.Conn)
-		usersHandlers := controllers.NewUsersHandlers(usersRepository)
+		passwordsHandlers := controllers.NewPasswordsHandlers(cfg.DB, cfg.Mailer, cfg.PasswordResetURL, loginAttempts)
+
+		v1Router.Post("/auth/forgot-password", passwordsHandlers.Forgot)
+		v1Router.Post("/auth/reset-password", passwordsHandlers.Reset)
+		v1Router.Put("/users/profile/password", authHandlers.MiddlewareAuth(passwordsHandlers.Change))
+
+		v1Router.Post("/users/profile/2fa", authHandlers.MiddlewareAuth(authHandlers.Enroll2FA))
+		v1Router.Post("/users/profile/2fa/confirm", authHandlers.MiddlewareAuth(authHandlers.Confirm2FA))
+		v1Router.Delete("/users/profile/2fa", authHandlers.MiddlewareAuth(authHandlers.Disable2FA))
+
+		verificationHandlers := controllers.NewVerificationHandlers(cfg.DB, cfg.Mailer, cfg.EmailVerificationURL, loginAttempts)
+
+		v1Router.Post("/auth/verify-email", verificationHandlers.Verify)
+		v1Router.Post("/auth/resend-verification", verificationHandlers.Resend)
+
+		usersRepository := repositories.NewUsersRepository(cfg.Conn)
+		usersHandlers := controllers.NewUsersHandlers(usersRepository, verificationHandlers)
 
 		v1Router.Post("/users", usersHandlers.Register)
-		v1Router.Get("/users", authHandlers.MiddlewareAuth(usersHandlers.GetUsers))
-		v1Router.Get("/users/{id}", authHandlers.MiddlewareAuth(usersHandlers.GetUser))
-		v1Router.Put("/users/{id}", authHandlers.MiddlewareAuth(usersHandlers.Update))
-		v1Router.Delete("/users/{id}", authHandlers.MiddlewareAuth(usersHandlers.Delete))
+		v1Router.Get("/users", authHandlers.MiddlewarePermission(controllers.ResourceUsers, controllers.PermissionRead, usersHandlers.GetUsers))
+		v1Router.Get("/users/{id}", authHandlers.MiddlewarePermission(controllers.ResourceUsers, controllers.PermissionRead, usersHandlers.GetUser))
+		v1Router.Put("/users/{id}", authHandlers.MiddlewarePermission(controllers.ResourceUsers, controllers.PermissionWrite, usersHandlers.Update))
+		v1Router.Delete("/users/{id}", authHandlers.MiddlewarePermission(controllers.ResourceUsers, controllers.PermissionWrite, usersHandlers.Delete))
 
 		v1Router.Get("/users/profile", authHandlers.MiddlewareAuth(usersHandlers.GetProfile))
 		v1Router.Put("/users/profile", authHandlers.MiddlewareAuth(usersHandlers.UpdateProfile))
 		v1Router.Delete("/users/profile", authHandlers.MiddlewareAuth(usersHandlers.DeleteProfile))
 
-		rolesHandlers := controllers.NewRolesHandlers(configuration.ApiCfg.DB)
+		sessionsHandlers := controllers.NewSessionsHandlers(cfg.DB)
 
-		v1Router.Get("/roles", authHandlers.MiddlewareAuth(rolesHandlers.GetAll))
-		v1Router.Post("/roles", authHandlers.MiddlewareAuth(rolesHandlers.Create))
-		v1Router.Get("/roles/{id}", authHandlers.MiddlewareAuth(rolesHandlers.Get))
-		v1Router.Put("/roles/{id}", authHandlers.MiddlewareAuth(rolesHandlers.Update))
-		v1Router.Put("/roles/{id}", authHandlers.MiddlewareAuth(rolesHandlers.Delete))
+		v1Router.Get("/users/profile/sessions", authHandlers.MiddlewareAuth(sessionsHandlers.GetAll))
+		v1Router.Delete("/users/profile/sessions", authHandlers.MiddlewareAuth(sessionsHandlers.RevokeOthers))
+		v1Router.Delete("/users/profile/sessions/{id}", authHandlers.MiddlewareAuth(sessionsHandlers.Revoke))
+		v1Router.Post("/users/{id}/unlock", authHandlers.MiddlewarePermission(controllers.ResourceUsers, controllers.PermissionWrite, authHandlers.Unlock))
+		v1Router.Delete("/users/{id}/sessions", authHandlers.MiddlewarePermission(controllers.ResourceUsers, controllers.PermissionWrite, sessionsHandlers.RevokeOfUser))
 
-		genresHandlers := controllers.NewGenresHandlers(configuration.ApiCfg.DB)
+		rolesHandlers := controllers.NewRolesHandlers(cfg.DB)
 
-		v1Router.Get("/genres", authHandlers.MiddlewareAuth(genresHandlers.GetAll))
-		v1Router.Post("/genres", authHandlers.MiddlewareAuth(genresHandlers.Create))
-		v1Router.Get("/genres/{id}", authHandlers.MiddlewareAuth(genresHandlers.Get))
-		v1Router.Put("/genres/{id}", authHandlers.MiddlewareAuth(genresHandlers.Update))
-		v1Router.Put("/genres/{id}", authHandlers.MiddlewareAuth(genresHandlers.Delete))
+		v1Router.Get("/roles", authHandlers.MiddlewarePermission(controllers.ResourceRoles, controllers.PermissionRead, rolesHandlers.GetAll))
+		v1Router.Post("/roles", authHandlers.MiddlewarePermission(controllers.ResourceRoles, controllers.PermissionWrite, rolesHandlers.Create))
+		v1Router.Get("/roles/{id}", authHandlers.MiddlewarePermission(controllers.ResourceRoles, controllers.PermissionRead, rolesHandlers.Get))
+		v1Router.Put("/roles/{id}", authHandlers.MiddlewarePermission(controllers.ResourceRoles, controllers.PermissionWrite, rolesHandlers.Update))
+		v1Router.Delete("/roles/{id}", authHandlers.MiddlewarePermission(controllers.ResourceRoles, controllers.PermissionWrite, rolesHandlers.Delete))
 
-		ageCategoriesHandlers := controllers.NewAgeCategoriesHandlers(configuration.ApiCfg.DB)
+		genresHandlers := controllers.NewGenresHandlers(cfg.DB)
 
-		v1Router.Get("/age-categories", authHandlers.MiddlewareAuth(ageCategoriesHandlers.GetAll))
-		v1Router.Post("/age-categories", authHandlers.MiddlewareAuth(ageCategoriesHandlers.Create))
-		v1Router.Get("/age-categories/{id}", authHandlers.MiddlewareAuth(ageCategoriesHandlers.Get))
-		v1Router.Put("/age-categories/{id}", authHandlers.MiddlewareAuth(ageCategoriesHandlers.Update))
-		v1Router.Put("/age-categories/{id}", authHandlers.MiddlewareAuth(ageCategoriesHandlers.Delete))
+		v1Router.Get("/genres", authHandlers.MiddlewarePermission(controllers.ResourceGenres, controllers.PermissionRead, genresHandlers.GetAll))
+		v1Router.Post("/genres", authHandlers.MiddlewarePermission(controllers.ResourceGenres, controllers.PermissionWrite, genresHandlers.Create))
+		v1Router.Get("/genres/{id}", authHandlers.MiddlewarePermission(controllers.ResourceGenres, controllers.PermissionRead, genresHandlers.Get))
+		v1Router.Put("/genres/{id}", authHandlers.MiddlewarePermission(controllers.ResourceGenres, controllers.PermissionWrite, genresHandlers.Update))
+		v1Router.Delete("/genres/{id}", authHandlers.MiddlewarePermission(controllers.ResourceGenres, controllers.PermissionWrite, genresHandlers.Delete))
 
-		typesHandlers := controllers.NewTypesHandlers(configuration.ApiCfg.DB)
+		ageCategoriesHandlers := controllers.NewAgeCategoriesHandlers(cfg.DB)
 
-		v1Router.Get("/types", authHandlers.MiddlewareAuth(typesHandlers.GetAll))
-		v1Router.Post("/types", authHandlers.MiddlewareAuth(typesHandlers.Create))
-		v1Router.Get("/types/{id}", authHandlers.MiddlewareAuth(typesHandlers.Get))
-		v1Router.Put("/types/{id}", authHandlers.MiddlewareAuth(typesHandlers.Update))
-		v1Router.Put("/types/{id}", authHandlers.MiddlewareAuth(typesHandlers.Delete))
+		v1Router.Get("/age-categories", authHandlers.MiddlewarePermission(controllers.ResourceAgeCategories, controllers.PermissionRead, ageCategoriesHandlers.GetAll))
+		v1Router.Post("/age-categories", authHandlers.MiddlewarePermission(controllers.ResourceAgeCategories, controllers.PermissionWrite, ageCategoriesHandlers.Create))
+		v1Router.Get("/age-categories/{id}", authHandlers.MiddlewarePermission(controllers.ResourceAgeCategories, controllers.PermissionRead, ageCategoriesHandlers.Get))
+		v1Router.Put("/age-categories/{id}", authHandlers.MiddlewarePermission(controllers.ResourceAgeCategories, controllers.PermissionWrite, ageCategoriesHandlers.Update))
+		v1Router.Delete("/age-categories/{id}", authHandlers.MiddlewarePermission(controllers.ResourceAgeCategories, controllers.PermissionWrite, ageCategoriesHandlers.Delete))
 
-		imagesHandlers := controllers.NewImagesHandlers(configuration.ApiCfg.DB, configuration.ApiCfg.Dir)
+		typesHandlers := controllers.NewTypesHandlers(cfg.DB)
 
-		v1Router.Post("/projects/images", authHandlers.MiddlewareAuth(imagesHandlers.Upload))
-		v1Router.Get("/projects/images/{id}", authHandlers.MiddlewareAuth(imagesHandlers.Get))
-		v1Router.Get("/projects/images/show/{id}", authHandlers.MiddlewareAuth(imagesHandlers.Display))
-		v1Router.Delete("/projects/images/{id}", authHandlers.MiddlewareAuth(imagesHandlers.Delete))
+		v1Router.Get("/types", authHandlers.MiddlewarePermission(controllers.ResourceTypes, controllers.PermissionRead, typesHandlers.GetAll))
+		v1Router.Post("/types", authHandlers.MiddlewarePermission(controllers.ResourceTypes, controllers.PermissionWrite, typesHandlers.Create))
+		v1Router.Get("/types/{id}", authHandlers.MiddlewarePermission(controllers.ResourceTypes, controllers.PermissionRead, typesHandlers.Get))
+		v1Router.Put("/types/{id}", authHandlers.MiddlewarePermission(controllers.ResourceTypes, controllers.PermissionWrite, typesHandlers.Update))
+		v1Router.Delete("/types/{id}", authHandlers.MiddlewarePermission(controllers.ResourceTypes, controllers.PermissionWrite, typesHandlers.Delete))
 
-		videosHandlers := controllers.NewVideosHandlers(configuration.ApiCfg.DB, configuration.ApiCfg.Dir)
+		imagesHandlers := controllers.NewImagesHandlers(cfg.DB, cfg.Storage)
 
-		v1Router.Post("/projects/videos", authHandlers.MiddlewareAuth(videosHandlers.Upload))
-		v1Router.Get("/projects/videos/{id}", authHandlers.MiddlewareAuth(videosHandlers.Get))
-		v1Router.Delete("/projects/videos/{id}", authHandlers.MiddlewareAuth(videosHandlers.Delete))
-		v1Router.Get("/projects/videos/play/{id}", authHandlers.MiddlewareAuth(videosHandlers.Play))
+		v1Router.Post("/projects/images", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, imagesHandlers.Upload))
+		v1Router.Get("/projects/images/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, imagesHandlers.Get))
+		v1Router.Get("/projects/images/show/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, imagesHandlers.Display))
+		v1Router.Delete("/projects/images/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, imagesHandlers.Delete))
 
-		projectsRepository := repositories.NewProjectsRepository(configuration.ApiCfg.Conn)
-		projectsHandlers := controllers.NewProjecsHandlers(projectsRepository, configuration.ApiCfg.Dir)
+		projectsRepository := repositories.NewProjectsRepository(cfg.Conn)
+		videosHandlers := controllers.NewVideosHandlers(cfg.DB, projectsRepository, cfg.Storage, cfg.Packager)
 
-		v1Router.Get("/projects", authHandlers.MiddlewareAuth(projectsHandlers.GetAll))
-		v1Router.Get("/projects/{id}", authHandlers.MiddlewareAuth(projectsHandlers.Get))
-		v1Router.Post("/projects", authHandlers.MiddlewareAuth(projectsHandlers.Create))
-		v1Router.Put("/projects/{id}", authHandlers.MiddlewareAuth(projectsHandlers.Update))
+		v1Router.Post("/projects/videos", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, videosHandlers.Upload))
+		v1Router.Get("/projects/videos/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, videosHandlers.Get))
+		v1Router.Delete("/projects/videos/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, videosHandlers.Delete))
+		v1Router.Get("/projects/videos/play/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, videosHandlers.Play))
+		v1Router.Get("/projects/videos/{id}/hls/*", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, videosHandlers.Stream))
+		v1Router.Post("/projects/videos/{id}/hls", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, videosHandlers.Repackage))
 
-		v1Router.Post("/projects/{id}/cover", authHandlers.MiddlewareAuth(projectsHandlers.UploadCover))
-		v1Router.Patch("/projects/{id}/cover", authHandlers.MiddlewareAuth(projectsHandlers.SetCover))
+		signedURLsHandlers := controllers.NewSignedURLsHandlers(cfg.DB, cfg.JwtSecret)
 
-		v1Router.Get("/projects/search", authHandlers.MiddlewareAuth(projectsHandlers.GetAllSearch))
-	}
+		v1Router.Get("/projects/videos/{id}/signed-url", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, signedURLsHandlers.Video))
+		v1Router.Get("/projects/images/{id}/signed-url", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, signedURLsHandlers.Image))
+		// <video> and <img> tags load the media by the signed URLs without the Authorization header
+		v1Router.Get("/media/videos/{id}", authHandlers.MiddlewareSignature(controllers.ResourceProjects, controllers.PermissionRead, videosHandlers.Play))
+		v1Router.Get("/media/images/{id}", authHandlers.MiddlewareSignature(controllers.ResourceProjects, controllers.PermissionRead, imagesHandlers.Display))
+		v1Router.Get("/media/hls/{id}/{user}/{expires}/{signature}/*", authHandlers.MiddlewareHLSSignature(controllers.ResourceProjects, controllers.PermissionRead, videosHandlers.Stream))
 
-	router.Mount("/v1", v1Router)
+		subtitlesHandlers := controllers.NewSubtitlesHandlers(cfg.DB, cfg.Storage)
 
-	srv := &http.Server{
-		Addr:              ":" + port,
-		Handler:           router,
-		ReadHeaderTimeout: time.Second * 10,
+		v1Router.Get("/projects/videos/{id}/subtitles", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, subtitlesHandlers.GetAll))
+		v1Router.Post("/projects/videos/{id}/subtitles", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, subtitlesHandlers.Upload))
+		v1Router.Get("/projects/subtitles/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, subtitlesHandlers.Get))
+		v1Router.Put("/projects/subtitles/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, subtitlesHandlers.Update))
+		v1Router.Delete("/projects/subtitles/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, subtitlesHandlers.Delete))
+
+		uploadsHandlers := controllers.NewUploadsHandlers(cfg.DB, cfg.Storage, imagesHandlers, videosHandlers)
+
+		v1Router.Options("/uploads", uploadsHandlers.Options)
+		v1Router.Post("/uploads", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, uploadsHandlers.Create))
+		v1Router.Head("/uploads/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, uploadsHandlers.Head))
+		v1Router.Patch("/uploads/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, uploadsHandlers.Patch))
+		v1Router.Delete("/uploads/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, uploadsHandlers.Delete))
+
+		projectsHandlers := controllers.NewProjecsHandlers(projectsRepository, cfg.Storage)
+
+		v1Router.Get("/projects", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, projectsHandlers.GetAll))
+		v1Router.Get("/projects/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, projectsHandlers.Get))
+		v1Router.Post("/projects", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, projectsHandlers.Create))
+		v1Router.Put("/projects/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, projectsHandlers.Update))
+		v1Router.Delete("/projects/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, projectsHandlers.Delete))
+
+		v1Router.Post("/projects/{id}/cover", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, projectsHandlers.UploadCover))
+		v1Router.Patch("/projects/{id}/cover", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, projectsHandlers.SetCover))
+
+		v1Router.Get("/projects/search", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, projectsHandlers.GetAllSearch))
+
+		favouritesHandlers := controllers.NewFavouritesHandlers(projectsRepository)
+
+		v1Router.Get("/users/profile/favourites", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, favouritesHandlers.GetAll))
+		v1Router.Post("/users/profile/favourites/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, favouritesHandlers.Add))
+		v1Router.Delete("/users/profile/favourites/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, favouritesHandlers.Delete))
+
+		watchlistHandlers := controllers.NewWatchlistHandlers(projectsRepository)
+
+		v1Router.Get("/users/profile/watchlist", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, watchlistHandlers.GetAll))
+		v1Router.Post("/users/profile/watchlist/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, watchlistHandlers.Add))
+		v1Router.Delete("/users/profile/watchlist/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, watchlistHandlers.Delete))
+
+		progressRepository := repositories.NewProgressRepository(cfg.Conn)
+		progressHandlers := controllers.NewProgressHandlers(progressRepository)
+
+		v1Router.Get("/users/profile/progress/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, progressHandlers.Get))
+		v1Router.Put("/users/profile/progress/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, progressHandlers.Save))
+		v1Router.Get("/users/profile/continue-watching", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, progressHandlers.ContinueWatching))
+
+		seasonsHandlers := controllers.NewSeasonsHandlers(cfg.DB, projectsRepository)
+
+		v1Router.Get("/projects/{id}/seasons", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, seasonsHandlers.GetAll))
+		v1Router.Post("/projects/{id}/seasons", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, seasonsHandlers.Create))
+		v1Router.Put("/projects/seasons/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, seasonsHandlers.Update))
+		v1Router.Delete("/projects/seasons/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, seasonsHandlers.Delete))
+		v1Router.Post("/projects/seasons/{id}/episodes", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, seasonsHandlers.CreateEpisode))
+		v1Router.Put("/projects/episodes/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, seasonsHandlers.UpdateEpisode))
+		v1Router.Delete("/projects/episodes/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, seasonsHandlers.DeleteEpisode))
+
+		ratingsHandlers := controllers.NewRatingsHandlers(cfg.DB)
+
+		v1Router.Get("/projects/{id}/rating", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, ratingsHandlers.Get))
+		v1Router.Put("/projects/{id}/rating", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, ratingsHandlers.Rate))
+		v1Router.Delete("/projects/{id}/rating", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, ratingsHandlers.Delete))
+		v1Router.Get("/projects/{id}/ratings", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, ratingsHandlers.GetAllOfProject))
+		v1Router.Patch("/projects/ratings/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, ratingsHandlers.Hide))
+		v1Router.Get("/users/profile/ratings", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, ratingsHandlers.GetAllOfProfile))
+
+		creditsHandlers := controllers.NewCreditsHandlers(cfg.DB, projectsRepository)
+
+		v1Router.Get("/projects/{id}/credits", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, creditsHandlers.GetAll))
+		v1Router.Post("/projects/{id}/credits", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, creditsHandlers.Add))
+		v1Router.Delete("/projects/credits/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, creditsHandlers.Delete))
+
+		peopleHandlers := controllers.NewPeopleHandlers(cfg.DB, repositories.NewPeopleRepository(cfg.Conn), cfg.Storage)
+
+		v1Router.Get("/people", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, peopleHandlers.GetAll))
+		v1Router.Post("/people", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, peopleHandlers.Create))
+		v1Router.Get("/people/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionRead, peopleHandlers.Get))
+		v1Router.Put("/people/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, peopleHandlers.Update))
+		v1Router.Delete("/people/{id}", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, peopleHandlers.Delete))
+		v1Router.Post("/people/{id}/photo", authHandlers.MiddlewarePermission(controllers.ResourceProjects, controllers.PermissionWrite, peopleHandlers.UploadPhoto))
 	}
 
-	log.Printf("Serving on: http://localhost:%s\n", port)
-	log.Fatal(srv.ListenAndServe())
+	router.Mount("/v1", v1Router)
+
+	return router
 }
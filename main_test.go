@@ -0,0 +1,2974 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/http/httptest"
+	netmail "net/mail"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/configuration"
+	"github.com/Bayan2019/go-ozinshe/controllers"
+	"github.com/Bayan2019/go-ozinshe/hls"
+	"github.com/Bayan2019/go-ozinshe/imaging"
+	"github.com/Bayan2019/go-ozinshe/mail"
+	"github.com/Bayan2019/go-ozinshe/repositories"
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/repositories/dbtest"
+	"github.com/Bayan2019/go-ozinshe/storage"
+	"github.com/Bayan2019/go-ozinshe/throttle"
+	"github.com/Bayan2019/go-ozinshe/totp"
+	"github.com/Bayan2019/go-ozinshe/views"
+	"github.com/go-chi/chi"
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "password"
+
+// newTestConfig opens a fresh SQLite database
+// with all the goose Up migrations of repositories/sql/schema applied
+func newTestConfig(t *testing.T) *configuration.ApiConfiguration {
+	t.Helper()
+
+	db := dbtest.Open(t)
+
+	dir := t.TempDir() + "/"
+	return &configuration.ApiConfiguration{
+		Conn:      db,
+		DB:        database.New(db),
+		Dir:       dir,
+		Storage:   storage.NewLocal(dir),
+		JwtSecret: "secret",
+		Mailer:    mail.NewOutbox(t.TempDir(), mail.DefaultFrom),
+		// the reset emails have links with the tokens
+		PasswordResetURL: "https://ozinshe.test/reset-password",
+	}
+}
+
+// createTestUser creates a user having a single role with the given levels
+// and returns its access token
+func createTestUser(t *testing.T, cfg *configuration.ApiConfiguration, router http.Handler, name string, levels map[controllers.Resource]controllers.PermissionLevel) string {
+	t.Helper()
+
+	level := func(resource controllers.Resource) int64 {
+		return int64(levels[resource])
+	}
+	roleID, err := cfg.DB.CreateRole(t.Context(), database.CreateRoleParams{
+		Title:         name,
+		Projects:      level(controllers.ResourceProjects),
+		Genres:        level(controllers.ResourceGenres),
+		AgeCategories: level(controllers.ResourceAgeCategories),
+		Types:         level(controllers.ResourceTypes),
+		Users:         level(controllers.ResourceUsers),
+		Roles:         level(controllers.ResourceRoles),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := name + "@test.com"
+	userID, err := cfg.DB.CreateUser(t.Context(), database.CreateUserParams{
+		Name:         name,
+		Email:        email,
+		PasswordHash: string(hash),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.DB.AddRole2User(t.Context(), database.AddRole2UserParams{
+		UserID: userID,
+		RoleID: roleID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signIn(t, router, email).AccessToken
+}
+
+// signIn returns the tokens of the user with testPassword
+func signIn(t *testing.T, router http.Handler, email string) views.TokensResponse {
+	t.Helper()
+
+	body, _ := json.Marshal(views.SignInRequest{Email: email, Password: testPassword})
+	req := httptest.NewRequest(http.MethodPost, "/v1/auth/sign-in", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("sign in %s: status %d: %s", email, rec.Code, rec.Body.String())
+	}
+	tokens := views.TokensResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &tokens); err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+var allResources = []controllers.Resource{
+	controllers.ResourceProjects,
+	controllers.ResourceGenres,
+	controllers.ResourceAgeCategories,
+	controllers.ResourceTypes,
+	controllers.ResourceUsers,
+	controllers.ResourceRoles,
+}
+
+// testUsers creates for every resource and level two users:
+// one having exactly the level on the resource and no access to anything else,
+// and one having the level below on the resource and full access to anything else.
+func testUsers(t *testing.T, cfg *configuration.ApiConfiguration, router http.Handler) (allowed, denied map[controllers.Resource]map[controllers.PermissionLevel]string) {
+	t.Helper()
+
+	allowed = map[controllers.Resource]map[controllers.PermissionLevel]string{}
+	denied = map[controllers.Resource]map[controllers.PermissionLevel]string{}
+	for _, resource := range allResources {
+		allowed[resource] = map[controllers.PermissionLevel]string{}
+		denied[resource] = map[controllers.PermissionLevel]string{}
+		for _, level := range []controllers.PermissionLevel{controllers.PermissionRead, controllers.PermissionWrite} {
+			only := map[controllers.Resource]controllers.PermissionLevel{}
+			except := map[controllers.Resource]controllers.PermissionLevel{}
+			for _, other := range allResources {
+				only[other] = controllers.PermissionForbidden
+				except[other] = controllers.PermissionWrite
+			}
+			only[resource] = level
+			except[resource] = level - 1
+
+			allowed[resource][level] = createTestUser(t, cfg, router, fmt.Sprintf("allowed-%s-%d", resource, level), only)
+			denied[resource][level] = createTestUser(t, cfg, router, fmt.Sprintf("denied-%s-%d", resource, level), except)
+		}
+	}
+	return allowed, denied
+}
+
+func TestRoutesPermissions(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	allowed, denied := testUsers(t, cfg, router)
+
+	// ids in paths don't exist, so that allowed requests don't change anything
+	tests := []struct {
+		method   string
+		route    string
+		path     string
+		resource controllers.Resource
+		level    controllers.PermissionLevel
+	}{
+		{http.MethodGet, "/v1/users", "/v1/users", controllers.ResourceUsers, controllers.PermissionRead},
+		{http.MethodGet, "/v1/users/{id}", "/v1/users/999", controllers.ResourceUsers, controllers.PermissionRead},
+		{http.MethodPut, "/v1/users/{id}", "/v1/users/999", controllers.ResourceUsers, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/users/{id}", "/v1/users/999", controllers.ResourceUsers, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/users/{id}/sessions", "/v1/users/999/sessions", controllers.ResourceUsers, controllers.PermissionWrite},
+		{http.MethodPost, "/v1/users/{id}/unlock", "/v1/users/999/unlock", controllers.ResourceUsers, controllers.PermissionWrite},
+
+		{http.MethodGet, "/v1/roles", "/v1/roles", controllers.ResourceRoles, controllers.PermissionRead},
+		{http.MethodPost, "/v1/roles", "/v1/roles", controllers.ResourceRoles, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/roles/{id}", "/v1/roles/999", controllers.ResourceRoles, controllers.PermissionRead},
+		{http.MethodPut, "/v1/roles/{id}", "/v1/roles/999", controllers.ResourceRoles, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/roles/{id}", "/v1/roles/999", controllers.ResourceRoles, controllers.PermissionWrite},
+
+		{http.MethodGet, "/v1/genres", "/v1/genres", controllers.ResourceGenres, controllers.PermissionRead},
+		{http.MethodPost, "/v1/genres", "/v1/genres", controllers.ResourceGenres, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/genres/{id}", "/v1/genres/999", controllers.ResourceGenres, controllers.PermissionRead},
+		{http.MethodPut, "/v1/genres/{id}", "/v1/genres/999", controllers.ResourceGenres, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/genres/{id}", "/v1/genres/999", controllers.ResourceGenres, controllers.PermissionWrite},
+
+		{http.MethodGet, "/v1/age-categories", "/v1/age-categories", controllers.ResourceAgeCategories, controllers.PermissionRead},
+		{http.MethodPost, "/v1/age-categories", "/v1/age-categories", controllers.ResourceAgeCategories, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/age-categories/{id}", "/v1/age-categories/999", controllers.ResourceAgeCategories, controllers.PermissionRead},
+		{http.MethodPut, "/v1/age-categories/{id}", "/v1/age-categories/999", controllers.ResourceAgeCategories, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/age-categories/{id}", "/v1/age-categories/999", controllers.ResourceAgeCategories, controllers.PermissionWrite},
+
+		{http.MethodGet, "/v1/types", "/v1/types", controllers.ResourceTypes, controllers.PermissionRead},
+		{http.MethodPost, "/v1/types", "/v1/types", controllers.ResourceTypes, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/types/{id}", "/v1/types/999", controllers.ResourceTypes, controllers.PermissionRead},
+		{http.MethodPut, "/v1/types/{id}", "/v1/types/999", controllers.ResourceTypes, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/types/{id}", "/v1/types/999", controllers.ResourceTypes, controllers.PermissionWrite},
+
+		{http.MethodPost, "/v1/projects/images", "/v1/projects/images", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/images/{id}", "/v1/projects/images/none.png", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodGet, "/v1/projects/images/show/{id}", "/v1/projects/images/show/none.png", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodDelete, "/v1/projects/images/{id}", "/v1/projects/images/none.png", controllers.ResourceProjects, controllers.PermissionWrite},
+
+		{http.MethodPost, "/v1/projects/videos", "/v1/projects/videos", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/videos/{id}", "/v1/projects/videos/none.mp4", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodDelete, "/v1/projects/videos/{id}", "/v1/projects/videos/none.mp4", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/videos/play/{id}", "/v1/projects/videos/play/none.mp4", controllers.ResourceProjects, controllers.PermissionRead},
+
+		{http.MethodGet, "/v1/projects", "/v1/projects", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodGet, "/v1/projects/{id}", "/v1/projects/999", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/projects", "/v1/projects", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPut, "/v1/projects/{id}", "/v1/projects/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/projects/{id}", "/v1/projects/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPost, "/v1/projects/{id}/cover", "/v1/projects/999/cover", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPatch, "/v1/projects/{id}/cover", "/v1/projects/999/cover", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/search", "/v1/projects/search", controllers.ResourceProjects, controllers.PermissionRead},
+
+		{http.MethodGet, "/v1/users/profile/favourites", "/v1/users/profile/favourites", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/users/profile/favourites/{id}", "/v1/users/profile/favourites/999", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodDelete, "/v1/users/profile/favourites/{id}", "/v1/users/profile/favourites/999", controllers.ResourceProjects, controllers.PermissionRead},
+
+		{http.MethodGet, "/v1/users/profile/watchlist", "/v1/users/profile/watchlist", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/users/profile/watchlist/{id}", "/v1/users/profile/watchlist/999", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodDelete, "/v1/users/profile/watchlist/{id}", "/v1/users/profile/watchlist/999", controllers.ResourceProjects, controllers.PermissionRead},
+
+		{http.MethodGet, "/v1/users/profile/progress/{id}", "/v1/users/profile/progress/none.mp4", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPut, "/v1/users/profile/progress/{id}", "/v1/users/profile/progress/none.mp4", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodGet, "/v1/users/profile/continue-watching", "/v1/users/profile/continue-watching", controllers.ResourceProjects, controllers.PermissionRead},
+
+		{http.MethodGet, "/v1/projects/{id}/rating", "/v1/projects/999/rating", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPut, "/v1/projects/{id}/rating", "/v1/projects/999/rating", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodDelete, "/v1/projects/{id}/rating", "/v1/projects/999/rating", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodGet, "/v1/projects/{id}/ratings", "/v1/projects/999/ratings", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPatch, "/v1/projects/ratings/{id}", "/v1/projects/ratings/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/users/profile/ratings", "/v1/users/profile/ratings", controllers.ResourceProjects, controllers.PermissionRead},
+
+		{http.MethodGet, "/v1/projects/{id}/seasons", "/v1/projects/999/seasons", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/projects/{id}/seasons", "/v1/projects/999/seasons", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPut, "/v1/projects/seasons/{id}", "/v1/projects/seasons/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/projects/seasons/{id}", "/v1/projects/seasons/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPost, "/v1/projects/seasons/{id}/episodes", "/v1/projects/seasons/999/episodes", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPut, "/v1/projects/episodes/{id}", "/v1/projects/episodes/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/projects/episodes/{id}", "/v1/projects/episodes/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/videos/{id}/hls/*", "/v1/projects/videos/999/hls/master.m3u8", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/projects/videos/{id}/hls", "/v1/projects/videos/999/hls", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/{id}/credits", "/v1/projects/999/credits", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/projects/{id}/credits", "/v1/projects/999/credits", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/projects/credits/{id}", "/v1/projects/credits/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/people", "/v1/people", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/people", "/v1/people", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/people/{id}", "/v1/people/999", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPut, "/v1/people/{id}", "/v1/people/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/people/{id}", "/v1/people/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPost, "/v1/people/{id}/photo", "/v1/people/999/photo", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPost, "/v1/uploads", "/v1/uploads", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodHead, "/v1/uploads/{id}", "/v1/uploads/none", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodPatch, "/v1/uploads/{id}", "/v1/uploads/none", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/uploads/{id}", "/v1/uploads/none", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/videos/{id}/subtitles", "/v1/projects/videos/none.mp4/subtitles", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPost, "/v1/projects/videos/{id}/subtitles", "/v1/projects/videos/none.mp4/subtitles", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/subtitles/{id}", "/v1/projects/subtitles/999", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodPut, "/v1/projects/subtitles/{id}", "/v1/projects/subtitles/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodDelete, "/v1/projects/subtitles/{id}", "/v1/projects/subtitles/999", controllers.ResourceProjects, controllers.PermissionWrite},
+		{http.MethodGet, "/v1/projects/videos/{id}/signed-url", "/v1/projects/videos/none.mp4/signed-url", controllers.ResourceProjects, controllers.PermissionRead},
+		{http.MethodGet, "/v1/projects/images/{id}/signed-url", "/v1/projects/images/none.png/signed-url", controllers.ResourceProjects, controllers.PermissionRead},
+	}
+
+	// routes which don't require any permission
+	public := map[string]bool{
+		"GET /":                             true,
+		"GET /hello":                        true,
+		"GET /swagger/*":                    true,
+		"POST /v1/auth/sign-in":             true,
+		"POST /v1/auth/sign-in/2fa":         true,
+		"POST /v1/auth/refresh":             true,
+		"POST /v1/auth/sign-out":            true,
+		"POST /v1/auth/forgot-password":     true,
+		"POST /v1/auth/reset-password":      true,
+		"POST /v1/auth/verify-email":        true,
+		"POST /v1/auth/resend-verification": true,
+		"PUT /v1/users/profile/password":    true,
+		"POST /v1/users":                    true,
+		"GET /v1/users/profile":             true,
+		"PUT /v1/users/profile":             true,
+		"DELETE /v1/users/profile":          true,
+		// own sessions of the signed in user
+		"GET /v1/users/profile/sessions":         true,
+		"DELETE /v1/users/profile/sessions":      true,
+		"DELETE /v1/users/profile/sessions/{id}": true,
+		"POST /v1/users/profile/2fa":             true,
+		"POST /v1/users/profile/2fa/confirm":     true,
+		"DELETE /v1/users/profile/2fa":           true,
+		"OPTIONS /v1/uploads":                    true,
+		// signed URLs are checked instead of the Authorization header
+		"GET /v1/media/videos/{id}":                             true,
+		"GET /v1/media/images/{id}":                             true,
+		"GET /v1/media/hls/{id}/{user}/{expires}/{signature}/*": true,
+	}
+
+	declared := map[string]bool{}
+	for _, tt := range tests {
+		declared[tt.method+" "+tt.route] = true
+	}
+	registered := map[string]bool{}
+	err := chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		route = strings.Replace(route, "/*/", "/", 1)
+		registered[method+" "+route] = true
+		if !public[method+" "+route] && !declared[method+" "+route] {
+			t.Errorf("route %s %s isn't covered by permissions test", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for route := range declared {
+		if !registered[route] {
+			t.Errorf("route %s isn't registered", route)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.route, func(t *testing.T) {
+			do := func(token string) int {
+				return doRequest(router, tt.method, tt.path, token).Code
+			}
+
+			if code := do(denied[tt.resource][tt.level]); code != http.StatusForbidden {
+				t.Errorf("user below %s level %d: got status %d, want %d", tt.resource, tt.level, code, http.StatusForbidden)
+			}
+			if code := do(allowed[tt.resource][tt.level]); code == http.StatusForbidden || code == http.StatusUnauthorized {
+				t.Errorf("user with %s level %d: got status %d", tt.resource, tt.level, code)
+			}
+		})
+	}
+}
+
+func doRequest(router http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFavouritesAndWatchlist(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	token := createTestUser(t, cfg, router, "viewer", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+
+	for _, list := range []string{"favourites", "watchlist"} {
+		t.Run(list, func(t *testing.T) {
+			path := "/v1/users/profile/" + list
+
+			for _, id := range []string{"2", "1", "2"} {
+				if rec := doRequest(router, http.MethodPost, path+"/"+id, token); rec.Code != http.StatusCreated {
+					t.Fatalf("add %s: status %d: %s", id, rec.Code, rec.Body.String())
+				}
+			}
+			if rec := doRequest(router, http.MethodPost, path+"/999", token); rec.Code != http.StatusNotFound {
+				t.Errorf("add missing project: status %d, want %d", rec.Code, http.StatusNotFound)
+			}
+
+			projectIds := func() []int64 {
+				rec := doRequest(router, http.MethodGet, path, token)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("list: status %d: %s", rec.Code, rec.Body.String())
+				}
+				projects := []views.Project{}
+				if err := json.Unmarshal(rec.Body.Bytes(), &projects); err != nil {
+					t.Fatal(err)
+				}
+				ids := []int64{}
+				for _, p := range projects {
+					ids = append(ids, p.ID)
+				}
+				return ids
+			}
+
+			if got := fmt.Sprint(projectIds()); got != "[1 2]" {
+				t.Errorf("list = %s, want [1 2]", got)
+			}
+
+			if rec := doRequest(router, http.MethodDelete, path+"/1", token); rec.Code != http.StatusOK {
+				t.Fatalf("delete: status %d: %s", rec.Code, rec.Body.String())
+			}
+			if got := fmt.Sprint(projectIds()); got != "[2]" {
+				t.Errorf("list after delete = %s, want [2]", got)
+			}
+		})
+	}
+}
+
+func TestContinueWatching(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	token := createTestUser(t, cfg, router, "viewer", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+
+	for _, v := range []database.AddVideo2SeriesParams{
+		{ID: "s1e1.mp4", ProjectID: 1, Season: 1, Serie: 1},
+		{ID: "s1e2.mp4", ProjectID: 1, Season: 1, Serie: 2},
+	} {
+		if err := cfg.DB.AddVideo2Series(t.Context(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save := func(videoID string, position int64) {
+		body, _ := json.Marshal(views.SaveProgressRequest{PositionInSecs: position, DurationInSecs: 1000})
+		req := httptest.NewRequest(http.MethodPut, "/v1/users/profile/progress/"+videoID, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("save progress: status %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	next := func() []views.ContinueWatching {
+		rec := doRequest(router, http.MethodGet, "/v1/users/profile/continue-watching", token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("continue watching: status %d: %s", rec.Code, rec.Body.String())
+		}
+		cw := []views.ContinueWatching{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &cw); err != nil {
+			t.Fatal(err)
+		}
+		return cw
+	}
+
+	save("s1e1.mp4", 100)
+	if cw := next(); len(cw) != 1 || cw[0].Video.ID != "s1e1.mp4" || cw[0].Progress.PositionInSecs != 100 {
+		t.Errorf("after starting s1e1: %+v", cw)
+	}
+
+	save("s1e1.mp4", 990)
+	if cw := next(); len(cw) != 1 || cw[0].Video.ID != "s1e2.mp4" || cw[0].Project.ID != 1 {
+		t.Errorf("after watching s1e1: %+v", cw)
+	}
+
+	save("s1e2.mp4", 1000)
+	if cw := next(); len(cw) != 0 {
+		t.Errorf("after watching everything: %+v", cw)
+	}
+}
+
+func TestRatings(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	viewer := createTestUser(t, cfg, router, "viewer", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+	critic := createTestUser(t, cfg, router, "critic", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+	moderator := createTestUser(t, cfg, router, "moderator", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	send := func(method, path, token string, v any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	list := func(token string) ([]views.Rating, string) {
+		rec := doRequest(router, http.MethodGet, "/v1/projects/1/ratings", token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("list ratings: status %d: %s", rec.Code, rec.Body.String())
+		}
+		ratings := []views.Rating{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &ratings); err != nil {
+			t.Fatal(err)
+		}
+		return ratings, rec.Header().Get("X-Total-Count")
+	}
+
+	for _, tc := range []struct {
+		path   string
+		stars  int64
+		status int
+	}{
+		{"/v1/projects/1/rating", 0, http.StatusBadRequest},
+		{"/v1/projects/1/rating", 6, http.StatusBadRequest},
+		{"/v1/projects/999/rating", 3, http.StatusNotFound},
+	} {
+		if rec := send(http.MethodPut, tc.path, viewer, views.RateProjectRequest{Stars: tc.stars}); rec.Code != tc.status {
+			t.Errorf("rate %s with %d stars: status %d, want %d", tc.path, tc.stars, rec.Code, tc.status)
+		}
+	}
+
+	if rec := send(http.MethodPut, "/v1/projects/1/rating", viewer, views.RateProjectRequest{Stars: 2, Review: "meh"}); rec.Code != http.StatusOK {
+		t.Fatalf("rate: status %d: %s", rec.Code, rec.Body.String())
+	}
+	// rating again replaces the previous one
+	if rec := send(http.MethodPut, "/v1/projects/1/rating", viewer, views.RateProjectRequest{Stars: 4, Review: "good"}); rec.Code != http.StatusOK {
+		t.Fatalf("re-rate: status %d: %s", rec.Code, rec.Body.String())
+	}
+	rec := send(http.MethodPut, "/v1/projects/1/rating", critic, views.RateProjectRequest{Stars: 1, Review: "spam"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rate: status %d: %s", rec.Code, rec.Body.String())
+	}
+	spam := views.Rating{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spam); err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := repositories.NewProjectsRepository(cfg.Conn).GetById(t.Context(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Rating.Count != 2 || project.Rating.Average != 2.5 {
+		t.Errorf("rating stats = %+v, want 2 ratings with 2.5 average", project.Rating)
+	}
+
+	if rec := send(http.MethodPatch, "/v1/projects/ratings/"+strconv.FormatInt(spam.ID, 10), viewer, views.HideRatingRequest{Hidden: true}); rec.Code != http.StatusForbidden {
+		t.Errorf("hide by viewer: status %d, want 403", rec.Code)
+	}
+	if rec := send(http.MethodPatch, "/v1/projects/ratings/"+strconv.FormatInt(spam.ID, 10), moderator, views.HideRatingRequest{Hidden: true}); rec.Code != http.StatusOK {
+		t.Fatalf("hide by moderator: status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if ratings, total := list(viewer); len(ratings) != 1 || ratings[0].Review != "good" || ratings[0].UserName != "viewer" || total != "1" {
+		t.Errorf("viewer sees %+v, total %s", ratings, total)
+	}
+	if ratings, total := list(moderator); len(ratings) != 2 || total != "2" {
+		t.Errorf("moderator sees %+v, total %s", ratings, total)
+	}
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/1/rating", viewer); rec.Code != http.StatusOK {
+		t.Fatalf("delete rating: status %d", rec.Code)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/projects/1/rating", viewer); rec.Code != http.StatusNotFound {
+		t.Errorf("get deleted rating: status %d, want 404", rec.Code)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users/profile/ratings", critic); rec.Code != http.StatusOK || rec.Header().Get("X-Total-Count") != "1" {
+		t.Errorf("own ratings: status %d, total %s", rec.Code, rec.Header().Get("X-Total-Count"))
+	}
+}
+
+func TestProjectsList(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	token := createTestUser(t, cfg, router, "viewer", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+
+	// project 2 is the best rated one, project 1 the worst
+	for projectID, stars := range map[int64]int64{1: 1, 2: 5, 4: 3} {
+		if err := cfg.DB.RateProject(t.Context(), database.RateProjectParams{UserID: 1, ProjectID: projectID, Stars: stars}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query     string
+		wantIds   []int64
+		wantTotal string
+	}{
+		{"", []int64{1, 2, 3, 4}, "4"},
+		{"?limit=2&offset=1", []int64{2, 3}, "4"},
+		{"?order=desc", []int64{4, 3, 2, 1}, "4"},
+		{"?sort=release_year", []int64{2, 4, 1, 3}, "4"},
+		{"?sort=release_year&order=desc&limit=1", []int64{3}, "4"},
+		{"?sort=title", []int64{2, 4, 3, 1}, "4"},
+		{"?sort=rating&order=desc", []int64{2, 4, 1, 3}, "4"},
+		{"?type_id=1", []int64{4}, "1"},
+		{"?genre_id=7", []int64{1, 2, 3}, "3"},
+		{"?age_category_id=2&genre_id=4", []int64{2, 3}, "2"},
+		{"?year_from=2019&year_to=2020", []int64{1, 4}, "2"},
+		{"?type_id=4&sort=release_year&order=desc&limit=1&offset=1", []int64{1}, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := doRequest(router, http.MethodGet, "/v1/projects"+tt.query, token)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			projects := []views.Project{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &projects); err != nil {
+				t.Fatal(err)
+			}
+			ids := []int64{}
+			for _, p := range projects {
+				ids = append(ids, p.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIds) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIds)
+			}
+			if total := rec.Header().Get("X-Total-Count"); total != tt.wantTotal {
+				t.Errorf("total = %s, want %s", total, tt.wantTotal)
+			}
+		})
+	}
+
+	for _, query := range []string{"?limit=0", "?sort=director", "?order=up", "?type_id=one"} {
+		if rec := doRequest(router, http.MethodGet, "/v1/projects"+query, token); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestProjectsSearch(t *testing.T) {
+	cfg := newTestConfig(t)
+	if !dbtest.HasFTS5(t, cfg.Conn) {
+		if os.Getenv("CI") != "" {
+			t.Fatal("go-sqlite3 is built without FTS5, CI must run tests with -tags sqlite_fts5")
+		}
+		t.Skip("go-sqlite3 is built without FTS5, run tests with -tags sqlite_fts5")
+	}
+	router := newRouter(cfg)
+	token := createTestUser(t, cfg, router, "viewer", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+
+	search := func(query string) ([]views.ProjectSearchResult, string) {
+		rec := doRequest(router, http.MethodGet, "/v1/projects/search"+query, token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", query, rec.Code, rec.Body.String())
+		}
+		results := []views.ProjectSearchResult{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+			t.Fatal(err)
+		}
+		return results, rec.Header().Get("X-Total-Count")
+	}
+	ids := func(results []views.ProjectSearchResult) []int64 {
+		ids := []int64{}
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantIds []int64
+	}{
+		{"Title is ranked higher than description", "?searchTerm=" + url.QueryEscape("ойыншық"), []int64{3}},
+		{"Prefix of a word", "?searchTerm=" + url.QueryEscape("Айда"), []int64{2}},
+		{"Case insensitive", "?searchTerm=" + url.QueryEscape("КАНИКУЛЫ"), []int64{4}},
+		{"All the words", "?searchTerm=" + url.QueryEscape("Қасиетті тұмар"), []int64{2}},
+		{"Syntax is not interpreted", "?searchTerm=" + url.QueryEscape(`"Айдар*) (`), []int64{2}},
+		{"Genre filter", "?searchTerm=cinema&genre_id=2&genre_id=7", []int64{1}},
+		{"Genre filter excludes", "?searchTerm=cinema&genre_id=4", []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, _ := search(tt.query)
+			if got := ids(results); fmt.Sprint(got) != fmt.Sprint(tt.wantIds) {
+				t.Errorf("ids = %v, want %v", got, tt.wantIds)
+			}
+		})
+	}
+
+	results, _ := search("?searchTerm=" + url.QueryEscape("айдар"))
+	if len(results) == 0 || results[0].TitleHighlight != "<mark>Айдар</mark>" || !strings.Contains(results[0].DescriptionSnippet, "<mark>Айдар</mark>") {
+		t.Errorf("highlights = %+v", results)
+	}
+
+	// the text of the project isn't markup
+	if err := cfg.DB.UpdateProject(t.Context(), database.UpdateProjectParams{
+		ID:          2,
+		Title:       `Айдар <img src=x onerror="alert(1)">`,
+		Description: results[0].Description,
+		TypeID:      results[0].Type.ID,
+		ReleaseYear: results[0].ReleaseYear,
+		Keywords:    results[0].Keywords,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	results, _ = search("?searchTerm=" + url.QueryEscape("айдар"))
+	if want := "<mark>Айдар</mark> &lt;img src=x onerror=&#34;alert(1)&#34;&gt;"; len(results) == 0 || results[0].TitleHighlight != want {
+		t.Errorf("highlights = %+v, want title %s", results, want)
+	}
+
+	// the index follows updates of projects
+	rec := doRequest(router, http.MethodGet, "/v1/projects/3", token)
+	project := views.Project{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &project); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DB.UpdateProject(t.Context(), database.UpdateProjectParams{
+		ID:          3,
+		Title:       "Жаңа ойын",
+		Description: project.Description,
+		TypeID:      project.Type.ID,
+		ReleaseYear: project.ReleaseYear,
+		Keywords:    "ертегілер",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if results, total := search("?searchTerm=" + url.QueryEscape("ертегі")); fmt.Sprint(ids(results)) != "[3]" || total != "1" {
+		t.Errorf("after update: %v, total %s", ids(results), total)
+	}
+
+	if err := cfg.DB.DeleteProject(t.Context(), 3); err != nil {
+		t.Fatal(err)
+	}
+	if results, total := search("?searchTerm=" + url.QueryEscape("ертегі")); len(results) != 0 || total != "0" {
+		t.Errorf("after delete: %v, total %s", ids(results), total)
+	}
+}
+
+func TestProjectsOfGenres(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	token := createTestUser(t, cfg, router, "viewer", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+
+	tests := []struct {
+		query     string
+		wantIds   []int64
+		wantTotal string
+	}{
+		// projects of both genres are listed once
+		{"?genre_id=6&genre_id=7", []int64{1, 2, 3}, "3"},
+		{"?genre_id=6&genre_id=7&limit=2", []int64{1, 2}, "3"},
+		{"?genre_id=6&genre_id=7&limit=2&offset=2", []int64{3}, "3"},
+		{"?genre_id=1", []int64{4}, "1"},
+		{"", []int64{}, "0"},
+	}
+	for _, tt := range tests {
+		rec := doRequest(router, http.MethodGet, "/v1/projects/search"+tt.query, token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", tt.query, rec.Code, rec.Body.String())
+		}
+		results := []views.ProjectSearchResult{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+			t.Fatal(err)
+		}
+		ids := []int64{}
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.wantIds) || rec.Header().Get("X-Total-Count") != tt.wantTotal {
+			t.Errorf("%s: ids %v, total %s, want %v, total %s", tt.query, ids, rec.Header().Get("X-Total-Count"), tt.wantIds, tt.wantTotal)
+		}
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/v1/projects/search?genre_id=7&limit=0", token); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid limit: status %d, want 400", rec.Code)
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	if err := cfg.DB.AddImage2Movie(t.Context(), database.AddImage2MovieParams{ID: "cover.png", ProjectID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []database.AddVideo2SeriesParams{
+		{ID: "s1e1.mp4", ProjectID: 1, Season: 1, Serie: 1},
+		{ID: "s1e2.mp4", ProjectID: 1, Season: 1, Serie: 2},
+	} {
+		if err := cfg.DB.AddVideo2Series(t.Context(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// s1e2.mp4 has no file
+	for _, name := range []string{"cover.png", "s1e1.mp4"} {
+		if err := os.WriteFile(cfg.Dir+name, []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.DB.RateProject(t.Context(), database.RateProjectParams{UserID: 1, ProjectID: 1, Stars: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DB.AddProject2Favourites(t.Context(), database.AddProject2FavouritesParams{UserID: 1, ProjectID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := doRequest(router, http.MethodDelete, "/v1/projects/1", editor)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("delete: status %d: %s", rec.Code, rec.Body.String())
+	}
+	deleted := views.DeletedProject{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &deleted); err != nil {
+		t.Fatal(err)
+	}
+	if deleted.ID != 1 || fmt.Sprint(deleted.Images) != "[cover.png]" || fmt.Sprint(deleted.Videos) != "[s1e1.mp4 s1e2.mp4]" || len(deleted.FailedFiles) != 0 {
+		t.Errorf("deleted = %+v", deleted)
+	}
+
+	for _, name := range []string{"cover.png", "s1e1.mp4"} {
+		if _, err := os.Stat(cfg.Dir + name); !os.IsNotExist(err) {
+			t.Errorf("%s wasn't removed: %v", name, err)
+		}
+	}
+	for table, query := range map[string]string{
+		"images":          "SELECT COUNT(*) FROM images WHERE project_id = 1",
+		"videos":          "SELECT COUNT(*) FROM videos WHERE project_id = 1",
+		"projects_genres": "SELECT COUNT(*) FROM projects_genres WHERE project_id = 1",
+		"ratings":         "SELECT COUNT(*) FROM ratings WHERE project_id = 1",
+		"favourites":      "SELECT COUNT(*) FROM favourites WHERE project_id = 1",
+	} {
+		var count int
+		if err := cfg.Conn.QueryRow(query).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("%s has %d rows of the deleted project", table, count)
+		}
+	}
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/1", editor); rec.Code != http.StatusNotFound {
+		t.Errorf("delete again: status %d, want 404", rec.Code)
+	}
+}
+
+func TestSeasonsAndEpisodes(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	send := func(method, path string, v any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+editor)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	create := func(path string, v any) string {
+		rec := send(http.MethodPost, path, v)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("create %s: status %d: %s", path, rec.Code, rec.Body.String())
+		}
+		id := views.ResponseId{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &id); err != nil {
+			t.Fatal(err)
+		}
+		return strconv.Itoa(id.ID)
+	}
+	seasons := func() []views.Season {
+		rec := doRequest(router, http.MethodGet, "/v1/projects/1/seasons", editor)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("seasons: status %d: %s", rec.Code, rec.Body.String())
+		}
+		seasons := []views.Season{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &seasons); err != nil {
+			t.Fatal(err)
+		}
+		return seasons
+	}
+
+	if err := cfg.DB.AddImage2Movie(t.Context(), database.AddImage2MovieParams{ID: "thumb.png", ProjectID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DB.AddImage2Movie(t.Context(), database.AddImage2MovieParams{ID: "other.png", ProjectID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DB.AddVideo2Series(t.Context(), database.AddVideo2SeriesParams{ID: "e1.mp4", ProjectID: 1, Season: 1, Serie: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	season2 := create("/v1/projects/1/seasons", views.CreateSeasonRequest{Number: 2, Title: "Second"})
+	season1 := create("/v1/projects/1/seasons", views.CreateSeasonRequest{Number: 1, Title: "First"})
+	if rec := send(http.MethodPost, "/v1/projects/1/seasons", views.CreateSeasonRequest{Number: 1}); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate season: status %d, want 409", rec.Code)
+	}
+	if rec := send(http.MethodPost, "/v1/projects/999/seasons", views.CreateSeasonRequest{Number: 1}); rec.Code != http.StatusNotFound {
+		t.Errorf("season of missing project: status %d, want 404", rec.Code)
+	}
+
+	episode := create("/v1/projects/seasons/"+season1+"/episodes", views.CreateEpisodeRequest{
+		Number:         1,
+		Title:          "Pilot",
+		DurationInSecs: 1500,
+		ReleaseDate:    "2020-01-31",
+		ThumbnailID:    "thumb.png",
+		VideoID:        "e1.mp4",
+	})
+	create("/v1/projects/seasons/"+season2+"/episodes", views.CreateEpisodeRequest{Number: 1, Title: "Return"})
+
+	for name, cer := range map[string]views.CreateEpisodeRequest{
+		"bad date":           {Number: 3, ReleaseDate: "31.01.2020"},
+		"thumbnail of other": {Number: 3, ThumbnailID: "other.png"},
+		"missing video":      {Number: 3, VideoID: "none.mp4"},
+		"negative duration":  {Number: 3, DurationInSecs: -1},
+	} {
+		if rec := send(http.MethodPost, "/v1/projects/seasons/"+season1+"/episodes", cer); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", name, rec.Code)
+		}
+	}
+	if rec := send(http.MethodPost, "/v1/projects/seasons/"+season1+"/episodes", views.CreateEpisodeRequest{Number: 1}); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate episode: status %d, want 409", rec.Code)
+	}
+
+	got := seasons()
+	if len(got) != 2 || got[0].Title != "First" || got[1].Title != "Second" {
+		t.Fatalf("seasons = %+v", got)
+	}
+	e := got[0].Episodes
+	if len(e) != 1 || e[0].Title != "Pilot" || e[0].Thumbnail.ID != "thumb.png" || e[0].Video.ID != "e1.mp4" || e[0].ReleaseDate != "2020-01-31" {
+		t.Errorf("episodes of first season = %+v", e)
+	}
+
+	if rec := send(http.MethodPut, "/v1/projects/episodes/"+episode, views.UpdateEpisodeRequest{Number: 1, Title: "Pilot (extended)"}); rec.Code != http.StatusOK {
+		t.Fatalf("update episode: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := send(http.MethodPut, "/v1/projects/seasons/"+season2, views.UpdateSeasonRequest{Number: 1}); rec.Code != http.StatusConflict {
+		t.Errorf("update season to taken number: status %d, want 409", rec.Code)
+	}
+	if e := seasons()[0].Episodes; e[0].Title != "Pilot (extended)" || e[0].Video.ID != "" {
+		t.Errorf("updated episode = %+v", e[0])
+	}
+
+	// renumbering moves the videos along, so they stay the episode of the season
+	if rec := send(http.MethodPut, "/v1/projects/seasons/"+season1, views.UpdateSeasonRequest{Number: 3, Title: "First"}); rec.Code != http.StatusOK {
+		t.Fatalf("renumber season: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := send(http.MethodPut, "/v1/projects/episodes/"+episode, views.UpdateEpisodeRequest{Number: 4, Title: "Pilot (extended)"}); rec.Code != http.StatusOK {
+		t.Fatalf("renumber episode: status %d: %s", rec.Code, rec.Body.String())
+	}
+	video, err := cfg.DB.GetVideoById(t.Context(), "e1.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if video.Season != 3 || video.Serie != 4 {
+		t.Errorf("video of renumbered episode: season %d, serie %d, want 3, 4", video.Season, video.Serie)
+	}
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/seasons/"+season2, editor); rec.Code != http.StatusOK {
+		t.Fatalf("delete season: status %d", rec.Code)
+	}
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/episodes/"+episode, editor); rec.Code != http.StatusOK {
+		t.Fatalf("delete episode: status %d", rec.Code)
+	}
+	if got := seasons(); len(got) != 1 || len(got[0].Episodes) != 0 {
+		t.Errorf("after deletes: %+v", got)
+	}
+	var episodes int
+	if err := cfg.Conn.QueryRow("SELECT COUNT(*) FROM episodes").Scan(&episodes); err != nil {
+		t.Fatal(err)
+	}
+	if episodes != 0 {
+		t.Errorf("%d episodes are left", episodes)
+	}
+}
+
+// testMP4 encodes the boxes of an MP4 video
+// with a video track of width x height
+func testMP4(t *testing.T, duration time.Duration, width, height int) []byte {
+	t.Helper()
+
+	box := func(typ string, content ...[]byte) []byte {
+		data := bytes.Join(content, nil)
+		return append(binary.BigEndian.AppendUint32(nil, uint32(8+len(data))), append([]byte(typ), data...)...)
+	}
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], uint32(duration.Milliseconds()))
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], uint32(height)<<16)
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:], "vide")
+
+	return bytes.Join([][]byte{
+		box("ftyp", []byte("isom\x00\x00\x02\x00isomiso2avc1mp41")),
+		box("moov", box("mvhd", mvhd), box("trak", box("tkhd", tkhd), box("mdia", box("hdlr", hdlr)))),
+		box("mdat", []byte("not really frames")),
+	}, nil)
+}
+
+// postTestVideo posts the video of the project as the serie of the season
+func postTestVideo(t *testing.T, router http.Handler, token, projectID, season, serie string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for name, value := range map[string]string{"project_id": projectID, "season": season, "serie": serie} {
+		if err := form.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="video"; filename="video.mp4"`)
+	header.Set("Content-Type", "video/mp4")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/videos", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// uploadTestVideo uploads a 125 seconds 1280x720 video of the project
+// as the serie of the season and returns its id
+func uploadTestVideo(t *testing.T, router http.Handler, token, projectID, season, serie string) string {
+	t.Helper()
+
+	rec := postTestVideo(t, router, token, projectID, season, serie, testMP4(t, 125*time.Second, 1280, 720))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload: status %d: %s", rec.Code, rec.Body.String())
+	}
+	video := views.ResponseIdStr{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &video); err != nil {
+		t.Fatal(err)
+	}
+	return video.ID
+}
+
+func TestVideoUploadCreatesEpisode(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+	// the duration of the project is filled by the video
+	if _, err := cfg.Conn.Exec("UPDATE projects SET duration_in_mins = 0 WHERE id = 2"); err != nil {
+		t.Fatal(err)
+	}
+
+	video := uploadTestVideo(t, router, editor, "2", "3", "4")
+
+	rec := doRequest(router, http.MethodGet, "/v1/projects/2/seasons", editor)
+	seasons := []views.Season{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &seasons); err != nil {
+		t.Fatal(err)
+	}
+	if len(seasons) != 1 || seasons[0].Number != 3 || len(seasons[0].Episodes) != 1 ||
+		seasons[0].Episodes[0].Number != 4 || seasons[0].Episodes[0].Video.ID != video {
+		t.Fatalf("seasons = %+v", seasons)
+	}
+	episode := seasons[0].Episodes[0]
+	if episode.DurationInSecs != 125 || episode.Video.DurationInSecs != 125 || episode.Video.Width != 1280 || episode.Video.Height != 720 {
+		t.Errorf("episode = %+v", episode)
+	}
+
+	project := views.Project{}
+	rec = doRequest(router, http.MethodGet, "/v1/projects/2", editor)
+	if err := json.Unmarshal(rec.Body.Bytes(), &project); err != nil {
+		t.Fatal(err)
+	}
+	if project.DurationInMins != 2 {
+		t.Errorf("duration of project %d, want 2", project.DurationInMins)
+	}
+	// the set duration isn't changed
+	uploadTestVideo(t, router, editor, "1", "1", "1")
+	rec = doRequest(router, http.MethodGet, "/v1/projects/1", editor)
+	if err := json.Unmarshal(rec.Body.Bytes(), &project); err != nil {
+		t.Fatal(err)
+	}
+	if project.DurationInMins != 7 {
+		t.Errorf("duration of project %d, want 7", project.DurationInMins)
+	}
+
+	// the video isn't saved without its episode
+	if _, err := cfg.Conn.Exec("CREATE TRIGGER fail_episodes BEFORE INSERT ON episodes BEGIN SELECT RAISE(ABORT, 'failed'); END"); err != nil {
+		t.Fatal(err)
+	}
+	if rec := postTestVideo(t, router, editor, "2", "5", "1", testMP4(t, time.Minute, 640, 360)); rec.Code != http.StatusInternalServerError {
+		t.Errorf("upload with failing episode: status %d, want 500", rec.Code)
+	}
+	videos, err := cfg.DB.GetVideosOfProject(t.Context(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(videos) != 1 {
+		t.Errorf("videos after failed upload: %+v", videos)
+	}
+	if _, err := cfg.DB.GetSeasonByNumber(t.Context(), database.GetSeasonByNumberParams{ProjectID: 2, Number: 5}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("season of failed upload: %v", err)
+	}
+	files, err := cfg.Storage.List(t.Context(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("files after failed upload: %+v", files)
+	}
+}
+
+func TestVideoUploadValidation(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	video := testMP4(t, time.Minute, 640, 360)
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "not a video", content: []byte("not really a video")},
+		{name: "image", content: testPNG(t, 10, 10)},
+		{name: "truncated", content: video[:len(video)-5]},
+	}
+	for _, tt := range tests {
+		if rec := postTestVideo(t, router, editor, "1", "1", "1", tt.content); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", tt.name, rec.Code)
+		}
+	}
+
+	videos, err := cfg.DB.GetVideosOfProject(t.Context(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(videos) != 0 {
+		t.Errorf("invalid videos are saved: %+v", videos)
+	}
+
+	// an image sent as a PNG must be a PNG
+	var jpegImage bytes.Buffer
+	if err := jpeg.Encode(&jpegImage, image.NewRGBA(image.Rect(0, 0, 10, 10)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if rec := uploadTestImage(t, router, editor, "/v1/projects/images", map[string]string{"project_id": "1"}, jpegImage.Bytes()); rec.Code != http.StatusBadRequest {
+		t.Errorf("JPEG sent as PNG: status %d, want 400", rec.Code)
+	}
+	if rec := uploadTestImage(t, router, editor, "/v1/people/1/photo", nil, []byte("not an image")); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid photo: status %d, want 400", rec.Code)
+	}
+}
+
+func TestPeopleAndCredits(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	send := func(method, path string, v any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+editor)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	credits := func(projectID string) []views.Credit {
+		rec := doRequest(router, http.MethodGet, "/v1/projects/"+projectID+"/credits", editor)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("credits: status %d: %s", rec.Code, rec.Body.String())
+		}
+		credits := []views.Credit{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &credits); err != nil {
+			t.Fatal(err)
+		}
+		return credits
+	}
+
+	// director and producer strings are migrated into credits
+	migrated := credits("4")
+	got := []string{}
+	for _, c := range migrated {
+		got = append(got, c.Role+":"+c.Name)
+	}
+	want := []string{"director:Ернар Курмашев", "director:Руслан Акун", "producer:Акан Сатаев"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("migrated credits = %v, want %v", got, want)
+	}
+	if c := credits("2"); len(c) != 0 {
+		t.Errorf("credits of project without director = %+v", c)
+	}
+
+	// director and producer of the project are the names of the credits
+	project := func(id string) views.Project {
+		rec := doRequest(router, http.MethodGet, "/v1/projects/"+id, editor)
+		project := views.Project{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &project); err != nil {
+			t.Fatal(err)
+		}
+		return project
+	}
+	p4 := project("4")
+	if p4.Director != "Ернар Курмашев, Руслан Акун" || p4.Producer != "Акан Сатаев" {
+		t.Errorf("director %q, producer %q of project", p4.Director, p4.Producer)
+	}
+	if rec := send(http.MethodPut, "/v1/projects/4", views.UpdateProjectRequest{
+		Title:       p4.Title,
+		Description: p4.Description,
+		TypeID:      p4.Type.ID,
+		ReleaseYear: p4.ReleaseYear,
+		Director:    "Басқа режиссер",
+	}); rec.Code != http.StatusOK {
+		t.Fatalf("update project: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if p4 := project("4"); p4.Director != "Ернар Курмашев, Руслан Акун" {
+		t.Errorf("director is written: %q", p4.Director)
+	}
+
+	rec := send(http.MethodPost, "/v1/people", views.CreatePersonRequest{Name: "Актер", Bio: "bio"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create person: status %d: %s", rec.Code, rec.Body.String())
+	}
+	id := views.ResponseId{}
+	json.Unmarshal(rec.Body.Bytes(), &id)
+	actor := strconv.Itoa(id.ID)
+	if rec := send(http.MethodPost, "/v1/people", views.CreatePersonRequest{Name: " "}); rec.Code != http.StatusBadRequest {
+		t.Errorf("person without name: status %d, want 400", rec.Code)
+	}
+
+	if rec := send(http.MethodPost, "/v1/projects/4/credits", views.AddCreditRequest{PersonID: int64(id.ID), Role: "stuntman"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid role: status %d, want 400", rec.Code)
+	}
+	if rec := send(http.MethodPost, "/v1/projects/4/credits", views.AddCreditRequest{PersonID: 999, Role: "actor"}); rec.Code != http.StatusNotFound {
+		t.Errorf("missing person: status %d, want 404", rec.Code)
+	}
+	for _, project := range []string{"4", "1"} {
+		rec := send(http.MethodPost, "/v1/projects/"+project+"/credits", views.AddCreditRequest{PersonID: int64(id.ID), Role: "actor", Character: "Герой"})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("add credit: status %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	if rec := send(http.MethodPost, "/v1/projects/4/credits", views.AddCreditRequest{PersonID: int64(id.ID), Role: "actor", Character: "Герой"}); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate credit: status %d, want 409", rec.Code)
+	}
+
+	if c := credits("4"); len(c) != 4 || c[3].Role != "actor" || c[3].Character != "Герой" {
+		t.Errorf("credits after adding actor = %+v", c)
+	}
+
+	rec = doRequest(router, http.MethodGet, "/v1/people/"+actor, editor)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("person: status %d: %s", rec.Code, rec.Body.String())
+	}
+	person := views.Person{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &person); err != nil {
+		t.Fatal(err)
+	}
+	// the latest projects first
+	if len(person.Filmography) != 2 || person.Filmography[0].Project.ID != 1 || person.Filmography[1].Project.ID != 4 {
+		t.Errorf("filmography = %+v", person.Filmography)
+	}
+
+	rec = doRequest(router, http.MethodGet, "/v1/people?search=Акун", editor)
+	people := []database.Person{}
+	json.Unmarshal(rec.Body.Bytes(), &people)
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Total-Count") != "1" || len(people) != 1 || people[0].Name != "Руслан Акун" {
+		t.Errorf("search people: status %d, people %+v", rec.Code, people)
+	}
+
+	if rec := send(http.MethodPut, "/v1/people/"+actor, views.UpdatePersonRequest{Name: "Актриса"}); rec.Code != http.StatusOK {
+		t.Errorf("update person: status %d", rec.Code)
+	}
+	if rec := send(http.MethodDelete, "/v1/projects/credits/"+strconv.FormatInt(person.Filmography[0].CreditID, 10), nil); rec.Code != http.StatusOK {
+		t.Errorf("delete credit: status %d", rec.Code)
+	}
+	if c := credits("1"); len(c) != 2 || c[0].Role != "director" || c[1].Role != "producer" {
+		t.Errorf("credits after deleting actor = %+v", c)
+	}
+
+	if rec := send(http.MethodDelete, "/v1/people/"+actor, nil); rec.Code != http.StatusOK {
+		t.Errorf("delete person: status %d", rec.Code)
+	}
+	if c := credits("4"); len(c) != 3 {
+		t.Errorf("credits after deleting person = %+v", c)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/people/"+actor, editor); rec.Code != http.StatusNotFound {
+		t.Errorf("deleted person: status %d, want 404", rec.Code)
+	}
+}
+
+func TestVideosHLS(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	// fake ffmpeg writes the playlist and a segment of the rendition
+	ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+	script := `#!/bin/sh
+for last; do :; done
+echo "#EXTM3U" > "$last"
+echo "ts" > "$(dirname "$last")/segment_0000.ts"
+`
+	if err := os.WriteFile(ffmpeg, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Packager = hls.NewPackager(cfg.DB, cfg.Storage, ffmpeg)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.Packager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	waitStatus := func(videoID, want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			video, err := cfg.DB.GetVideoById(context.Background(), videoID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if video.HlsStatus == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("status of %s = %s, want %s", videoID, video.HlsStatus, want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	video := uploadTestVideo(t, router, editor, "1", "1", "1")
+	waitStatus(video, hls.StatusReady)
+
+	prefix := "/v1/projects/videos/" + video + "/hls/"
+	tests := []struct {
+		file            string
+		wantCode        int
+		wantContentType string
+	}{
+		{file: hls.MasterPlaylist, wantCode: http.StatusOK, wantContentType: "application/vnd.apple.mpegurl"},
+		{file: "720p/index.m3u8", wantCode: http.StatusOK, wantContentType: "application/vnd.apple.mpegurl"},
+		{file: "360p/segment_0000.ts", wantCode: http.StatusOK, wantContentType: "video/mp2t"},
+		{file: "360p/segment_9999.ts", wantCode: http.StatusNotFound},
+		{file: "1080p/index.m3u8", wantCode: http.StatusNotFound},
+		{file: "4k/index.m3u8", wantCode: http.StatusNotFound},
+		{file: "360p/../../" + video, wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := doRequest(router, http.MethodGet, prefix+tt.file, editor)
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: status %d, want %d", tt.file, rec.Code, tt.wantCode)
+		}
+		if tt.wantContentType != "" && rec.Header().Get("Content-Type") != tt.wantContentType {
+			t.Errorf("%s: Content-Type %q, want %q", tt.file, rec.Header().Get("Content-Type"), tt.wantContentType)
+		}
+	}
+	// the 720p video isn't upscaled to 1080p
+	if rec := doRequest(router, http.MethodGet, prefix+hls.MasterPlaylist, editor); !strings.Contains(rec.Body.String(), "RESOLUTION=1280x720") || strings.Contains(rec.Body.String(), "1080p") {
+		t.Errorf("master playlist:\n%s", rec.Body.String())
+	}
+
+	// the playlists and segments are loaded by the relative URLs of the signed master playlist
+	rec := doRequest(router, http.MethodGet, "/v1/projects/videos/"+video+"/signed-url", editor)
+	signed := views.SignedURLResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &signed); err != nil || !strings.HasPrefix(signed.HlsURL, "/v1/media/hls/"+video+"/") {
+		t.Fatalf("signed URL: status %d, %+v, %v", rec.Code, signed, err)
+	}
+	master, err := url.Parse(signed.HlsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []string{hls.MasterPlaylist, "720p/index.m3u8", "360p/segment_0000.ts"} {
+		u := master.ResolveReference(&url.URL{Path: ref})
+		if rec := doRequest(router, http.MethodGet, u.String(), ""); rec.Code != http.StatusOK {
+			t.Errorf("signed %s: status %d, want 200", u, rec.Code)
+		}
+	}
+	for _, u := range []string{
+		strings.Replace(signed.HlsURL, video, "other.mp4", 1),
+		strings.Replace(signed.HlsURL, "/"+hls.MasterPlaylist, "x/"+hls.MasterPlaylist, 1),
+	} {
+		if rec := doRequest(router, http.MethodGet, u, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("tampered %s: status %d, want 401", u, rec.Code)
+		}
+	}
+
+	// failed packaging is retried on request
+	if err := cfg.DB.SetVideoHlsStatus(context.Background(), database.SetVideoHlsStatusParams{
+		ID:        video,
+		HlsStatus: hls.StatusFailed,
+		HlsError:  "ffmpeg failed",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if rec := doRequest(router, http.MethodGet, prefix+hls.MasterPlaylist, editor); rec.Code != http.StatusConflict {
+		t.Errorf("failed video: status %d, want 409", rec.Code)
+	}
+	if rec := doRequest(router, http.MethodPost, "/v1/projects/videos/"+video+"/hls", editor); rec.Code != http.StatusAccepted {
+		t.Errorf("repackage: status %d, want 202", rec.Code)
+	}
+	waitStatus(video, hls.StatusReady)
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/videos/"+video, editor); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status %d", rec.Code)
+	}
+	if _, err := cfg.Storage.Open(context.Background(), hls.Prefix(video)+hls.MasterPlaylist); !storage.IsNotExist(err) {
+		t.Errorf("HLS files of deleted video: %v", err)
+	}
+}
+
+func TestResumableUpload(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+	other := createTestUser(t, cfg, router, "other", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	do := func(method, path, token string, header map[string]string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	b64 := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+	create := func(length int, metadata string) string {
+		t.Helper()
+		rec := do(http.MethodPost, "/v1/uploads", editor, map[string]string{
+			"Upload-Length":   strconv.Itoa(length),
+			"Upload-Metadata": metadata,
+		}, "")
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("create: status %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Upload-Expires") == "" {
+			t.Error("create: no Upload-Expires")
+		}
+		return rec.Header().Get("Location")
+	}
+	patch := func(upload, token string, offset int, chunk string) *httptest.ResponseRecorder {
+		return do(http.MethodPatch, upload, token, map[string]string{
+			"Content-Type":  "application/offset+octet-stream",
+			"Upload-Offset": strconv.Itoa(offset),
+		}, chunk)
+	}
+
+	if rec := do(http.MethodOptions, "/v1/uploads", "", nil, ""); rec.Code != http.StatusNoContent || rec.Header().Get("Tus-Extension") == "" {
+		t.Errorf("options: status %d, headers %v", rec.Code, rec.Header())
+	}
+
+	t.Run("video", func(t *testing.T) {
+		content := string(testMP4(t, 90*time.Second, 1920, 1080))
+		upload := create(len(content), "filetype "+b64("video/mp4")+",project_id "+b64("2")+",season "+b64("1")+",serie "+b64("2"))
+
+		if rec := patch(upload, editor, 0, content[:10]); rec.Code != http.StatusNoContent || rec.Header().Get("Upload-Offset") != "10" {
+			t.Fatalf("first chunk: status %d, offset %q: %s", rec.Code, rec.Header().Get("Upload-Offset"), rec.Body.String())
+		}
+		rec := do(http.MethodHead, upload, editor, nil, "")
+		if rec.Code != http.StatusOK || rec.Header().Get("Upload-Offset") != "10" || rec.Header().Get("Upload-Length") != strconv.Itoa(len(content)) {
+			t.Errorf("head: status %d, headers %v", rec.Code, rec.Header())
+		}
+		if rec := do(http.MethodHead, upload, other, nil, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("head of other user: status %d, want 404", rec.Code)
+		}
+		if rec := patch(upload, editor, 5, content[5:]); rec.Code != http.StatusConflict {
+			t.Errorf("wrong offset: status %d, want 409", rec.Code)
+		}
+
+		rec = patch(upload, editor, 10, content[10:])
+		if rec.Code != http.StatusNoContent || rec.Header().Get("Upload-Offset") != strconv.Itoa(len(content)) {
+			t.Fatalf("last chunk: status %d, offset %q: %s", rec.Code, rec.Header().Get("Upload-Offset"), rec.Body.String())
+		}
+		video := rec.Header().Get("X-Media-Id")
+		if video == "" {
+			t.Fatal("no X-Media-Id")
+		}
+		if rec := do(http.MethodHead, upload, editor, nil, ""); rec.Header().Get("X-Media-Id") != video {
+			t.Errorf("head of completed upload: X-Media-Id %q, want %q", rec.Header().Get("X-Media-Id"), video)
+		}
+
+		if rec := doRequest(router, http.MethodGet, "/v1/projects/videos/"+video, editor); rec.Body.String() != content {
+			t.Errorf("video = %q, want %q", rec.Body.String(), content)
+		}
+		seasons := []views.Season{}
+		rec = doRequest(router, http.MethodGet, "/v1/projects/2/seasons", editor)
+		if err := json.Unmarshal(rec.Body.Bytes(), &seasons); err != nil {
+			t.Fatal(err)
+		}
+		if len(seasons) != 1 || len(seasons[0].Episodes) != 1 || seasons[0].Episodes[0].Number != 2 || seasons[0].Episodes[0].Video.ID != video {
+			t.Errorf("seasons = %+v", seasons)
+		}
+
+		parts, err := cfg.Storage.List(t.Context(), "uploads/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(parts) != 0 {
+			t.Errorf("parts left: %v", parts)
+		}
+	})
+
+	t.Run("image", func(t *testing.T) {
+		content := string(testPNG(t, 30, 20))
+		upload := create(len(content), "filetype "+b64("image/png")+",project_id "+b64("3")+",href "+b64("https://example.com"))
+		rec := patch(upload, editor, 0, content+" and more")
+		if rec.Code != http.StatusNoContent || rec.Header().Get("Upload-Offset") != strconv.Itoa(len(content)) {
+			t.Fatalf("patch: status %d, offset %q: %s", rec.Code, rec.Header().Get("Upload-Offset"), rec.Body.String())
+		}
+		image := rec.Header().Get("X-Media-Id")
+		if !strings.HasSuffix(image, ".png") {
+			t.Fatalf("X-Media-Id = %q", image)
+		}
+		images, err := cfg.DB.GetImagesOfProject(context.Background(), 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, img := range images {
+			found = found || (img.ID == image && img.Href == "https://example.com" && img.Width == 30 && img.Height == 20)
+		}
+		if !found {
+			t.Errorf("image %s isn't added to project: %+v", image, images)
+		}
+		if rec := doRequest(router, http.MethodGet, "/v1/projects/images/"+image, editor); rec.Body.String() != content {
+			t.Errorf("image of %d bytes, want %d", rec.Body.Len(), len(content))
+		}
+	})
+
+	t.Run("invalid image", func(t *testing.T) {
+		upload := create(5, "filetype "+b64("image/png")+",project_id "+b64("3"))
+		if rec := patch(upload, editor, 0, "image"); rec.Code != http.StatusBadRequest {
+			t.Errorf("patch: status %d, want 400", rec.Code)
+		}
+		if rec := do(http.MethodHead, upload, editor, nil, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("head of invalid upload: status %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		upload := create(100, "filetype "+b64("image/jpeg")+",project_id "+b64("1"))
+		codes := make(chan int, 8)
+		for range cap(codes) {
+			go func() {
+				codes <- patch(upload, editor, 0, "data").Code
+			}()
+		}
+		accepted := 0
+		for range cap(codes) {
+			switch code := <-codes; code {
+			case http.StatusNoContent:
+				accepted++
+			case http.StatusConflict:
+			default:
+				t.Errorf("patch: status %d", code)
+			}
+		}
+		if accepted != 1 {
+			t.Errorf("%d patches at the same offset are accepted, want 1", accepted)
+		}
+		if rec := do(http.MethodHead, upload, editor, nil, ""); rec.Header().Get("Upload-Offset") != "4" {
+			t.Errorf("head: Upload-Offset %q, want 4", rec.Header().Get("Upload-Offset"))
+		}
+		parts, err := cfg.Storage.List(t.Context(), "uploads/"+path.Base(upload)+"/")
+		if err != nil || len(parts) != 1 {
+			t.Errorf("parts = %v, %v, want 1 part", parts, err)
+		}
+		if rec := do(http.MethodDelete, upload, editor, nil, ""); rec.Code != http.StatusNoContent {
+			t.Errorf("delete: status %d", rec.Code)
+		}
+	})
+
+	t.Run("termination", func(t *testing.T) {
+		upload := create(100, "filetype "+b64("image/jpeg")+",project_id "+b64("1"))
+		if rec := do(http.MethodDelete, upload, other, nil, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("delete by other user: status %d, want 404", rec.Code)
+		}
+		if rec := do(http.MethodDelete, upload, editor, nil, ""); rec.Code != http.StatusNoContent {
+			t.Errorf("delete: status %d", rec.Code)
+		}
+		if rec := patch(upload, editor, 0, "data"); rec.Code != http.StatusNotFound {
+			t.Errorf("patch of deleted upload: status %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			header   map[string]string
+			wantCode int
+		}{
+			{"no tus version", map[string]string{"Tus-Resumable": "", "Upload-Length": "1", "Upload-Metadata": "filetype " + b64("image/png") + ",project_id " + b64("1")}, http.StatusPreconditionFailed},
+			{"too large", map[string]string{"Upload-Length": strconv.Itoa(2 << 30), "Upload-Metadata": "filetype " + b64("image/png") + ",project_id " + b64("1")}, http.StatusRequestEntityTooLarge},
+			{"no length", map[string]string{"Upload-Metadata": "filetype " + b64("image/png") + ",project_id " + b64("1")}, http.StatusBadRequest},
+			{"wrong filetype", map[string]string{"Upload-Length": "1", "Upload-Metadata": "filetype " + b64("text/plain") + ",project_id " + b64("1")}, http.StatusBadRequest},
+			{"no serie", map[string]string{"Upload-Length": "1", "Upload-Metadata": "filetype " + b64("video/mp4") + ",project_id " + b64("1") + ",season " + b64("1")}, http.StatusBadRequest},
+			{"no project", map[string]string{"Upload-Length": "1", "Upload-Metadata": "filetype " + b64("image/png") + ",project_id " + b64("999")}, http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			if rec := do(http.MethodPost, "/v1/uploads", editor, tt.header, ""); rec.Code != tt.wantCode {
+				t.Errorf("%s: status %d, want %d: %s", tt.name, rec.Code, tt.wantCode, rec.Body.String())
+			}
+		}
+	})
+}
+
+// testPNG encodes a width x height PNG image with a gradient
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// uploadTestImage posts the image to url as the "image" form file
+func uploadTestImage(t *testing.T, router http.Handler, token, url string, fields map[string]string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="image"; filename="image.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestImageVariants(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	rec := uploadTestImage(t, router, editor, "/v1/projects/images", map[string]string{"project_id": "1"}, testPNG(t, 1000, 1500))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload: status %d: %s", rec.Code, rec.Body.String())
+	}
+	uploaded := views.ResponseIdStr{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &uploaded); err != nil {
+		t.Fatal(err)
+	}
+
+	dImage, err := cfg.DB.GetImage(t.Context(), uploaded.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dImage.Width != 1000 || dImage.Height != 1500 {
+		t.Errorf("image size %dx%d, want 1000x1500", dImage.Width, dImage.Height)
+	}
+
+	tests := []struct {
+		variant    string
+		wantWidth  int
+		wantHeight int
+	}{
+		{variant: "", wantWidth: 1000, wantHeight: 1500},
+		{variant: "thumbnail", wantWidth: 160, wantHeight: 240},
+		{variant: "card", wantWidth: 400, wantHeight: 600},
+		{variant: "backdrop", wantWidth: 480, wantHeight: 720},
+	}
+	for _, tt := range tests {
+		rec := doRequest(router, http.MethodGet, "/v1/projects/images/show/"+uploaded.ID+"?variant="+tt.variant, editor)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("variant %q: status %d", tt.variant, rec.Code)
+		}
+		config, err := png.DecodeConfig(rec.Body)
+		if err != nil {
+			t.Fatalf("variant %q: %v", tt.variant, err)
+		}
+		if config.Width != tt.wantWidth || config.Height != tt.wantHeight {
+			t.Errorf("variant %q: %dx%d, want %dx%d", tt.variant, config.Width, config.Height, tt.wantWidth, tt.wantHeight)
+		}
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/projects/images/show/"+uploaded.ID+"?variant=huge", editor); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown variant: status %d, want 400", rec.Code)
+	}
+
+	// images uploaded without variants are served in full size
+	old := testPNG(t, 10, 10)
+	if err := cfg.Storage.Put(t.Context(), "old.png", bytes.NewReader(old), int64(len(old)), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/projects/images/show/old.png?variant=thumbnail", editor); rec.Code != http.StatusOK {
+		t.Errorf("image without variants: status %d", rec.Code)
+	}
+
+	if rec := uploadTestImage(t, router, editor, "/v1/projects/images", map[string]string{"project_id": "1"}, []byte("not an image")); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid image: status %d, want 400", rec.Code)
+	}
+
+	rec = uploadTestImage(t, router, editor, "/v1/projects/2/cover", nil, testPNG(t, 1920, 1080))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("cover: status %d: %s", rec.Code, rec.Body.String())
+	}
+	cover := views.ResponseIdStr{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &cover); err != nil {
+		t.Fatal(err)
+	}
+	rec = doRequest(router, http.MethodGet, "/v1/projects/images/show/"+cover.ID+"?variant=thumbnail", editor)
+	if config, err := png.DecodeConfig(rec.Body); err != nil || config.Width != 160 || config.Height != 90 {
+		t.Errorf("cover thumbnail: %+v, %v", config, err)
+	}
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/images/"+uploaded.ID, editor); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status %d", rec.Code)
+	}
+	for _, variant := range imaging.Variants {
+		if _, err := cfg.Storage.Open(t.Context(), imaging.Name(uploaded.ID, variant.Name)); !storage.IsNotExist(err) {
+			t.Errorf("variant %s of deleted image: %v", variant.Name, err)
+		}
+	}
+}
+
+// uploadTestSubtitles posts the subtitles to the video as the "subtitles" form file
+func uploadTestSubtitles(t *testing.T, router http.Handler, token, videoID, fileName string, fields map[string]string, content string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	part, err := form.CreateFormFile("subtitles", fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/projects/videos/"+videoID+"/subtitles", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSubtitles(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+	video := uploadTestVideo(t, router, editor, "1", "1", "1")
+
+	upload := func(fileName string, fields map[string]string, content string) int {
+		t.Helper()
+		rec := uploadTestSubtitles(t, router, editor, video, fileName, fields, content)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("upload %s: status %d: %s", fileName, rec.Code, rec.Body.String())
+		}
+		id := views.ResponseId{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &id); err != nil {
+			t.Fatal(err)
+		}
+		return id.ID
+	}
+	tracks := func() []views.Subtitle {
+		t.Helper()
+		rec := doRequest(router, http.MethodGet, "/v1/projects/videos/"+video+"/subtitles", editor)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("subtitles: status %d: %s", rec.Code, rec.Body.String())
+		}
+		vSubtitles := []views.Subtitle{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &vSubtitles); err != nil {
+			t.Fatal(err)
+		}
+		return vSubtitles
+	}
+
+	kk := upload("movie.kk.srt", map[string]string{"language": "kk", "label": "Қазақша", "is_default": "true"},
+		"1\r\n00:00:01,000 --> 00:00:02,500\r\nСәлем\r\n")
+	rec := doRequest(router, http.MethodGet, "/v1/projects/subtitles/"+strconv.Itoa(kk), editor)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/vtt; charset=utf-8" {
+		t.Fatalf("get: status %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if want := "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nСәлем\n"; rec.Body.String() != want {
+		t.Errorf("converted subtitles = %q, want %q", rec.Body.String(), want)
+	}
+
+	// the new default track unsets the previous one
+	ru := upload("movie.vtt", map[string]string{"language": "ru", "is_default": "true"},
+		"WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nПривет\n")
+	if got := tracks(); len(got) != 2 || got[0].ID != int64(ru) || !got[0].IsDefault || got[1].IsDefault {
+		t.Errorf("subtitles after new default = %+v", got)
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+		fields   map[string]string
+		content  string
+		wantCode int
+	}{
+		{name: "Invalid timing", fileName: "en.srt", fields: map[string]string{"language": "en"}, content: "1\n00:00:05,000 --> 00:00:01,000\nHi\n", wantCode: http.StatusBadRequest},
+		{name: "No header", fileName: "en.vtt", fields: map[string]string{"language": "en"}, content: "00:00:01.000 --> 00:00:02.000\nHi\n", wantCode: http.StatusBadRequest},
+		{name: "Invalid language", fileName: "en.srt", fields: map[string]string{"language": "English"}, content: "1\n00:00:01,000 --> 00:00:02,000\nHi\n", wantCode: http.StatusBadRequest},
+		{name: "Duplicate", fileName: "kk.srt", fields: map[string]string{"language": "kk", "label": "Қазақша"}, content: "1\n00:00:01,000 --> 00:00:02,000\nHi\n", wantCode: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if rec := uploadTestSubtitles(t, router, editor, video, tt.fileName, tt.fields, tt.content); rec.Code != tt.wantCode {
+			t.Errorf("%s: status %d, want %d: %s", tt.name, rec.Code, tt.wantCode, rec.Body.String())
+		}
+	}
+	if rec := uploadTestSubtitles(t, router, editor, "none.mp4", "en.srt", map[string]string{"language": "en"}, "1\n00:00:01,000 --> 00:00:02,000\nHi\n"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown video: status %d, want 404", rec.Code)
+	}
+
+	body, _ := json.Marshal(views.UpdateSubtitleRequest{Language: "kk", Label: "Қазақша", IsDefault: true})
+	req := httptest.NewRequest(http.MethodPut, "/v1/projects/subtitles/"+strconv.Itoa(kk), bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+editor)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// the tracks are listed with the videos of the project
+	rec = doRequest(router, http.MethodGet, "/v1/projects/1", editor)
+	project := views.Project{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &project); err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Subtitles) != 2 || project.Subtitles[0].ID != int64(kk) || !project.Subtitles[0].IsDefault || project.Subtitles[0].VideoID != video {
+		t.Errorf("subtitles of project = %+v", project.Subtitles)
+	}
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/subtitles/"+strconv.Itoa(ru), editor); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status %d", rec.Code)
+	}
+	if got := tracks(); len(got) != 1 || got[0].ID != int64(kk) {
+		t.Errorf("subtitles after delete = %+v", got)
+	}
+
+	// the tracks are removed with the video
+	dSubtitle, err := cfg.DB.GetSubtitleById(t.Context(), int64(kk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := doRequest(router, http.MethodDelete, "/v1/projects/videos/"+video, editor); rec.Code != http.StatusOK {
+		t.Fatalf("delete video: status %d", rec.Code)
+	}
+	if _, err := cfg.Storage.Open(t.Context(), dSubtitle.File); !storage.IsNotExist(err) {
+		t.Errorf("subtitles of deleted video: %v", err)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/projects/subtitles/"+strconv.Itoa(kk), editor); rec.Code != http.StatusNotFound {
+		t.Errorf("get deleted: status %d, want 404", rec.Code)
+	}
+}
+
+func TestGC(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+
+	video := uploadTestVideo(t, router, editor, "1", "1", "1")
+	rec := uploadTestImage(t, router, editor, "/v1/projects/images", map[string]string{"project_id": "1"}, testPNG(t, 100, 100))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload image: status %d: %s", rec.Code, rec.Body.String())
+	}
+	image := views.ResponseIdStr{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &image); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DB.AddImage2Movie(t.Context(), database.AddImage2MovieParams{ID: "missing.png", ProjectID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a part of a resumable upload in progress
+	req := httptest.NewRequest(http.MethodPost, "/v1/uploads", nil)
+	req.Header.Set("Authorization", "Bearer "+editor)
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	req.Header.Set("Upload-Length", "100")
+	req.Header.Set("Upload-Metadata", "filetype "+base64.StdEncoding.EncodeToString([]byte("image/png"))+",project_id "+base64.StdEncoding.EncodeToString([]byte("1")))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	req = httptest.NewRequest(http.MethodPatch, rec.Header().Get("Location"), strings.NewReader("part"))
+	req.Header.Set("Authorization", "Bearer "+editor)
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+	req.Header.Set("Upload-Offset", "0")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("patch upload: status %d: %s", rec.Code, rec.Body.String())
+	}
+	parts, err := cfg.Storage.List(t.Context(), "uploads/")
+	if err != nil || len(parts) != 1 {
+		t.Fatalf("parts = %v, %v", parts, err)
+	}
+	part := parts[0].Name
+
+	// orphans of failed uploads and deleted videos, young.mp4 may be an upload in progress
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(cfg.Dir, part), old, old); err != nil {
+		t.Fatal(err)
+	}
+	for name, modTime := range map[string]time.Time{
+		"orphan.png":                      old,
+		"hls/deleted.mp4/master.m3u8":     old,
+		hls.Prefix(video) + "master.m3u8": old,
+		"young.mp4":                       time.Now(),
+	} {
+		if err := cfg.Storage.Put(t.Context(), name, strings.NewReader(name), int64(len(name)), ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filepath.Join(cfg.Dir, name), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exists := func(name string) bool {
+		object, err := cfg.Storage.Open(t.Context(), name)
+		if err != nil {
+			return false
+		}
+		object.Close()
+		return true
+	}
+
+	var out bytes.Buffer
+	if code := runGC(cfg, nil, &out); code != 0 {
+		t.Fatalf("gc: exit code %d: %s", code, out.String())
+	}
+	for _, want := range []string{"orphan orphan.png ", "orphan hls/deleted.mp4/master.m3u8 ", "orphan young.mp4 ", "missing missing.png of images missing.png", "3 orphans, 0 deleted"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report doesn't have %q:\n%s", want, out.String())
+		}
+	}
+	if !exists("orphan.png") {
+		t.Error("orphan is deleted without -delete")
+	}
+
+	out.Reset()
+	if code := runGC(cfg, []string{"-delete"}, &out); code != 0 {
+		t.Fatalf("gc -delete: exit code %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "3 orphans, 2 deleted") {
+		t.Errorf("gc -delete:\n%s", out.String())
+	}
+	for name, want := range map[string]bool{
+		"orphan.png":                        false,
+		"hls/deleted.mp4/master.m3u8":       false,
+		"young.mp4":                         true,
+		video:                               true,
+		hls.Prefix(video) + "master.m3u8":   true,
+		image.ID:                            true,
+		imaging.Name(image.ID, "thumbnail"): true,
+		part:                                true,
+	} {
+		if got := exists(name); got != want {
+			t.Errorf("%s exists = %v, want %v", name, got, want)
+		}
+	}
+
+	out.Reset()
+	if code := runGC(cfg, []string{"-delete", "-grace", "0s"}, &out); code != 0 || exists("young.mp4") {
+		t.Errorf("gc -grace 0s: exit code %d, young.mp4 exists = %v: %s", code, exists("young.mp4"), out.String())
+	}
+	if code := runGC(cfg, []string{"-grace", "soon"}, io.Discard); code != 2 {
+		t.Errorf("invalid grace: exit code %d, want 2", code)
+	}
+}
+
+func TestSignedMediaURLs(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+	editor := createTestUser(t, cfg, router, "editor", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionWrite,
+	})
+	viewer := createTestUser(t, cfg, router, "viewer", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceProjects: controllers.PermissionRead,
+	})
+
+	video := uploadTestVideo(t, router, editor, "1", "1", "1")
+	rec := uploadTestImage(t, router, editor, "/v1/projects/images", map[string]string{"project_id": "1"}, testPNG(t, 1000, 1500))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload image: status %d: %s", rec.Code, rec.Body.String())
+	}
+	image := views.ResponseIdStr{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &image); err != nil {
+		t.Fatal(err)
+	}
+
+	signedURL := func(path string) string {
+		t.Helper()
+		rec := doRequest(router, http.MethodGet, path, viewer)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", path, rec.Code, rec.Body.String())
+		}
+		signed := views.SignedURLResponse{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &signed); err != nil {
+			t.Fatal(err)
+		}
+		if expiresAt, err := time.Parse(time.RFC3339, signed.ExpiresAt); err != nil || time.Until(expiresAt) > 15*time.Minute {
+			t.Errorf("expires_at %q: %v", signed.ExpiresAt, err)
+		}
+		return signed.URL
+	}
+	// the tags send no Authorization header
+	get := func(url string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	videoURL := signedURL("/v1/projects/videos/" + video + "/signed-url")
+	if !strings.HasPrefix(videoURL, "/v1/media/videos/"+video+"?") {
+		t.Errorf("video URL %s", videoURL)
+	}
+	rec = get(videoURL, map[string]string{"Range": "bytes=0-7"})
+	if rec.Code != http.StatusPartialContent || rec.Header().Get("Content-Type") != "video/mp4" || rec.Body.Len() != 8 {
+		t.Errorf("signed video: status %d, Content-Type %q, %d bytes", rec.Code, rec.Header().Get("Content-Type"), rec.Body.Len())
+	}
+
+	imageURL := signedURL("/v1/projects/images/" + image.ID + "/signed-url?variant=thumbnail")
+	rec = get(imageURL, nil)
+	if config, err := png.DecodeConfig(rec.Body); err != nil || config.Width != 160 {
+		t.Errorf("signed thumbnail: status %d, %+v, %v", rec.Code, config, err)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/projects/images/"+image.ID+"/signed-url?variant=huge", viewer); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown variant: status %d, want 400", rec.Code)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/projects/videos/none.mp4/signed-url", viewer); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown video: status %d, want 404", rec.Code)
+	}
+
+	// the signature is bound to the video and the user
+	for _, url := range []string{
+		"/v1/media/videos/" + video,
+		strings.Replace(videoURL, "signature=", "signature=x", 1),
+		strings.Replace(imageURL, "/v1/media/images/"+image.ID, "/v1/media/images/other.png", 1),
+		strings.Replace(videoURL, "/v1/media/videos/", "/v1/media/images/", 1),
+	} {
+		if rec := get(url, nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want 401", url, rec.Code)
+		}
+	}
+
+	// the user must still have the permission
+	user, err := cfg.DB.GetUserByEmail(t.Context(), "viewer@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DB.RemoveRolesOfUser(t.Context(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(videoURL, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("user without roles: status %d, want 403", rec.Code)
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+
+	createTestUser(t, cfg, router, "rotation", nil)
+	tokens := signIn(t, router, "rotation@test.com")
+
+	refresh := func(token string) (*httptest.ResponseRecorder, views.TokensResponse) {
+		t.Helper()
+		rec := doRequest(router, http.MethodPost, "/v1/auth/refresh", token)
+		refreshed := views.TokensResponse{}
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &refreshed); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rec, refreshed
+	}
+
+	rec, first := refresh(tokens.RefreshToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("refresh: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if first.RefreshToken == "" || first.RefreshToken == tokens.RefreshToken {
+		t.Fatalf("refresh returned token %q, want a new one", first.RefreshToken)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users/profile", first.AccessToken); rec.Code != http.StatusOK {
+		t.Fatalf("profile with refreshed access token: status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec, second := refresh(first.RefreshToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("second refresh: status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// the rotated token is reused: the whole family is revoked
+	if rec, _ := refresh(tokens.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("reused token: status %d, want 401", rec.Code)
+	}
+	if rec, _ := refresh(second.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("latest token after reuse: status %d, want 401", rec.Code)
+	}
+
+	if rec, _ := refresh("unknown"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unknown token: status %d, want 401", rec.Code)
+	}
+
+	// a new sign in starts a new family
+	tokens = signIn(t, router, "rotation@test.com")
+	if rec, _ := refresh(tokens.RefreshToken); rec.Code != http.StatusOK {
+		t.Errorf("refresh after sign in: status %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSessions(t *testing.T) {
+	cfg := newTestConfig(t)
+	// the requests of httptest come from 192.0.2.1
+	cfg.TrustedProxies, _ = controllers.ParseTrustedProxies("192.0.2.1")
+	router := newRouter(cfg)
+
+	token := createTestUser(t, cfg, router, "sessions", nil)
+
+	signInFrom := func(remoteAddr, userAgent string) views.TokensResponse {
+		t.Helper()
+		body, _ := json.Marshal(views.SignInRequest{Email: "sessions@test.com", Password: testPassword})
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/sign-in", bytes.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("X-Forwarded-For", "203.0.113.5")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("sign in: status %d: %s", rec.Code, rec.Body.String())
+		}
+		tokens := views.TokensResponse{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &tokens); err != nil {
+			t.Fatal(err)
+		}
+		return tokens
+	}
+	// forwarded by the trusted proxy
+	phone := signInFrom("192.0.2.1:1234", "Phone")
+	// the header of an untrusted peer is ignored
+	signInFrom("198.51.100.3:1234", "Tablet")
+	laptop := signIn(t, router, "sessions@test.com")
+
+	getSessions := func() []views.Session {
+		t.Helper()
+		rec := doRequest(router, http.MethodGet, "/v1/users/profile/sessions", token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("sessions: status %d: %s", rec.Code, rec.Body.String())
+		}
+		sessions := []views.Session{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+			t.Fatal(err)
+		}
+		return sessions
+	}
+	profile := func(token string) int {
+		return doRequest(router, http.MethodGet, "/v1/users/profile", token).Code
+	}
+
+	sessions := getSessions()
+	if len(sessions) != 4 {
+		t.Fatalf("got %d sessions, want 4", len(sessions))
+	}
+	phoneID := ""
+	current := 0
+	for _, session := range sessions {
+		if session.Current {
+			current++
+		}
+		switch session.UserAgent {
+		case "Phone":
+			phoneID = session.Id
+			if session.Ip != "203.0.113.5" || session.CreatedAt == "" || session.ExpiresAt == "" {
+				t.Errorf("phone session %+v", session)
+			}
+		case "Tablet":
+			if session.Ip != "198.51.100.3" {
+				t.Errorf("tablet session %+v, want the address of the connection", session)
+			}
+		}
+	}
+	if current != 1 || phoneID == "" {
+		t.Fatalf("sessions %+v: want one current and the phone", sessions)
+	}
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/users/profile/sessions/"+phoneID, token); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke phone: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if code := profile(phone.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("phone access token after revoke: status %d, want 401", code)
+	}
+	if rec := doRequest(router, http.MethodPost, "/v1/auth/refresh", phone.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("phone refresh token after revoke: status %d, want 401", rec.Code)
+	}
+	if rec := doRequest(router, http.MethodDelete, "/v1/users/profile/sessions/"+phoneID, token); rec.Code != http.StatusNotFound {
+		t.Errorf("revoke phone again: status %d, want 404", rec.Code)
+	}
+
+	// the sessions of the others can't be revoked
+	otherToken := createTestUser(t, cfg, router, "sessions-other", nil)
+	if rec := doRequest(router, http.MethodDelete, "/v1/users/profile/sessions/"+phoneID, otherToken); rec.Code != http.StatusNotFound {
+		t.Errorf("revoke session of other user: status %d, want 404", rec.Code)
+	}
+
+	if rec := doRequest(router, http.MethodDelete, "/v1/users/profile/sessions", token); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke others: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if code := profile(laptop.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("laptop access token after revoke others: status %d, want 401", code)
+	}
+	if code := profile(token); code != http.StatusOK {
+		t.Errorf("current access token after revoke others: status %d, want 200", code)
+	}
+	if sessions := getSessions(); len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("sessions after revoke others %+v, want the current", sessions)
+	}
+
+	// the admin signs out every session of the user
+	user, err := cfg.DB.GetUserByEmail(t.Context(), "sessions@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminToken := createTestUser(t, cfg, router, "sessions-admin", map[controllers.Resource]controllers.PermissionLevel{
+		controllers.ResourceUsers: controllers.PermissionWrite,
+	})
+	if rec := doRequest(router, http.MethodDelete, fmt.Sprintf("/v1/users/%d/sessions", user.ID), adminToken); rec.Code != http.StatusNoContent {
+		t.Fatalf("admin revoke: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if code := profile(token); code != http.StatusUnauthorized {
+		t.Errorf("access token after admin revoke: status %d, want 401", code)
+	}
+	if code := profile(otherToken); code != http.StatusOK {
+		t.Errorf("other user after admin revoke: status %d, want 200", code)
+	}
+	if rec := doRequest(router, http.MethodDelete, "/v1/users/999/sessions", adminToken); rec.Code != http.StatusNotFound {
+		t.Errorf("admin revoke of unknown user: status %d, want 404", rec.Code)
+	}
+}
+
+// lastMailBody returns the decoded body of the latest message of the outbox
+func lastMailBody(t *testing.T, cfg *configuration.ApiConfiguration) (to, body string) {
+	t.Helper()
+	files, err := cfg.Mailer.(*mail.Outbox).Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no messages in outbox")
+	}
+	data, err := os.ReadFile(files[len(files)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := netmail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg.Header.Get("To"), string(decoded)
+}
+
+func TestPasswords(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+
+	postJSON := func(path, token string, payload any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	signInWith := func(password string) int {
+		return postJSON("/v1/auth/sign-in", "", views.SignInRequest{Email: "passwords@test.com", Password: password}).Code
+	}
+
+	token := createTestUser(t, cfg, router, "passwords", nil)
+	other := signIn(t, router, "passwords@test.com")
+
+	change := func(token string, cpr views.ChangePasswordRequest) int {
+		body, _ := json.Marshal(cpr)
+		req := httptest.NewRequest(http.MethodPut, "/v1/users/profile/password", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := change(token, views.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "changed"}); code != http.StatusUnauthorized {
+		t.Errorf("change with wrong password: status %d, want 401", code)
+	}
+	if code := change(token, views.ChangePasswordRequest{CurrentPassword: testPassword}); code != http.StatusBadRequest {
+		t.Errorf("change to empty password: status %d, want 400", code)
+	}
+	if code := change(token, views.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "changed"}); code != http.StatusNoContent {
+		t.Fatalf("change: status %d, want 204", code)
+	}
+	if code := signInWith(testPassword); code != http.StatusUnauthorized {
+		t.Errorf("sign in with old password: status %d, want 401", code)
+	}
+	if code := signInWith("changed"); code != http.StatusOK {
+		t.Errorf("sign in with new password: status %d, want 200", code)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users/profile", token); rec.Code != http.StatusOK {
+		t.Errorf("current session after change: status %d, want 200", rec.Code)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users/profile", other.AccessToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("other session after change: status %d, want 401", rec.Code)
+	}
+
+	// unknown emails get the same answer and no email
+	if rec := postJSON("/v1/auth/forgot-password", "", views.ForgotPasswordRequest{Email: "nobody@test.com"}); rec.Code != http.StatusAccepted {
+		t.Errorf("forgot of unknown email: status %d, want 202", rec.Code)
+	}
+
+	sent := 0
+	resetToken := func() string {
+		t.Helper()
+		if rec := postJSON("/v1/auth/forgot-password", "", views.ForgotPasswordRequest{Email: "passwords@test.com"}); rec.Code != http.StatusAccepted {
+			t.Fatalf("forgot: status %d: %s", rec.Code, rec.Body.String())
+		}
+		// the email is sent after the answer
+		sent++
+		waitMessages(t, cfg, sent)
+		to, token := mailToken(t, cfg, "https://ozinshe.test/reset-password")
+		if to != "<passwords@test.com>" {
+			t.Errorf("email to %q", to)
+		}
+		return token
+	}
+
+	// a new token replaces the previous one
+	stale := resetToken()
+	if files, _ := cfg.Mailer.(*mail.Outbox).Messages(); len(files) != 1 {
+		t.Errorf("got %d emails, want none for unknown email", len(files)-1)
+	}
+	fresh := resetToken()
+	if rec := postJSON("/v1/auth/reset-password", "", views.ResetPasswordRequest{Token: stale, NewPassword: "stale"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("reset with replaced token: status %d, want 400", rec.Code)
+	}
+
+	if rec := postJSON("/v1/auth/reset-password", "", views.ResetPasswordRequest{Token: fresh, NewPassword: "reset"}); rec.Code != http.StatusNoContent {
+		t.Fatalf("reset: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if code := signInWith("reset"); code != http.StatusOK {
+		t.Errorf("sign in with reset password: status %d, want 200", code)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users/profile", token); rec.Code != http.StatusUnauthorized {
+		t.Errorf("session after reset: status %d, want 401", rec.Code)
+	}
+	if rec := postJSON("/v1/auth/reset-password", "", views.ResetPasswordRequest{Token: fresh, NewPassword: "again"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("reset with used token: status %d, want 400", rec.Code)
+	}
+
+	// the tokens expire in an hour
+	expired := resetToken()
+	_, err := cfg.Conn.Exec("UPDATE password_reset_tokens SET expires_at = ?", time.Now().UTC().Add(-time.Minute).Format(time.RFC3339))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := postJSON("/v1/auth/reset-password", "", views.ResetPasswordRequest{Token: expired, NewPassword: "expired"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("reset with expired token: status %d, want 400", rec.Code)
+	}
+
+	// 5 requests an hour for an email, known or not, then the next one waits
+	forgotFrom := func(ip, email string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(views.ForgotPasswordRequest{Email: email})
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/forgot-password", bytes.NewReader(body))
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	for i := 1; i <= 6; i++ {
+		if rec := forgotFrom(fmt.Sprintf("198.51.100.%d", i), "Flood@test.com"); rec.Code != http.StatusAccepted {
+			t.Fatalf("request %d: status %d, want 202", i, rec.Code)
+		}
+	}
+	rec := forgotFrom("198.51.100.7", "flood@test.com ")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("request after 6: status %d, Retry-After %q, want 429 after 60", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	// and so for an address
+	for i := 1; i <= 6; i++ {
+		forgotFrom("203.0.113.50", fmt.Sprintf("flood%d@test.com", i))
+	}
+	if rec := forgotFrom("203.0.113.50", "passwords@test.com"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("request from flooding address: status %d, want 429", rec.Code)
+	}
+}
+
+// waitMessages waits for n messages in the outbox
+func waitMessages(t *testing.T, cfg *configuration.ApiConfiguration, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		files, err := cfg.Mailer.(*mail.Outbox).Messages()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d messages in outbox, want %d", len(files), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// mailToken returns the token of the link of the latest email
+func mailToken(t *testing.T, cfg *configuration.ApiConfiguration, link string) (to, token string) {
+	t.Helper()
+	to, body := lastMailBody(t, cfg)
+	i := strings.Index(body, link+"?token=")
+	if i < 0 {
+		t.Fatalf("email %q has no link %s", body, link)
+	}
+	return to, strings.Fields(body[i+len(link+"?token="):])[0]
+}
+
+func TestEmailVerification(t *testing.T) {
+	const verifyURL = "https://ozinshe.test/verify-email"
+
+	postJSON := func(router http.Handler, path string, payload any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	register := func(t *testing.T, cfg *configuration.ApiConfiguration, router http.Handler, name string) (email, token string) {
+		t.Helper()
+		email = name + "@test.com"
+		rec := postJSON(router, "/v1/users", views.CreateUserRequest{Name: name, Email: email, Password: testPassword})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("register: status %d: %s", rec.Code, rec.Body.String())
+		}
+		to, token := mailToken(t, cfg, verifyURL)
+		if to != "<"+email+">" {
+			t.Errorf("verification email to %q, want %s", to, email)
+		}
+		return email, token
+	}
+	signInCode := func(router http.Handler, email string) int {
+		return postJSON(router, "/v1/auth/sign-in", views.SignInRequest{Email: email, Password: testPassword}).Code
+	}
+	profile := func(t *testing.T, router http.Handler, token string) views.User {
+		t.Helper()
+		rec := doRequest(router, http.MethodGet, "/v1/users/profile", token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("profile: status %d: %s", rec.Code, rec.Body.String())
+		}
+		user := views.User{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &user); err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		cfg := newTestConfig(t)
+		cfg.EmailVerification = "limit"
+		cfg.EmailVerificationURL = verifyURL
+		router := newRouter(cfg)
+
+		email, token := register(t, cfg, router, "limited")
+		access := signIn(t, router, email).AccessToken
+		if user := profile(t, router, access); user.EmailVerified || len(user.Roles) != 0 {
+			t.Errorf("unverified profile %+v, want no roles", user)
+		}
+		if rec := doRequest(router, http.MethodGet, "/v1/genres", access); rec.Code != http.StatusForbidden {
+			t.Errorf("genres before verification: status %d, want 403", rec.Code)
+		}
+
+		if rec := postJSON(router, "/v1/auth/verify-email", views.VerifyEmailRequest{Token: token}); rec.Code != http.StatusNoContent {
+			t.Fatalf("verify: status %d: %s", rec.Code, rec.Body.String())
+		}
+		if user := profile(t, router, access); !user.EmailVerified || len(user.Roles) != 1 {
+			t.Errorf("verified profile %+v, want the role", user)
+		}
+		if rec := doRequest(router, http.MethodGet, "/v1/genres", access); rec.Code != http.StatusOK {
+			t.Errorf("genres after verification: status %d, want 200", rec.Code)
+		}
+		if rec := postJSON(router, "/v1/auth/verify-email", views.VerifyEmailRequest{Token: token}); rec.Code != http.StatusBadRequest {
+			t.Errorf("verify with used token: status %d, want 400", rec.Code)
+		}
+
+		// a changed email isn't verified
+		body, _ := json.Marshal(views.UpdateProfileRequest{Name: "limited", Email: "changed@test.com"})
+		req := httptest.NewRequest(http.MethodPut, "/v1/users/profile", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+access)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("update profile: status %d: %s", rec.Code, rec.Body.String())
+		}
+		to, token := mailToken(t, cfg, verifyURL)
+		if to != "<changed@test.com>" {
+			t.Errorf("verification email to %q, want the changed email", to)
+		}
+		user, err := cfg.DB.GetUserByEmail(t.Context(), "changed@test.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.EmailVerifiedAt.Valid {
+			t.Error("changed email is verified")
+		}
+		if rec := postJSON(router, "/v1/auth/verify-email", views.VerifyEmailRequest{Token: token}); rec.Code != http.StatusNoContent {
+			t.Errorf("verify changed email: status %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		cfg := newTestConfig(t)
+		cfg.EmailVerification = "block"
+		cfg.EmailVerificationURL = verifyURL
+		router := newRouter(cfg)
+
+		email, stale := register(t, cfg, router, "blocked")
+		if code := signInCode(router, email); code != http.StatusForbidden {
+			t.Errorf("sign in before verification: status %d, want 403", code)
+		}
+
+		if rec := postJSON(router, "/v1/auth/resend-verification", views.ResendVerificationRequest{Email: email}); rec.Code != http.StatusAccepted {
+			t.Fatalf("resend: status %d: %s", rec.Code, rec.Body.String())
+		}
+		// the email is sent after the answer
+		waitMessages(t, cfg, 2)
+		_, token := mailToken(t, cfg, verifyURL)
+		if rec := postJSON(router, "/v1/auth/verify-email", views.VerifyEmailRequest{Token: stale}); rec.Code != http.StatusBadRequest {
+			t.Errorf("verify with replaced token: status %d, want 400", rec.Code)
+		}
+		if rec := postJSON(router, "/v1/auth/verify-email", views.VerifyEmailRequest{Token: token}); rec.Code != http.StatusNoContent {
+			t.Fatalf("verify: status %d: %s", rec.Code, rec.Body.String())
+		}
+		if code := signInCode(router, email); code != http.StatusOK {
+			t.Errorf("sign in after verification: status %d, want 200", code)
+		}
+
+		// unknown, verified and unverified emails get the same answer,
+		// only the unverified one gets an email
+		unverified, _ := register(t, cfg, router, "unverified")
+		files, _ := cfg.Mailer.(*mail.Outbox).Messages()
+		want := postJSON(router, "/v1/auth/resend-verification", views.ResendVerificationRequest{Email: unverified})
+		for _, email := range []string{"nobody@test.com", email} {
+			rec := postJSON(router, "/v1/auth/resend-verification", views.ResendVerificationRequest{Email: email})
+			if rec.Code != want.Code || rec.Body.String() != want.Body.String() {
+				t.Errorf("resend to %s: %d %q, want %d %q of unverified email", email, rec.Code, rec.Body.String(), want.Code, want.Body.String())
+			}
+		}
+		if want.Code != http.StatusAccepted {
+			t.Errorf("resend to unverified email: status %d, want 202", want.Code)
+		}
+		waitMessages(t, cfg, len(files)+1)
+		if to, _ := mailToken(t, cfg, verifyURL); to != "<"+unverified+">" {
+			t.Errorf("verification email to %q, want %s", to, unverified)
+		}
+		if after, _ := cfg.Mailer.(*mail.Outbox).Messages(); len(after) != len(files)+1 {
+			t.Errorf("got %d new emails, want 1", len(after)-len(files))
+		}
+
+		// 5 requests an hour for an email, known or not, then the next one waits
+		resendFrom := func(ip, email string) *httptest.ResponseRecorder {
+			body, _ := json.Marshal(views.ResendVerificationRequest{Email: email})
+			req := httptest.NewRequest(http.MethodPost, "/v1/auth/resend-verification", bytes.NewReader(body))
+			req.RemoteAddr = ip + ":1234"
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+		for i := 1; i <= 6; i++ {
+			if rec := resendFrom(fmt.Sprintf("198.51.100.%d", i), "Flood@test.com"); rec.Code != http.StatusAccepted {
+				t.Fatalf("resend %d: status %d, want 202", i, rec.Code)
+			}
+		}
+		rec := resendFrom("198.51.100.7", "flood@test.com ")
+		if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+			t.Errorf("resend after 6: status %d, Retry-After %q, want 429 after 60", rec.Code, rec.Header().Get("Retry-After"))
+		}
+		// and so for an address
+		for i := 1; i <= 6; i++ {
+			resendFrom("203.0.113.50", fmt.Sprintf("flood%d@test.com", i))
+		}
+		if rec := resendFrom("203.0.113.50", unverified); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("resend from flooding address: status %d, want 429", rec.Code)
+		}
+	})
+}
+
+func TestSignInThrottling(t *testing.T) {
+	for name, store := range map[string]func(cfg *configuration.ApiConfiguration) throttle.Store{
+		"memory":   func(cfg *configuration.ApiConfiguration) throttle.Store { return throttle.NewMemory() },
+		"database": func(cfg *configuration.ApiConfiguration) throttle.Store { return throttle.NewDatabase(cfg.DB) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			cfg.LoginAttempts = store(cfg)
+			router := newRouter(cfg)
+
+			signInFrom := func(ip, email, password string) *httptest.ResponseRecorder {
+				body, _ := json.Marshal(views.SignInRequest{Email: email, Password: password})
+				req := httptest.NewRequest(http.MethodPost, "/v1/auth/sign-in", bytes.NewReader(body))
+				req.RemoteAddr = ip + ":1234"
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				return rec
+			}
+
+			createTestUser(t, cfg, router, "throttled", nil)
+			adminToken := createTestUser(t, cfg, router, "throttle-admin", map[controllers.Resource]controllers.PermissionLevel{
+				controllers.ResourceUsers: controllers.PermissionWrite,
+			})
+
+			// the registered emails aren't revealed
+			unknown := signInFrom("192.0.2.10", "nobody@test.com", testPassword)
+			wrong := signInFrom("192.0.2.10", "throttled@test.com", "wrong")
+			if unknown.Code != http.StatusUnauthorized || wrong.Code != http.StatusUnauthorized || unknown.Body.String() != wrong.Body.String() {
+				t.Errorf("unknown email: %d %s, wrong password: %d %s, want the same 401", unknown.Code, unknown.Body.String(), wrong.Code, wrong.Body.String())
+			}
+
+			// 5 free failures, the 6th delays the next attempt
+			for i := 2; i <= 6; i++ {
+				if rec := signInFrom("192.0.2.10", "Throttled@test.com ", "wrong"); rec.Code != http.StatusUnauthorized {
+					t.Fatalf("failure %d: status %d, want 401", i, rec.Code)
+				}
+			}
+			rec := signInFrom("192.0.2.11", "throttled@test.com", testPassword)
+			if rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("sign in after 6 failures: status %d, want 429", rec.Code)
+			}
+			if got := rec.Header().Get("Retry-After"); got != "1" {
+				t.Errorf("Retry-After %q, want 1", got)
+			}
+
+			user, err := cfg.DB.GetUserByEmail(t.Context(), "throttled@test.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec := doRequest(router, http.MethodPost, fmt.Sprintf("/v1/users/%d/unlock", user.ID), adminToken); rec.Code != http.StatusNoContent {
+				t.Fatalf("unlock: status %d: %s", rec.Code, rec.Body.String())
+			}
+			if rec := signInFrom("192.0.2.11", "throttled@test.com", testPassword); rec.Code != http.StatusOK {
+				t.Errorf("sign in after unlock: status %d, want 200", rec.Code)
+			}
+			// the account of the attempts is the account signed in to
+			if rec := signInFrom("192.0.2.11", " THROTTLED@test.com", testPassword); rec.Code != http.StatusOK {
+				t.Errorf("sign in with other spelling: status %d, want 200", rec.Code)
+			}
+
+			// the concurrent guesses can't pass the limit, only one of them is checked
+			createTestUser(t, cfg, router, "concurrent", nil)
+			for i := 1; i <= 5; i++ {
+				if rec := signInFrom("192.0.2.13", "concurrent@test.com", "wrong"); rec.Code != http.StatusUnauthorized {
+					t.Fatalf("failure %d: status %d, want 401", i, rec.Code)
+				}
+			}
+			codes := make([]int, 10)
+			var wg sync.WaitGroup
+			for i := range codes {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					codes[i] = signInFrom("192.0.2.13", "concurrent@test.com", "wrong").Code
+				}()
+			}
+			wg.Wait()
+			slices.Sort(codes)
+			if want := append([]int{http.StatusUnauthorized}, slices.Repeat([]int{http.StatusTooManyRequests}, 9)...); !slices.Equal(codes, want) {
+				t.Errorf("concurrent failures: statuses %v, want %v", codes, want)
+			}
+
+			// an address guessing many accounts is delayed too
+			for i := 1; i <= 21; i++ {
+				if rec := signInFrom("198.51.100.7", fmt.Sprintf("guess%d@test.com", i), "wrong"); rec.Code != http.StatusUnauthorized {
+					t.Fatalf("guess %d: status %d, want 401", i, rec.Code)
+				}
+			}
+			if rec := signInFrom("198.51.100.7", "throttled@test.com", testPassword); rec.Code != http.StatusTooManyRequests {
+				t.Errorf("sign in from guessing address: status %d, want 429", rec.Code)
+			}
+			if rec := signInFrom("192.0.2.12", "throttled@test.com", testPassword); rec.Code != http.StatusOK {
+				t.Errorf("sign in from other address: status %d, want 200", rec.Code)
+			}
+		})
+	}
+}
+
+func TestSignInThrottlingBehindProxy(t *testing.T) {
+	for name, tt := range map[string]struct {
+		proxies string
+		// wantSpread is whether the forwarded addresses are throttled apart
+		wantSpread bool
+	}{
+		"spoofed header": {proxies: ""},
+		"trusted proxy":  {proxies: "10.0.0.0/8", wantSpread: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			proxies, err := controllers.ParseTrustedProxies(tt.proxies)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.TrustedProxies = proxies
+			router := newRouter(cfg)
+			createTestUser(t, cfg, router, "proxied", nil)
+
+			signInVia := func(forwarded, email, password string) int {
+				body, _ := json.Marshal(views.SignInRequest{Email: email, Password: password})
+				req := httptest.NewRequest(http.MethodPost, "/v1/auth/sign-in", bytes.NewReader(body))
+				req.RemoteAddr = "10.0.0.1:1234"
+				req.Header.Set("X-Forwarded-For", forwarded)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				return rec.Code
+			}
+
+			for i := 1; i <= 21; i++ {
+				if code := signInVia("198.51.100.7", fmt.Sprintf("guess%d@test.com", i), "wrong"); code != http.StatusUnauthorized {
+					t.Fatalf("guess %d: status %d, want 401", i, code)
+				}
+			}
+			if code := signInVia("198.51.100.7", "proxied@test.com", testPassword); code != http.StatusTooManyRequests {
+				t.Errorf("sign in from guessing address: status %d, want 429", code)
+			}
+			want := http.StatusTooManyRequests
+			if tt.wantSpread {
+				want = http.StatusOK
+			}
+			if code := signInVia("192.0.2.12", "proxied@test.com", testPassword); code != want {
+				t.Errorf("sign in forwarded for other address: status %d, want %d", code, want)
+			}
+		})
+	}
+}
+
+func TestTwoFactor(t *testing.T) {
+	cfg := newTestConfig(t)
+	router := newRouter(cfg)
+
+	postJSON := func(method, path, token string, v any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(v)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	code := func(secret string, at time.Time) string {
+		code, err := totp.Code(secret, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+	challenge := func() string {
+		rec := postJSON(http.MethodPost, "/v1/auth/sign-in", "", views.SignInRequest{Email: "guarded@test.com", Password: testPassword})
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("sign in with 2FA: status %d, want 202: %s", rec.Code, rec.Body.String())
+		}
+		resp := views.TwoFactorChallengeResponse{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.ChallengeToken
+	}
+
+	token := createTestUser(t, cfg, router, "guarded", nil)
+	user, err := cfg.DB.GetUserByEmail(t.Context(), "guarded@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the role requiring 2FA is ignored until 2FA is on
+	roleID, err := cfg.DB.CreateRole(t.Context(), database.CreateRoleParams{
+		Title:            "guarded-admin",
+		Users:            int64(controllers.PermissionRead),
+		RequireTwoFactor: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.DB.AddRole2User(t.Context(), database.AddRole2UserParams{UserID: user.ID, RoleID: roleID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users", token); rec.Code != http.StatusForbidden {
+		t.Errorf("users without 2FA: status %d, want 403", rec.Code)
+	}
+
+	if rec := postJSON(http.MethodPost, "/v1/users/profile/2fa/confirm", token, views.TwoFactorCodeRequest{Code: "123456"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("confirm without enrollment: status %d, want 400", rec.Code)
+	}
+
+	rec := postJSON(http.MethodPost, "/v1/users/profile/2fa", token, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("enroll: status %d: %s", rec.Code, rec.Body.String())
+	}
+	enrollment := views.TwoFactorEnrollmentResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &enrollment); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enrollment.URI, "otpauth://totp/") || !strings.Contains(enrollment.URI, "secret="+enrollment.Secret) {
+		t.Errorf("URI %q of secret %q", enrollment.URI, enrollment.Secret)
+	}
+
+	if rec := postJSON(http.MethodPost, "/v1/users/profile/2fa/confirm", token, views.TwoFactorCodeRequest{Code: "000000x"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("confirm with invalid code: status %d, want 400", rec.Code)
+	}
+	now := time.Now()
+	rec = postJSON(http.MethodPost, "/v1/users/profile/2fa/confirm", token, views.TwoFactorCodeRequest{Code: code(enrollment.Secret, now)})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm: status %d: %s", rec.Code, rec.Body.String())
+	}
+	recovery := views.RecoveryCodesResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &recovery); err != nil {
+		t.Fatal(err)
+	}
+	if len(recovery.RecoveryCodes) != 10 {
+		t.Fatalf("%d recovery codes, want 10", len(recovery.RecoveryCodes))
+	}
+	if rec := postJSON(http.MethodPost, "/v1/users/profile/2fa", token, nil); rec.Code != http.StatusConflict {
+		t.Errorf("enroll with 2FA on: status %d, want 409", rec.Code)
+	}
+
+	rec = doRequest(router, http.MethodGet, "/v1/users/profile", token)
+	profile := views.User{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &profile); err != nil {
+		t.Fatal(err)
+	}
+	if !profile.TwoFactor {
+		t.Error("profile with 2FA on: two_factor is false")
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users", token); rec.Code != http.StatusOK {
+		t.Errorf("users with 2FA: status %d, want 200", rec.Code)
+	}
+
+	// the password alone gives no tokens
+	if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: token, Code: code(enrollment.Secret, now)}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("access token as challenge: status %d, want 401", rec.Code)
+	}
+	challengeToken := challenge()
+	if rec := doRequest(router, http.MethodGet, "/v1/users/profile", challengeToken); rec.Code == http.StatusOK {
+		t.Error("challenge as access token: status 200")
+	}
+
+	// the code of the confirmation is used, the next one is accepted once
+	if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challengeToken, Code: code(enrollment.Secret, now)}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("replayed confirmation code: status %d, want 401", rec.Code)
+	}
+	next := code(enrollment.Secret, now.Add(totp.Period))
+	rec = postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challengeToken, Code: next})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("sign in with code: status %d: %s", rec.Code, rec.Body.String())
+	}
+	tokens := views.TokensResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &tokens); err != nil {
+		t.Fatal(err)
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users/profile", tokens.AccessToken); rec.Code != http.StatusOK {
+		t.Errorf("profile after 2FA sign in: status %d, want 200", rec.Code)
+	}
+	if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challenge(), Code: next}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("replayed code: status %d, want 401", rec.Code)
+	}
+
+	// a recovery code is accepted once, in any case and without the dash
+	recoveryCode := strings.ToUpper(strings.Replace(recovery.RecoveryCodes[0], "-", "", 1))
+	if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challenge(), Code: recoveryCode}); rec.Code != http.StatusOK {
+		t.Errorf("sign in with recovery code: status %d, want 200", rec.Code)
+	}
+	if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challenge(), Code: recovery.RecoveryCodes[0]}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("reused recovery code: status %d, want 401", rec.Code)
+	}
+
+	// signing in with the password again doesn't forget the failed codes
+	for i := 2; i <= 6; i++ {
+		if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challenge(), Code: "wrong"}); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("failed code %d: status %d, want 401", i, rec.Code)
+		}
+	}
+	rec = postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challengeToken, Code: code(enrollment.Secret, now.Add(2*totp.Period))})
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("code after 6 failures: status %d, want 429", rec.Code)
+	}
+	// the delay passes before the sign in below
+	retryAfter, _ := strconv.Atoi(rec.Header().Get("Retry-After"))
+	time.Sleep(time.Duration(retryAfter) * time.Second)
+
+	// a passed second factor forgets the failed codes
+	if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challenge(), Code: recovery.RecoveryCodes[1]}); rec.Code != http.StatusOK {
+		t.Fatalf("sign in with recovery code: status %d, want 200", rec.Code)
+	}
+
+	// turning 2FA off needs the password and a code, the failures are throttled like the sign ins
+	disable := views.DisableTwoFactorRequest{CurrentPassword: testPassword, Code: recovery.RecoveryCodes[2]}
+	if rec := postJSON(http.MethodDelete, "/v1/users/profile/2fa", token, views.DisableTwoFactorRequest{CurrentPassword: "wrong", Code: disable.Code}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("disable with incorrect password: status %d, want 401", rec.Code)
+	}
+	for i := 2; i <= 6; i++ {
+		if rec := postJSON(http.MethodDelete, "/v1/users/profile/2fa", token, views.DisableTwoFactorRequest{CurrentPassword: testPassword, Code: "wrong"}); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("disable with invalid code %d: status %d, want 401", i, rec.Code)
+		}
+	}
+	rec = postJSON(http.MethodDelete, "/v1/users/profile/2fa", token, disable)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("disable after 6 failures: status %d, want 429", rec.Code)
+	}
+	retryAfter, _ = strconv.Atoi(rec.Header().Get("Retry-After"))
+	time.Sleep(time.Duration(retryAfter) * time.Second)
+
+	if rec := postJSON(http.MethodDelete, "/v1/users/profile/2fa", token, disable); rec.Code != http.StatusNoContent {
+		t.Fatalf("disable: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := doRequest(router, http.MethodGet, "/v1/users", token); rec.Code != http.StatusForbidden {
+		t.Errorf("users after disabling 2FA: status %d, want 403", rec.Code)
+	}
+	signIn(t, router, "guarded@test.com")
+	if rec := postJSON(http.MethodPost, "/v1/auth/sign-in/2fa", "", views.TwoFactorSignInRequest{ChallengeToken: challengeToken, Code: recovery.RecoveryCodes[3]}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("sign in with 2FA off: status %d, want 401", rec.Code)
+	}
+}
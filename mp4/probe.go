@@ -0,0 +1,238 @@
+// Package mp4 checks that uploaded files are MP4 videos
+// and reads their duration and resolution from the boxes.
+package mp4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrInvalid is returned for the files which aren't complete MP4 videos
+var ErrInvalid = errors.New("invalid MP4 video")
+
+// maxMoovSize protects from reading a huge box into memory, 64 MB
+const maxMoovSize = 64 << 20
+
+// Info of the video
+type Info struct {
+	Duration time.Duration
+	// Width and Height of the first video track
+	Width  int
+	Height int
+}
+
+type box struct {
+	typ string
+	// offset and size of the content after the header
+	offset int64
+	size   int64
+}
+
+// Probe reads the top level boxes of the file,
+// it must start with ftyp and have moov with a video track.
+// The boxes must fit in the file, so truncated files are rejected.
+func Probe(r io.ReaderAt, size int64) (Info, error) {
+	boxes, err := readBoxes(r, 0, size)
+	if err != nil {
+		return Info{}, err
+	}
+	if len(boxes) == 0 || boxes[0].typ != "ftyp" {
+		return Info{}, fmt.Errorf("%w: no ftyp box", ErrInvalid)
+	}
+
+	var moov *box
+	data := false
+	for i := range boxes {
+		switch boxes[i].typ {
+		case "moov":
+			moov = &boxes[i]
+		case "mdat", "moof":
+			data = true
+		}
+	}
+	if moov == nil {
+		return Info{}, fmt.Errorf("%w: no moov box", ErrInvalid)
+	}
+	if !data {
+		return Info{}, fmt.Errorf("%w: no media data", ErrInvalid)
+	}
+	if moov.size > maxMoovSize {
+		return Info{}, fmt.Errorf("%w: moov box is too large", ErrInvalid)
+	}
+
+	content := make([]byte, moov.size)
+	_, err = r.ReadAt(content, moov.offset)
+	if err != nil {
+		return Info{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return parseMoov(content)
+}
+
+// readBoxes reads the headers of the boxes in [offset, end)
+func readBoxes(r io.ReaderAt, offset, end int64) ([]box, error) {
+	boxes := []box{}
+	header := make([]byte, 16)
+	for offset < end {
+		if end-offset < 8 {
+			return nil, fmt.Errorf("%w: truncated box header", ErrInvalid)
+		}
+		_, err := r.ReadAt(header[:8], offset)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		b := box{typ: string(header[4:8])}
+		boxSize := int64(binary.BigEndian.Uint32(header))
+		headerSize := int64(8)
+		switch boxSize {
+		case 0:
+			// the box extends to the end of the file
+			boxSize = end - offset
+		case 1:
+			if end-offset < 16 {
+				return nil, fmt.Errorf("%w: truncated box header", ErrInvalid)
+			}
+			_, err := r.ReadAt(header[8:16], offset+8)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if boxSize < headerSize || boxSize > end-offset {
+			return nil, fmt.Errorf("%w: %q box of %d bytes at %d doesn't fit", ErrInvalid, b.typ, boxSize, offset)
+		}
+		b.offset = offset + headerSize
+		b.size = boxSize - headerSize
+		boxes = append(boxes, b)
+		offset += boxSize
+	}
+	return boxes, nil
+}
+
+// children returns the boxes inside the content of a box
+func children(content []byte) ([]box, error) {
+	return readBoxes(bytes.NewReader(content), 0, int64(len(content)))
+}
+
+func parseMoov(content []byte) (Info, error) {
+	info := Info{}
+	boxes, err := children(content)
+	if err != nil {
+		return Info{}, err
+	}
+
+	mvhd := false
+	video := false
+	for _, b := range boxes {
+		data := content[b.offset : b.offset+b.size]
+		switch b.typ {
+		case "mvhd":
+			info.Duration, err = parseMvhd(data)
+			if err != nil {
+				return Info{}, err
+			}
+			mvhd = true
+		case "trak":
+			if video {
+				continue
+			}
+			width, height, ok, err := parseTrak(data)
+			if err != nil {
+				return Info{}, err
+			}
+			if ok {
+				info.Width, info.Height = width, height
+				video = true
+			}
+		}
+	}
+	if !mvhd {
+		return Info{}, fmt.Errorf("%w: no mvhd box", ErrInvalid)
+	}
+	if !video {
+		return Info{}, fmt.Errorf("%w: no video track", ErrInvalid)
+	}
+	return info, nil
+}
+
+// parseMvhd returns the duration of the movie
+func parseMvhd(data []byte) (time.Duration, error) {
+	var timescale, duration uint64
+	switch {
+	case len(data) >= 20 && data[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(data[12:]))
+		duration = uint64(binary.BigEndian.Uint32(data[16:]))
+	case len(data) >= 32 && data[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(data[20:]))
+		duration = binary.BigEndian.Uint64(data[24:])
+	default:
+		return 0, fmt.Errorf("%w: invalid mvhd box", ErrInvalid)
+	}
+	if timescale == 0 {
+		return 0, fmt.Errorf("%w: zero timescale", ErrInvalid)
+	}
+	seconds := duration / timescale
+	rest := duration % timescale
+	return time.Duration(seconds)*time.Second + time.Duration(rest*uint64(time.Second)/timescale), nil
+}
+
+// parseTrak returns the size of the track if it is a video track
+func parseTrak(content []byte) (width, height int, ok bool, err error) {
+	boxes, err := children(content)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	var tkhd []byte
+	for _, b := range boxes {
+		data := content[b.offset : b.offset+b.size]
+		switch b.typ {
+		case "tkhd":
+			tkhd = data
+		case "mdia":
+			handler, err := parseMdia(data)
+			if err != nil {
+				return 0, 0, false, err
+			}
+			ok = handler == "vide"
+		}
+	}
+	if !ok {
+		return 0, 0, false, nil
+	}
+
+	// the size is the last 8 bytes of tkhd in 16.16 fixed point
+	var sizeOffset int
+	switch {
+	case len(tkhd) >= 84 && tkhd[0] == 0:
+		sizeOffset = 76
+	case len(tkhd) >= 96 && tkhd[0] == 1:
+		sizeOffset = 88
+	default:
+		return 0, 0, false, fmt.Errorf("%w: invalid tkhd box", ErrInvalid)
+	}
+	width = int(binary.BigEndian.Uint32(tkhd[sizeOffset:]) >> 16)
+	height = int(binary.BigEndian.Uint32(tkhd[sizeOffset+4:]) >> 16)
+	return width, height, true, nil
+}
+
+// parseMdia returns the handler type of the media, "vide" for video
+func parseMdia(content []byte) (string, error) {
+	boxes, err := children(content)
+	if err != nil {
+		return "", err
+	}
+	for _, b := range boxes {
+		if b.typ == "hdlr" {
+			if b.size < 12 {
+				return "", fmt.Errorf("%w: invalid hdlr box", ErrInvalid)
+			}
+			return string(content[b.offset+8 : b.offset+12]), nil
+		}
+	}
+	return "", nil
+}
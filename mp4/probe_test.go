@@ -0,0 +1,78 @@
+package mp4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+// mp4Box encodes a box with the content
+func mp4Box(typ string, content ...[]byte) []byte {
+	data := bytes.Join(content, nil)
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(8+len(data)))
+	copy(header[4:], typ)
+	return append(header, data...)
+}
+
+func mvhd(timescale, duration uint32) []byte {
+	data := make([]byte, 100)
+	binary.BigEndian.PutUint32(data[12:], timescale)
+	binary.BigEndian.PutUint32(data[16:], duration)
+	return mp4Box("mvhd", data)
+}
+
+func trak(handler string, width, height uint32) []byte {
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], width<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], height<<16)
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:], handler)
+	return mp4Box("trak", mp4Box("tkhd", tkhd), mp4Box("mdia", mp4Box("hdlr", hdlr)))
+}
+
+func TestProbe(t *testing.T) {
+	ftyp := mp4Box("ftyp", []byte("isom\x00\x00\x02\x00isomiso2avc1mp41"))
+	moov := mp4Box("moov", mvhd(1000, 90500), trak("soun", 0, 0), trak("vide", 1280, 720))
+	mdat := mp4Box("mdat", []byte("frames"))
+
+	largeMdat := append([]byte{0, 0, 0, 1, 'm', 'd', 'a', 't', 0, 0, 0, 0, 0, 0, 0, 22}, []byte("frames")...)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Info
+		wantErr bool
+	}{
+		{name: "Video", data: bytes.Join([][]byte{ftyp, moov, mdat}, nil), want: Info{Duration: 90500 * time.Millisecond, Width: 1280, Height: 720}},
+		{name: "Fast start", data: bytes.Join([][]byte{ftyp, mdat, moov}, nil), want: Info{Duration: 90500 * time.Millisecond, Width: 1280, Height: 720}},
+		{name: "Large size", data: bytes.Join([][]byte{ftyp, moov, largeMdat}, nil), want: Info{Duration: 90500 * time.Millisecond, Width: 1280, Height: 720}},
+		{name: "Not MP4", data: []byte("not really a video"), wantErr: true},
+		{name: "No ftyp", data: bytes.Join([][]byte{moov, mdat}, nil), wantErr: true},
+		{name: "No moov", data: bytes.Join([][]byte{ftyp, mdat}, nil), wantErr: true},
+		{name: "No mdat", data: bytes.Join([][]byte{ftyp, moov}, nil), wantErr: true},
+		{name: "Truncated", data: bytes.Join([][]byte{ftyp, moov, mdat}, nil)[:len(ftyp)+len(moov)+4], wantErr: true},
+		{name: "Audio only", data: bytes.Join([][]byte{ftyp, mp4Box("moov", mvhd(1000, 1000), trak("soun", 0, 0)), mdat}, nil), wantErr: true},
+		{name: "Zero timescale", data: bytes.Join([][]byte{ftyp, mp4Box("moov", mvhd(0, 1000), trak("vide", 1, 1)), mdat}, nil), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Probe(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Probe() error = %v, want ErrInvalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Probe() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
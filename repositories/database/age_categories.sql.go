@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"strings"
 )
 
 const createAgeCategory = `-- name: CreateAgeCategory :one
@@ -60,6 +61,53 @@ func (q *Queries) GetAgeCategories(ctx context.Context) ([]AgeCategory, error) {
 	return items, nil
 }
 
+const getAgeCategoriesOfProjects = `-- name: GetAgeCategoriesOfProjects :many
+
+SELECT pac.project_id, ac.id, ac.title FROM age_categories AS ac
+JOIN projects_age_categories AS pac
+ON ac.id = pac.age_category_id
+WHERE pac.project_id IN (/*SLICE:ids*/?)
+ORDER BY pac.project_id, ac.id
+`
+
+type GetAgeCategoriesOfProjectsRow struct {
+	ProjectID   int64
+	AgeCategory AgeCategory
+}
+
+func (q *Queries) GetAgeCategoriesOfProjects(ctx context.Context, ids []int64) ([]GetAgeCategoriesOfProjectsRow, error) {
+	query := getAgeCategoriesOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAgeCategoriesOfProjectsRow
+	for rows.Next() {
+		var i GetAgeCategoriesOfProjectsRow
+		if err := rows.Scan(&i.ProjectID, &i.AgeCategory.ID, &i.AgeCategory.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAgeCategoryById = `-- name: GetAgeCategoryById :one
 
 SELECT id, title FROM age_categories WHERE id = ?
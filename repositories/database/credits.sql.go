@@ -0,0 +1,225 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: credits.sql
+
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+const addCredit = `-- name: AddCredit :one
+INSERT INTO credits(project_id, person_id, role, character, position)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type AddCreditParams struct {
+	ProjectID int64
+	PersonID  int64
+	Role      string
+	Character string
+	Position  int64
+}
+
+func (q *Queries) AddCredit(ctx context.Context, arg AddCreditParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, addCredit,
+		arg.ProjectID,
+		arg.PersonID,
+		arg.Role,
+		arg.Character,
+		arg.Position,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteCredit = `-- name: DeleteCredit :exec
+
+DELETE FROM credits WHERE id = ?
+`
+
+func (q *Queries) DeleteCredit(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCredit, id)
+	return err
+}
+
+const deleteCreditsOfPerson = `-- name: DeleteCreditsOfPerson :exec
+
+DELETE FROM credits WHERE person_id = ?
+`
+
+func (q *Queries) DeleteCreditsOfPerson(ctx context.Context, personID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCreditsOfPerson, personID)
+	return err
+}
+
+const deleteCreditsOfProject = `-- name: DeleteCreditsOfProject :exec
+
+DELETE FROM credits WHERE project_id = ?
+`
+
+func (q *Queries) DeleteCreditsOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCreditsOfProject, projectID)
+	return err
+}
+
+const getCredit = `-- name: GetCredit :one
+
+SELECT id, created_at, project_id, person_id, role, character, position FROM credits
+WHERE project_id = ? AND person_id = ? AND role = ? AND character = ?
+`
+
+type GetCreditParams struct {
+	ProjectID int64
+	PersonID  int64
+	Role      string
+	Character string
+}
+
+func (q *Queries) GetCredit(ctx context.Context, arg GetCreditParams) (Credit, error) {
+	row := q.db.QueryRowContext(ctx, getCredit,
+		arg.ProjectID,
+		arg.PersonID,
+		arg.Role,
+		arg.Character,
+	)
+	var i Credit
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ProjectID,
+		&i.PersonID,
+		&i.Role,
+		&i.Character,
+		&i.Position,
+	)
+	return i, err
+}
+
+const getCreditById = `-- name: GetCreditById :one
+
+SELECT id, created_at, project_id, person_id, role, character, position FROM credits WHERE id = ?
+`
+
+func (q *Queries) GetCreditById(ctx context.Context, id int64) (Credit, error) {
+	row := q.db.QueryRowContext(ctx, getCreditById, id)
+	var i Credit
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ProjectID,
+		&i.PersonID,
+		&i.Role,
+		&i.Character,
+		&i.Position,
+	)
+	return i, err
+}
+
+const getCreditsOfPerson = `-- name: GetCreditsOfPerson :many
+
+SELECT id, created_at, project_id, person_id, role, character, position FROM credits
+WHERE person_id = ?
+ORDER BY project_id, role, id
+`
+
+func (q *Queries) GetCreditsOfPerson(ctx context.Context, personID int64) ([]Credit, error) {
+	rows, err := q.db.QueryContext(ctx, getCreditsOfPerson, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Credit
+	for rows.Next() {
+		var i Credit
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ProjectID,
+			&i.PersonID,
+			&i.Role,
+			&i.Character,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCreditsOfProjects = `-- name: GetCreditsOfProjects :many
+
+SELECT c.id, c.created_at, c.project_id, c.person_id, c.role, c.character, c.position, p.name, p.photo FROM credits AS c
+JOIN people AS p
+ON p.id = c.person_id
+WHERE c.project_id IN (/*SLICE:ids*/?)
+ORDER BY c.project_id,
+    CASE c.role WHEN 'director' THEN 0 WHEN 'writer' THEN 1 WHEN 'producer' THEN 2 ELSE 3 END,
+    c.position, c.id
+`
+
+type GetCreditsOfProjectsRow struct {
+	ID        int64
+	CreatedAt string
+	ProjectID int64
+	PersonID  int64
+	Role      string
+	Character string
+	Position  int64
+	Name      string
+	Photo     string
+}
+
+func (q *Queries) GetCreditsOfProjects(ctx context.Context, ids []int64) ([]GetCreditsOfProjectsRow, error) {
+	query := getCreditsOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCreditsOfProjectsRow
+	for rows.Next() {
+		var i GetCreditsOfProjectsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ProjectID,
+			&i.PersonID,
+			&i.Role,
+			&i.Character,
+			&i.Position,
+			&i.Name,
+			&i.Photo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,70 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: email_verification_tokens.sql
+
+package database
+
+import (
+	"context"
+)
+
+const createEmailVerificationToken = `-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens(token_hash, user_id, email, expires_at)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateEmailVerificationTokenParams struct {
+	TokenHash string
+	UserID    int64
+	Email     string
+	ExpiresAt string
+}
+
+func (q *Queries) CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createEmailVerificationToken,
+		arg.TokenHash,
+		arg.UserID,
+		arg.Email,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const deleteEmailVerificationTokensOfUser = `-- name: DeleteEmailVerificationTokensOfUser :exec
+
+DELETE FROM email_verification_tokens
+WHERE user_id = ? AND used_at IS NULL
+`
+
+func (q *Queries) DeleteEmailVerificationTokensOfUser(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEmailVerificationTokensOfUser, userID)
+	return err
+}
+
+const useEmailVerificationToken = `-- name: UseEmailVerificationToken :one
+
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = ?
+    AND used_at IS NULL
+    AND expires_at > ?2
+RETURNING user_id, email
+`
+
+type UseEmailVerificationTokenParams struct {
+	TokenHash string
+	Now       string
+}
+
+type UseEmailVerificationTokenRow struct {
+	UserID int64
+	Email  string
+}
+
+func (q *Queries) UseEmailVerificationToken(ctx context.Context, arg UseEmailVerificationTokenParams) (UseEmailVerificationTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, useEmailVerificationToken, arg.TokenHash, arg.Now)
+	var i UseEmailVerificationTokenRow
+	err := row.Scan(&i.UserID, &i.Email)
+	return i, err
+}
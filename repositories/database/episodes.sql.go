@@ -0,0 +1,270 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: episodes.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+const createEpisode = `-- name: CreateEpisode :one
+INSERT INTO episodes(season_id, number, title, synopsis, duration_in_secs, release_date, thumbnail, video_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type CreateEpisodeParams struct {
+	SeasonID       int64
+	Number         int64
+	Title          string
+	Synopsis       string
+	DurationInSecs int64
+	ReleaseDate    string
+	Thumbnail      sql.NullString
+	VideoID        sql.NullString
+}
+
+func (q *Queries) CreateEpisode(ctx context.Context, arg CreateEpisodeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createEpisode,
+		arg.SeasonID,
+		arg.Number,
+		arg.Title,
+		arg.Synopsis,
+		arg.DurationInSecs,
+		arg.ReleaseDate,
+		arg.Thumbnail,
+		arg.VideoID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteEpisode = `-- name: DeleteEpisode :exec
+
+DELETE FROM episodes WHERE id = ?
+`
+
+func (q *Queries) DeleteEpisode(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEpisode, id)
+	return err
+}
+
+const deleteEpisodesOfProject = `-- name: DeleteEpisodesOfProject :exec
+
+DELETE FROM episodes
+WHERE season_id IN (SELECT id FROM seasons WHERE project_id = ?)
+`
+
+func (q *Queries) DeleteEpisodesOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEpisodesOfProject, projectID)
+	return err
+}
+
+const deleteEpisodesOfSeason = `-- name: DeleteEpisodesOfSeason :exec
+
+DELETE FROM episodes WHERE season_id = ?
+`
+
+func (q *Queries) DeleteEpisodesOfSeason(ctx context.Context, seasonID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEpisodesOfSeason, seasonID)
+	return err
+}
+
+const getEpisodeById = `-- name: GetEpisodeById :one
+
+SELECT id, created_at, updated_at, season_id, number, title, synopsis, duration_in_secs, release_date, thumbnail, video_id FROM episodes WHERE id = ?
+`
+
+func (q *Queries) GetEpisodeById(ctx context.Context, id int64) (Episode, error) {
+	row := q.db.QueryRowContext(ctx, getEpisodeById, id)
+	var i Episode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.Number,
+		&i.Title,
+		&i.Synopsis,
+		&i.DurationInSecs,
+		&i.ReleaseDate,
+		&i.Thumbnail,
+		&i.VideoID,
+	)
+	return i, err
+}
+
+const getEpisodeByNumber = `-- name: GetEpisodeByNumber :one
+
+SELECT id, created_at, updated_at, season_id, number, title, synopsis, duration_in_secs, release_date, thumbnail, video_id FROM episodes WHERE season_id = ? AND number = ?
+`
+
+type GetEpisodeByNumberParams struct {
+	SeasonID int64
+	Number   int64
+}
+
+func (q *Queries) GetEpisodeByNumber(ctx context.Context, arg GetEpisodeByNumberParams) (Episode, error) {
+	row := q.db.QueryRowContext(ctx, getEpisodeByNumber, arg.SeasonID, arg.Number)
+	var i Episode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SeasonID,
+		&i.Number,
+		&i.Title,
+		&i.Synopsis,
+		&i.DurationInSecs,
+		&i.ReleaseDate,
+		&i.Thumbnail,
+		&i.VideoID,
+	)
+	return i, err
+}
+
+const getEpisodesOfProjects = `-- name: GetEpisodesOfProjects :many
+
+SELECT s.project_id, e.id, e.created_at, e.updated_at, e.season_id, e.number, e.title, e.synopsis, e.duration_in_secs, e.release_date, e.thumbnail, e.video_id FROM episodes AS e
+JOIN seasons AS s
+ON s.id = e.season_id
+WHERE s.project_id IN (/*SLICE:ids*/?)
+ORDER BY s.project_id, s.number, e.number
+`
+
+type GetEpisodesOfProjectsRow struct {
+	ProjectID int64
+	Episode   Episode
+}
+
+func (q *Queries) GetEpisodesOfProjects(ctx context.Context, ids []int64) ([]GetEpisodesOfProjectsRow, error) {
+	query := getEpisodesOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEpisodesOfProjectsRow
+	for rows.Next() {
+		var i GetEpisodesOfProjectsRow
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.Episode.ID,
+			&i.Episode.CreatedAt,
+			&i.Episode.UpdatedAt,
+			&i.Episode.SeasonID,
+			&i.Episode.Number,
+			&i.Episode.Title,
+			&i.Episode.Synopsis,
+			&i.Episode.DurationInSecs,
+			&i.Episode.ReleaseDate,
+			&i.Episode.Thumbnail,
+			&i.Episode.VideoID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setEpisodeVideo = `-- name: SetEpisodeVideo :exec
+
+INSERT INTO episodes(season_id, number, video_id, duration_in_secs)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(season_id, number) DO UPDATE
+SET updated_at = CURRENT_TIMESTAMP,
+    video_id = excluded.video_id,
+    duration_in_secs = CASE WHEN episodes.duration_in_secs = 0
+        THEN excluded.duration_in_secs
+        ELSE episodes.duration_in_secs END
+`
+
+type SetEpisodeVideoParams struct {
+	SeasonID       int64
+	Number         int64
+	VideoID        sql.NullString
+	DurationInSecs int64
+}
+
+func (q *Queries) SetEpisodeVideo(ctx context.Context, arg SetEpisodeVideoParams) error {
+	_, err := q.db.ExecContext(ctx, setEpisodeVideo,
+		arg.SeasonID,
+		arg.Number,
+		arg.VideoID,
+		arg.DurationInSecs,
+	)
+	return err
+}
+
+const unsetVideoOfEpisodes = `-- name: UnsetVideoOfEpisodes :exec
+
+UPDATE episodes
+SET updated_at = CURRENT_TIMESTAMP,
+    video_id = NULL
+WHERE video_id = ?
+`
+
+func (q *Queries) UnsetVideoOfEpisodes(ctx context.Context, videoID sql.NullString) error {
+	_, err := q.db.ExecContext(ctx, unsetVideoOfEpisodes, videoID)
+	return err
+}
+
+const updateEpisode = `-- name: UpdateEpisode :exec
+
+UPDATE episodes
+SET updated_at = CURRENT_TIMESTAMP,
+    number = ?,
+    title = ?,
+    synopsis = ?,
+    duration_in_secs = ?,
+    release_date = ?,
+    thumbnail = ?,
+    video_id = ?
+WHERE id = ?
+`
+
+type UpdateEpisodeParams struct {
+	Number         int64
+	Title          string
+	Synopsis       string
+	DurationInSecs int64
+	ReleaseDate    string
+	Thumbnail      sql.NullString
+	VideoID        sql.NullString
+	ID             int64
+}
+
+func (q *Queries) UpdateEpisode(ctx context.Context, arg UpdateEpisodeParams) error {
+	_, err := q.db.ExecContext(ctx, updateEpisode,
+		arg.Number,
+		arg.Title,
+		arg.Synopsis,
+		arg.DurationInSecs,
+		arg.ReleaseDate,
+		arg.Thumbnail,
+		arg.VideoID,
+		arg.ID,
+	)
+	return err
+}
@@ -12,6 +12,7 @@ import (
 const addProject2Favourites = `-- name: AddProject2Favourites :exec
 INSERT INTO favourites(user_id, project_id)
 VALUES (?, ?)
+ON CONFLICT(user_id, project_id) DO NOTHING
 `
 
 type AddProject2FavouritesParams struct {
@@ -24,6 +25,16 @@ func (q *Queries) AddProject2Favourites(ctx context.Context, arg AddProject2Favo
 	return err
 }
 
+const deleteFavouritesOfProject = `-- name: DeleteFavouritesOfProject :exec
+
+DELETE FROM favourites WHERE project_id = ?
+`
+
+func (q *Queries) DeleteFavouritesOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFavouritesOfProject, projectID)
+	return err
+}
+
 const deleteProjectFromFavourites = `-- name: DeleteProjectFromFavourites :exec
 
 DELETE FROM favourites WHERE user_id = ? AND project_id = ?
@@ -38,3 +49,48 @@ func (q *Queries) DeleteProjectFromFavourites(ctx context.Context, arg DeletePro
 	_, err := q.db.ExecContext(ctx, deleteProjectFromFavourites, arg.UserID, arg.ProjectID)
 	return err
 }
+
+const getFavouriteProjectsOfUser = `-- name: GetFavouriteProjectsOfUser :many
+
+SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords FROM projects AS p
+JOIN favourites AS f
+ON p.id = f.project_id
+WHERE f.user_id = ?
+ORDER BY f.added_at DESC, f.rowid DESC
+`
+
+func (q *Queries) GetFavouriteProjectsOfUser(ctx context.Context, userID int64) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, getFavouriteProjectsOfUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Description,
+			&i.TypeID,
+			&i.DurationInMins,
+			&i.ReleaseYear,
+			&i.Director,
+			&i.Producer,
+			&i.Cover,
+			&i.Keywords,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
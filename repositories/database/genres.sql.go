@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"strings"
 )
 
 const createGenre = `-- name: CreateGenre :one
@@ -103,6 +104,53 @@ func (q *Queries) GetGenres(ctx context.Context) ([]Genre, error) {
 	return items, nil
 }
 
+const getGenresOfProjects = `-- name: GetGenresOfProjects :many
+
+SELECT mg.project_id, g.id, g.title FROM genres AS g
+JOIN projects_genres AS mg
+ON g.id = mg.genre_id
+WHERE mg.project_id IN (/*SLICE:ids*/?)
+ORDER BY mg.project_id, g.id
+`
+
+type GetGenresOfProjectsRow struct {
+	ProjectID int64
+	Genre     Genre
+}
+
+func (q *Queries) GetGenresOfProjects(ctx context.Context, ids []int64) ([]GetGenresOfProjectsRow, error) {
+	query := getGenresOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGenresOfProjectsRow
+	for rows.Next() {
+		var i GetGenresOfProjectsRow
+		if err := rows.Scan(&i.ProjectID, &i.Genre.ID, &i.Genre.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateGenre = `-- name: UpdateGenre :exec
 
 UPDATE genres 
@@ -7,21 +7,30 @@ package database
 
 import (
 	"context"
+	"strings"
 )
 
 const addImage2Movie = `-- name: AddImage2Movie :exec
-INSERT INTO images(id, project_id, href)
-VALUES (?, ?, ?)
+INSERT INTO images(id, project_id, href, width, height)
+VALUES (?, ?, ?, ?, ?)
 `
 
 type AddImage2MovieParams struct {
 	ID        string
 	ProjectID int64
 	Href      string
+	Width     int64
+	Height    int64
 }
 
 func (q *Queries) AddImage2Movie(ctx context.Context, arg AddImage2MovieParams) error {
-	_, err := q.db.ExecContext(ctx, addImage2Movie, arg.ID, arg.ProjectID, arg.Href)
+	_, err := q.db.ExecContext(ctx, addImage2Movie,
+		arg.ID,
+		arg.ProjectID,
+		arg.Href,
+		arg.Width,
+		arg.Height,
+	)
 	return err
 }
 
@@ -35,9 +44,19 @@ func (q *Queries) DeleteImage(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteImagesOfProject = `-- name: DeleteImagesOfProject :exec
+
+DELETE FROM images WHERE project_id = ?
+`
+
+func (q *Queries) DeleteImagesOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteImagesOfProject, projectID)
+	return err
+}
+
 const getImage = `-- name: GetImage :one
 
-SELECT id, created_at, updated_at, project_id, href FROM images
+SELECT id, created_at, updated_at, project_id, href, width, height FROM images
 WHERE id = ?
 `
 
@@ -50,13 +69,15 @@ func (q *Queries) GetImage(ctx context.Context, id string) (Image, error) {
 		&i.UpdatedAt,
 		&i.ProjectID,
 		&i.Href,
+		&i.Width,
+		&i.Height,
 	)
 	return i, err
 }
 
 const getImages = `-- name: GetImages :many
 
-SELECT id, created_at, updated_at, project_id, href FROM images
+SELECT id, created_at, updated_at, project_id, href, width, height FROM images
 `
 
 func (q *Queries) GetImages(ctx context.Context) ([]Image, error) {
@@ -74,6 +95,55 @@ func (q *Queries) GetImages(ctx context.Context) ([]Image, error) {
 			&i.UpdatedAt,
 			&i.ProjectID,
 			&i.Href,
+			&i.Width,
+			&i.Height,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getImagesByIds = `-- name: GetImagesByIds :many
+
+SELECT id, created_at, updated_at, project_id, href, width, height FROM images
+WHERE id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) GetImagesByIds(ctx context.Context, ids []string) ([]Image, error) {
+	query := getImagesByIds
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Image
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProjectID,
+			&i.Href,
+			&i.Width,
+			&i.Height,
 		); err != nil {
 			return nil, err
 		}
@@ -90,7 +160,7 @@ func (q *Queries) GetImages(ctx context.Context) ([]Image, error) {
 
 const getImagesOfProject = `-- name: GetImagesOfProject :many
 
-SELECT id, created_at, updated_at, project_id, href FROM images
+SELECT id, created_at, updated_at, project_id, href, width, height FROM images
 WHERE project_id = ?
 `
 
@@ -109,6 +179,56 @@ func (q *Queries) GetImagesOfProject(ctx context.Context, projectID int64) ([]Im
 			&i.UpdatedAt,
 			&i.ProjectID,
 			&i.Href,
+			&i.Width,
+			&i.Height,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getImagesOfProjects = `-- name: GetImagesOfProjects :many
+
+SELECT id, created_at, updated_at, project_id, href, width, height FROM images
+WHERE project_id IN (/*SLICE:ids*/?)
+ORDER BY project_id, created_at, id
+`
+
+func (q *Queries) GetImagesOfProjects(ctx context.Context, ids []int64) ([]Image, error) {
+	query := getImagesOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Image
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProjectID,
+			&i.Href,
+			&i.Width,
+			&i.Height,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,104 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: login_attempts.sql
+
+package database
+
+import (
+	"context"
+)
+
+const addFirstLoginAttempt = `-- name: AddFirstLoginAttempt :execrows
+
+INSERT INTO login_attempts(key, failures, last_failed_at)
+VALUES (?, 1, ?)
+ON CONFLICT(key) DO NOTHING
+`
+
+type AddFirstLoginAttemptParams struct {
+	Key          string
+	LastFailedAt int64
+}
+
+func (q *Queries) AddFirstLoginAttempt(ctx context.Context, arg AddFirstLoginAttemptParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addFirstLoginAttempt, arg.Key, arg.LastFailedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const addLoginAttempt = `-- name: AddLoginAttempt :execrows
+
+UPDATE login_attempts
+SET failures = failures + 1,
+    previous_failed_at = last_failed_at,
+    last_failed_at = ?1
+WHERE key = ?2 AND failures = ?3
+`
+
+type AddLoginAttemptParams struct {
+	LastFailedAt int64
+	Key          string
+	Failures     int64
+}
+
+// the attempt is counted only if the failures are still the read ones
+func (q *Queries) AddLoginAttempt(ctx context.Context, arg AddLoginAttemptParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addLoginAttempt, arg.LastFailedAt, arg.Key, arg.Failures)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLoginAttempts = `-- name: DeleteLoginAttempts :exec
+
+DELETE FROM login_attempts WHERE key = ?
+`
+
+func (q *Queries) DeleteLoginAttempts(ctx context.Context, key string) error {
+	_, err := q.db.ExecContext(ctx, deleteLoginAttempts, key)
+	return err
+}
+
+const deleteStaleLoginAttempts = `-- name: DeleteStaleLoginAttempts :exec
+
+DELETE FROM login_attempts WHERE last_failed_at < ?
+`
+
+func (q *Queries) DeleteStaleLoginAttempts(ctx context.Context, lastFailedAt int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStaleLoginAttempts, lastFailedAt)
+	return err
+}
+
+const forgiveLoginAttempt = `-- name: ForgiveLoginAttempt :exec
+
+UPDATE login_attempts
+SET failures = failures - 1,
+    last_failed_at = previous_failed_at
+WHERE key = ? AND failures > 0
+`
+
+// the wait is as if the attempt wasn't made
+func (q *Queries) ForgiveLoginAttempt(ctx context.Context, key string) error {
+	_, err := q.db.ExecContext(ctx, forgiveLoginAttempt, key)
+	return err
+}
+
+const getLoginAttempts = `-- name: GetLoginAttempts :one
+SELECT failures, last_failed_at FROM login_attempts WHERE key = ?
+`
+
+type GetLoginAttemptsRow struct {
+	Failures     int64
+	LastFailedAt int64
+}
+
+func (q *Queries) GetLoginAttempts(ctx context.Context, key string) (GetLoginAttemptsRow, error) {
+	row := q.db.QueryRowContext(ctx, getLoginAttempts, key)
+	var i GetLoginAttemptsRow
+	err := row.Scan(&i.Failures, &i.LastFailedAt)
+	return i, err
+}
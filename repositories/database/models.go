@@ -13,6 +13,39 @@ type AgeCategory struct {
 	Title string
 }
 
+type Credit struct {
+	ID        int64
+	CreatedAt string
+	ProjectID int64
+	PersonID  int64
+	Role      string
+	Character string
+	Position  int64
+}
+
+type EmailVerificationToken struct {
+	TokenHash string
+	CreatedAt string
+	UserID    int64
+	Email     string
+	ExpiresAt string
+	UsedAt    sql.NullString
+}
+
+type Episode struct {
+	ID             int64
+	CreatedAt      string
+	UpdatedAt      string
+	SeasonID       int64
+	Number         int64
+	Title          string
+	Synopsis       string
+	DurationInSecs int64
+	ReleaseDate    string
+	Thumbnail      sql.NullString
+	VideoID        sql.NullString
+}
+
 type Favourite struct {
 	AddedAt   string
 	UserID    int64
@@ -30,6 +63,23 @@ type Image struct {
 	UpdatedAt string
 	ProjectID int64
 	Href      string
+	Width     int64
+	Height    int64
+}
+
+type LoginAttempt struct {
+	Key              string
+	Failures         int64
+	LastFailedAt     int64
+	PreviousFailedAt int64
+}
+
+type PasswordResetToken struct {
+	TokenHash string
+	CreatedAt string
+	UserID    int64
+	ExpiresAt string
+	UsedAt    sql.NullString
 }
 
 type Permission struct {
@@ -37,6 +87,15 @@ type Permission struct {
 	Title string
 }
 
+type Person struct {
+	ID        int64
+	CreatedAt string
+	UpdatedAt string
+	Name      string
+	Photo     string
+	Bio       string
+}
+
 type Project struct {
 	ID             int64
 	CreatedAt      string
@@ -62,24 +121,75 @@ type ProjectsGenre struct {
 	GenreID   int64
 }
 
-type RefreshToken struct {
-	Token     string
+type ProjectsSearch struct {
+	Title       string
+	Description string
+	Keywords    string
+}
+
+type Rating struct {
+	ID        int64
 	CreatedAt string
 	UpdatedAt string
 	UserID    int64
-	ExpiresAt string
-	RevokedAt sql.NullString
+	ProjectID int64
+	Stars     int64
+	Review    string
+	Hidden    bool
+}
+
+type RecoveryCode struct {
+	CodeHash  string
+	CreatedAt string
+	UserID    int64
+	UsedAt    sql.NullString
+}
+
+type RefreshToken struct {
+	Token      string
+	CreatedAt  string
+	UpdatedAt  string
+	UserID     int64
+	ExpiresAt  string
+	RevokedAt  sql.NullString
+	Family     string
+	ReplacedBy sql.NullString
+	UserAgent  string
+	Ip         string
+	SignedInAt string
 }
 
 type Role struct {
-	ID            int64
-	Title         string
-	Projects      int64
-	Genres        int64
-	AgeCategories int64
-	Types         int64
-	Users         int64
-	Roles         int64
+	ID               int64
+	Title            string
+	Projects         int64
+	Genres           int64
+	AgeCategories    int64
+	Types            int64
+	Users            int64
+	Roles            int64
+	RequireTwoFactor bool
+}
+
+type Season struct {
+	ID        int64
+	CreatedAt string
+	UpdatedAt string
+	ProjectID int64
+	Number    int64
+	Title     string
+	Synopsis  string
+}
+
+type Subtitle struct {
+	ID        int64
+	CreatedAt string
+	UpdatedAt string
+	VideoID   string
+	Language  string
+	Label     string
+	IsDefault bool
+	File      string
 }
 
 type Type struct {
@@ -87,15 +197,31 @@ type Type struct {
 	Title string
 }
 
+type Upload struct {
+	ID        string
+	CreatedAt string
+	ExpiresAt string
+	UserID    int64
+	Length    int64
+	Received  int64
+	Metadata  string
+	MediaID   string
+	Parts     string
+}
+
 type User struct {
-	ID           int64
-	CreatedAt    string
-	UpdatedAt    string
-	Name         string
-	Email        string
-	PasswordHash string
-	DateOfBirth  string
-	Phone        string
+	ID              int64
+	CreatedAt       string
+	UpdatedAt       string
+	Name            string
+	Email           string
+	PasswordHash    string
+	DateOfBirth     string
+	Phone           string
+	EmailVerifiedAt sql.NullString
+	TotpSecret      sql.NullString
+	TotpEnabledAt   sql.NullString
+	TotpCounter     int64
 }
 
 type UsersRole struct {
@@ -105,13 +231,28 @@ type UsersRole struct {
 }
 
 type Video struct {
-	ID        string
-	CreatedAt string
-	UpdatedAt string
-	ProjectID int64
-	Season    int64
-	Serie     int64
-	Href      string
+	ID             string
+	CreatedAt      string
+	UpdatedAt      string
+	ProjectID      int64
+	Season         int64
+	Serie          int64
+	Href           string
+	HlsStatus      string
+	HlsError       string
+	DurationInSecs int64
+	Width          int64
+	Height         int64
+}
+
+type WatchProgress struct {
+	CreatedAt      string
+	UpdatedAt      string
+	UserID         int64
+	VideoID        string
+	PositionInSecs int64
+	DurationInSecs int64
+	Completed      bool
 }
 
 type Watchlist struct {
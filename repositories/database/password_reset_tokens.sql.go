@@ -0,0 +1,59 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: password_reset_tokens.sql
+
+package database
+
+import (
+	"context"
+)
+
+const createPasswordResetToken = `-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens(token_hash, user_id, expires_at)
+VALUES (?, ?, ?)
+`
+
+type CreatePasswordResetTokenParams struct {
+	TokenHash string
+	UserID    int64
+	ExpiresAt string
+}
+
+func (q *Queries) CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createPasswordResetToken, arg.TokenHash, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const deletePasswordResetTokensOfUser = `-- name: DeletePasswordResetTokensOfUser :exec
+
+DELETE FROM password_reset_tokens
+WHERE user_id = ? AND used_at IS NULL
+`
+
+func (q *Queries) DeletePasswordResetTokensOfUser(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePasswordResetTokensOfUser, userID)
+	return err
+}
+
+const usePasswordResetToken = `-- name: UsePasswordResetToken :one
+
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = ?
+    AND used_at IS NULL
+    AND expires_at > ?2
+RETURNING user_id
+`
+
+type UsePasswordResetTokenParams struct {
+	TokenHash string
+	Now       string
+}
+
+func (q *Queries) UsePasswordResetToken(ctx context.Context, arg UsePasswordResetTokenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, usePasswordResetToken, arg.TokenHash, arg.Now)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
@@ -0,0 +1,187 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: people.sql
+
+package database
+
+import (
+	"context"
+)
+
+const countPeople = `-- name: CountPeople :one
+
+SELECT COUNT(*) FROM people
+WHERE name LIKE ?1
+`
+
+func (q *Queries) CountPeople(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPeople, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPerson = `-- name: CreatePerson :one
+INSERT INTO people(name, bio)
+VALUES (?, ?)
+RETURNING id
+`
+
+type CreatePersonParams struct {
+	Name string
+	Bio  string
+}
+
+func (q *Queries) CreatePerson(ctx context.Context, arg CreatePersonParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createPerson, arg.Name, arg.Bio)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deletePerson = `-- name: DeletePerson :exec
+
+DELETE FROM people WHERE id = ?
+`
+
+func (q *Queries) DeletePerson(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePerson, id)
+	return err
+}
+
+const getPeople = `-- name: GetPeople :many
+
+SELECT id, created_at, updated_at, name, photo, bio FROM people
+WHERE name LIKE ?1
+ORDER BY name, id
+LIMIT ?3 OFFSET ?2
+`
+
+type GetPeopleParams struct {
+	Search string
+	Offset int64
+	Limit  int64
+}
+
+func (q *Queries) GetPeople(ctx context.Context, arg GetPeopleParams) ([]Person, error) {
+	rows, err := q.db.QueryContext(ctx, getPeople, arg.Search, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Person
+	for rows.Next() {
+		var i Person
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Photo,
+			&i.Bio,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPeoplePhotos = `-- name: GetPeoplePhotos :many
+
+SELECT id, photo FROM people
+WHERE photo != ''
+ORDER BY id
+`
+
+type GetPeoplePhotosRow struct {
+	ID    int64
+	Photo string
+}
+
+func (q *Queries) GetPeoplePhotos(ctx context.Context) ([]GetPeoplePhotosRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPeoplePhotos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPeoplePhotosRow
+	for rows.Next() {
+		var i GetPeoplePhotosRow
+		if err := rows.Scan(&i.ID, &i.Photo); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPersonById = `-- name: GetPersonById :one
+
+SELECT id, created_at, updated_at, name, photo, bio FROM people WHERE id = ?
+`
+
+func (q *Queries) GetPersonById(ctx context.Context, id int64) (Person, error) {
+	row := q.db.QueryRowContext(ctx, getPersonById, id)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Photo,
+		&i.Bio,
+	)
+	return i, err
+}
+
+const setPersonPhoto = `-- name: SetPersonPhoto :exec
+
+UPDATE people
+SET updated_at = CURRENT_TIMESTAMP,
+    photo = ?
+WHERE id = ?
+`
+
+type SetPersonPhotoParams struct {
+	Photo string
+	ID    int64
+}
+
+func (q *Queries) SetPersonPhoto(ctx context.Context, arg SetPersonPhotoParams) error {
+	_, err := q.db.ExecContext(ctx, setPersonPhoto, arg.Photo, arg.ID)
+	return err
+}
+
+const updatePerson = `-- name: UpdatePerson :exec
+
+UPDATE people
+SET updated_at = CURRENT_TIMESTAMP,
+    name = ?,
+    bio = ?
+WHERE id = ?
+`
+
+type UpdatePersonParams struct {
+	Name string
+	Bio  string
+	ID   int64
+}
+
+func (q *Queries) UpdatePerson(ctx context.Context, arg UpdatePersonParams) error {
+	_, err := q.db.ExecContext(ctx, updatePerson, arg.Name, arg.Bio, arg.ID)
+	return err
+}
@@ -11,10 +11,72 @@ import (
 	"strings"
 )
 
+const countProjects = `-- name: CountProjects :one
+
+SELECT COUNT(*) FROM projects AS p
+WHERE (p.type_id = ?1 OR ?1 IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_genres AS pg
+            WHERE pg.project_id = p.id AND pg.genre_id = ?2)
+        OR ?2 IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_age_categories AS pac
+            WHERE pac.project_id = p.id AND pac.age_category_id = ?3)
+        OR ?3 IS NULL)
+    AND (p.release_year >= ?4 OR ?4 IS NULL)
+    AND (p.release_year <= ?5 OR ?5 IS NULL)
+`
+
+type CountProjectsParams struct {
+	TypeID        sql.NullInt64
+	GenreID       sql.NullInt64
+	AgeCategoryID sql.NullInt64
+	YearFrom      sql.NullInt64
+	YearTo        sql.NullInt64
+}
+
+func (q *Queries) CountProjects(ctx context.Context, arg CountProjectsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countProjects,
+		arg.TypeID,
+		arg.GenreID,
+		arg.AgeCategoryID,
+		arg.YearFrom,
+		arg.YearTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countProjectsOfGenres = `-- name: CountProjectsOfGenres :one
+
+SELECT COUNT(*) FROM projects AS p
+WHERE EXISTS (
+    SELECT 1 FROM projects_genres AS pg
+    WHERE pg.project_id = p.id AND pg.genre_id IN (/*SLICE:ids*/?))
+`
+
+func (q *Queries) CountProjectsOfGenres(ctx context.Context, ids []int64) (int64, error) {
+	query := countProjectsOfGenres
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	row := q.db.QueryRowContext(ctx, query, queryParams...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProject = `-- name: CreateProject :one
 
-INSERT INTO projects(title, description, type_id, duration_in_mins, release_year, director, producer, keywords)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO projects(title, description, type_id, duration_in_mins, release_year, keywords)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING id
 `
 
@@ -24,8 +86,6 @@ type CreateProjectParams struct {
 	TypeID         int64
 	DurationInMins int64
 	ReleaseYear    int64
-	Director       string
-	Producer       string
 	Keywords       string
 }
 
@@ -36,8 +96,6 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (i
 		arg.TypeID,
 		arg.DurationInMins,
 		arg.ReleaseYear,
-		arg.Director,
-		arg.Producer,
 		arg.Keywords,
 	)
 	var id int64
@@ -55,11 +113,33 @@ func (q *Queries) DeleteProject(ctx context.Context, id int64) error {
 	return err
 }
 
+const fillProjectDuration = `-- name: FillProjectDuration :exec
+
+UPDATE projects
+SET updated_at = CURRENT_TIMESTAMP,
+    duration_in_mins = ?
+WHERE id = ? AND duration_in_mins = 0
+`
+
+type FillProjectDurationParams struct {
+	DurationInMins int64
+	ID             int64
+}
+
+func (q *Queries) FillProjectDuration(ctx context.Context, arg FillProjectDurationParams) error {
+	_, err := q.db.ExecContext(ctx, fillProjectDuration, arg.DurationInMins, arg.ID)
+	return err
+}
+
 const getProjectById = `-- name: GetProjectById :one
 
+
 SELECT id, created_at, updated_at, title, description, type_id, duration_in_mins, release_year, director, producer, cover, keywords FROM projects WHERE id = ?
 `
 
+// -- name: PragmaCaseSensitiveOFF :exec
+// PRAGMA case_sensitive_like = OFF;
+// --
 func (q *Queries) GetProjectById(ctx context.Context, id int64) (Project, error) {
 	row := q.db.QueryRowContext(ctx, getProjectById, id)
 	var i Project
@@ -120,16 +200,23 @@ func (q *Queries) GetProjects(ctx context.Context) ([]Project, error) {
 	return items, nil
 }
 
-const getProjectsOfAgeCategory = `-- name: GetProjectsOfAgeCategory :many
+const getProjectsByIds = `-- name: GetProjectsByIds :many
 
-SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords FROM projects AS p
-JOIN projects_age_categories AS pac 
-ON p.id = pac.project_id
-WHERE pac.age_category_id = ?
+SELECT id, created_at, updated_at, title, description, type_id, duration_in_mins, release_year, director, producer, cover, keywords FROM projects WHERE id IN (/*SLICE:ids*/?)
 `
 
-func (q *Queries) GetProjectsOfAgeCategory(ctx context.Context, ageCategoryID int64) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, getProjectsOfAgeCategory, ageCategoryID)
+func (q *Queries) GetProjectsByIds(ctx context.Context, ids []int64) ([]Project, error) {
+	query := getProjectsByIds
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
 	}
@@ -164,16 +251,16 @@ func (q *Queries) GetProjectsOfAgeCategory(ctx context.Context, ageCategoryID in
 	return items, nil
 }
 
-const getProjectsOfGenre = `-- name: GetProjectsOfGenre :many
+const getProjectsOfAgeCategory = `-- name: GetProjectsOfAgeCategory :many
 
 SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords FROM projects AS p
-JOIN projects_genres AS pg 
-ON p.id = pg.project_id
-WHERE pg.genre_id = ?
+JOIN projects_age_categories AS pac 
+ON p.id = pac.project_id
+WHERE pac.age_category_id = ?
 `
 
-func (q *Queries) GetProjectsOfGenre(ctx context.Context, genreID int64) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, getProjectsOfGenre, genreID)
+func (q *Queries) GetProjectsOfAgeCategory(ctx context.Context, ageCategoryID int64) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, getProjectsOfAgeCategory, ageCategoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -208,26 +295,16 @@ func (q *Queries) GetProjectsOfGenre(ctx context.Context, genreID int64) ([]Proj
 	return items, nil
 }
 
-const getProjectsOfGenrers = `-- name: GetProjectsOfGenrers :many
+const getProjectsOfGenre = `-- name: GetProjectsOfGenre :many
 
 SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords FROM projects AS p
 JOIN projects_genres AS pg 
 ON p.id = pg.project_id
-WHERE pg.genre_id IN (/*SLICE:ids*/?)
+WHERE pg.genre_id = ?
 `
 
-func (q *Queries) GetProjectsOfGenrers(ctx context.Context, ids []int64) ([]Project, error) {
-	query := getProjectsOfGenrers
-	var queryParams []interface{}
-	if len(ids) > 0 {
-		for _, v := range ids {
-			queryParams = append(queryParams, v)
-		}
-		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
-	} else {
-		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
-	}
-	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+func (q *Queries) GetProjectsOfGenre(ctx context.Context, genreID int64) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, getProjectsOfGenre, genreID)
 	if err != nil {
 		return nil, err
 	}
@@ -262,28 +339,24 @@ func (q *Queries) GetProjectsOfGenrers(ctx context.Context, ids []int64) ([]Proj
 	return items, nil
 }
 
-const getProjectsOfGenresAndSearch = `-- name: GetProjectsOfGenresAndSearch :many
-
+const getProjectsOfGenrers = `-- name: GetProjectsOfGenrers :many
 
 SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords FROM projects AS p
-JOIN projects_genres AS pg 
-ON p.id = pg.project_id
-WHERE pg.genre_id IN (/*SLICE:ids*/?) 
-    AND ((LOWER(p.title) LIKE LOWER(?2)) 
-        OR (LOWER(p.description) LIKE LOWER(?2))
-        OR (LOWER(p.keywords) LIKE LOWER(?2)))
+WHERE EXISTS (
+    SELECT 1 FROM projects_genres AS pg
+    WHERE pg.project_id = p.id AND pg.genre_id IN (/*SLICE:ids*/?))
+ORDER BY p.id
+LIMIT ? OFFSET ?
 `
 
-type GetProjectsOfGenresAndSearchParams struct {
+type GetProjectsOfGenrersParams struct {
 	Ids    []int64
-	Search string
+	Limit  int64
+	Offset int64
 }
 
-// -- name: PragmaCaseSensitiveOFF :exec
-// PRAGMA case_sensitive_like = OFF;
-// --
-func (q *Queries) GetProjectsOfGenresAndSearch(ctx context.Context, arg GetProjectsOfGenresAndSearchParams) ([]Project, error) {
-	query := getProjectsOfGenresAndSearch
+func (q *Queries) GetProjectsOfGenrers(ctx context.Context, arg GetProjectsOfGenrersParams) ([]Project, error) {
+	query := getProjectsOfGenrers
 	var queryParams []interface{}
 	if len(arg.Ids) > 0 {
 		for _, v := range arg.Ids {
@@ -293,7 +366,8 @@ func (q *Queries) GetProjectsOfGenresAndSearch(ctx context.Context, arg GetProje
 	} else {
 		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
 	}
-	queryParams = append(queryParams, arg.Search)
+	queryParams = append(queryParams, arg.Limit)
+	queryParams = append(queryParams, arg.Offset)
 	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
@@ -371,16 +445,64 @@ func (q *Queries) GetProjectsOfType(ctx context.Context, typeID int64) ([]Projec
 	return items, nil
 }
 
-const getProjectsSearch = `-- name: GetProjectsSearch :many
+const getProjectsPage = `-- name: GetProjectsPage :many
 
-SELECT id, created_at, updated_at, title, description, type_id, duration_in_mins, release_year, director, producer, cover, keywords FROM projects
-WHERE ((LOWER(title) LIKE LOWER(?1)) 
-        OR (LOWER(description) LIKE LOWER(?1))
-        OR (LOWER(keywords) LIKE LOWER(?1)))
+SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords FROM projects AS p
+JOIN (
+    SELECT pr.id,
+        CASE CAST(?1 AS TEXT)
+            WHEN 'title' THEN pr.title
+            WHEN 'release_year' THEN pr.release_year
+            WHEN 'rating' THEN (SELECT COALESCE(AVG(r.stars), 0) FROM ratings AS r WHERE r.project_id = pr.id)
+            ELSE pr.created_at
+        END AS sort_key,
+        CAST(?2 AS BOOLEAN) AS descending
+    FROM projects AS pr
+) AS s
+ON s.id = p.id
+WHERE (p.type_id = ?3 OR ?3 IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_genres AS pg
+            WHERE pg.project_id = p.id AND pg.genre_id = ?4)
+        OR ?4 IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_age_categories AS pac
+            WHERE pac.project_id = p.id AND pac.age_category_id = ?5)
+        OR ?5 IS NULL)
+    AND (p.release_year >= ?6 OR ?6 IS NULL)
+    AND (p.release_year <= ?7 OR ?7 IS NULL)
+ORDER BY
+    CASE WHEN NOT s.descending THEN s.sort_key END ASC,
+    CASE WHEN s.descending THEN s.sort_key END DESC,
+    CASE WHEN NOT s.descending THEN p.id END ASC,
+    p.id DESC
+LIMIT ?9 OFFSET ?8
 `
 
-func (q *Queries) GetProjectsSearch(ctx context.Context, search string) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, getProjectsSearch, search)
+type GetProjectsPageParams struct {
+	SortBy        string
+	Descending    bool
+	TypeID        sql.NullInt64
+	GenreID       sql.NullInt64
+	AgeCategoryID sql.NullInt64
+	YearFrom      sql.NullInt64
+	YearTo        sql.NullInt64
+	Offset        int64
+	Limit         int64
+}
+
+func (q *Queries) GetProjectsPage(ctx context.Context, arg GetProjectsPageParams) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, getProjectsPage,
+		arg.SortBy,
+		arg.Descending,
+		arg.TypeID,
+		arg.GenreID,
+		arg.AgeCategoryID,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -442,8 +564,6 @@ SET updated_at = CURRENT_TIMESTAMP,
     type_id = ?,
     duration_in_mins = ?,
     release_year = ?,
-    director = ?,
-    producer = ?,
     keywords = ?
 WHERE id = ?
 `
@@ -454,8 +574,6 @@ type UpdateProjectParams struct {
 	TypeID         int64
 	DurationInMins int64
 	ReleaseYear    int64
-	Director       string
-	Producer       string
 	Keywords       string
 	ID             int64
 }
@@ -467,8 +585,6 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) er
 		arg.TypeID,
 		arg.DurationInMins,
 		arg.ReleaseYear,
-		arg.Director,
-		arg.Producer,
 		arg.Keywords,
 		arg.ID,
 	)
@@ -0,0 +1,353 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: ratings.sql
+
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+const countRatingsOfProject = `-- name: CountRatingsOfProject :one
+
+SELECT COUNT(*) FROM ratings
+WHERE project_id = ?1
+    AND (hidden = FALSE OR CAST(?2 AS BOOLEAN))
+`
+
+type CountRatingsOfProjectParams struct {
+	ProjectID  int64
+	WithHidden bool
+}
+
+func (q *Queries) CountRatingsOfProject(ctx context.Context, arg CountRatingsOfProjectParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRatingsOfProject, arg.ProjectID, arg.WithHidden)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRatingsOfUser = `-- name: CountRatingsOfUser :one
+
+SELECT COUNT(*) FROM ratings WHERE user_id = ?
+`
+
+func (q *Queries) CountRatingsOfUser(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRatingsOfUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteRatingOfUser = `-- name: DeleteRatingOfUser :exec
+
+DELETE FROM ratings WHERE user_id = ? AND project_id = ?
+`
+
+type DeleteRatingOfUserParams struct {
+	UserID    int64
+	ProjectID int64
+}
+
+func (q *Queries) DeleteRatingOfUser(ctx context.Context, arg DeleteRatingOfUserParams) error {
+	_, err := q.db.ExecContext(ctx, deleteRatingOfUser, arg.UserID, arg.ProjectID)
+	return err
+}
+
+const deleteRatingsOfProject = `-- name: DeleteRatingsOfProject :exec
+
+DELETE FROM ratings WHERE project_id = ?
+`
+
+func (q *Queries) DeleteRatingsOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRatingsOfProject, projectID)
+	return err
+}
+
+const getRatingById = `-- name: GetRatingById :one
+
+SELECT id, created_at, updated_at, user_id, project_id, stars, review, hidden FROM ratings WHERE id = ?
+`
+
+func (q *Queries) GetRatingById(ctx context.Context, id int64) (Rating, error) {
+	row := q.db.QueryRowContext(ctx, getRatingById, id)
+	var i Rating
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ProjectID,
+		&i.Stars,
+		&i.Review,
+		&i.Hidden,
+	)
+	return i, err
+}
+
+const getRatingOfUser = `-- name: GetRatingOfUser :one
+
+SELECT id, created_at, updated_at, user_id, project_id, stars, review, hidden FROM ratings WHERE user_id = ? AND project_id = ?
+`
+
+type GetRatingOfUserParams struct {
+	UserID    int64
+	ProjectID int64
+}
+
+func (q *Queries) GetRatingOfUser(ctx context.Context, arg GetRatingOfUserParams) (Rating, error) {
+	row := q.db.QueryRowContext(ctx, getRatingOfUser, arg.UserID, arg.ProjectID)
+	var i Rating
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ProjectID,
+		&i.Stars,
+		&i.Review,
+		&i.Hidden,
+	)
+	return i, err
+}
+
+const getRatingStatsOfProject = `-- name: GetRatingStatsOfProject :one
+
+SELECT CAST(COALESCE(AVG(stars), 0) AS REAL) AS average, COUNT(*) AS count
+FROM ratings
+WHERE project_id = ?
+`
+
+type GetRatingStatsOfProjectRow struct {
+	Average float64
+	Count   int64
+}
+
+func (q *Queries) GetRatingStatsOfProject(ctx context.Context, projectID int64) (GetRatingStatsOfProjectRow, error) {
+	row := q.db.QueryRowContext(ctx, getRatingStatsOfProject, projectID)
+	var i GetRatingStatsOfProjectRow
+	err := row.Scan(&i.Average, &i.Count)
+	return i, err
+}
+
+const getRatingStatsOfProjects = `-- name: GetRatingStatsOfProjects :many
+
+SELECT project_id, CAST(AVG(stars) AS REAL) AS average, COUNT(*) AS count
+FROM ratings
+WHERE project_id IN (/*SLICE:ids*/?)
+GROUP BY project_id
+`
+
+type GetRatingStatsOfProjectsRow struct {
+	ProjectID int64
+	Average   float64
+	Count     int64
+}
+
+func (q *Queries) GetRatingStatsOfProjects(ctx context.Context, ids []int64) ([]GetRatingStatsOfProjectsRow, error) {
+	query := getRatingStatsOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatingStatsOfProjectsRow
+	for rows.Next() {
+		var i GetRatingStatsOfProjectsRow
+		if err := rows.Scan(&i.ProjectID, &i.Average, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatingsOfProject = `-- name: GetRatingsOfProject :many
+
+SELECT r.id, r.created_at, r.updated_at, r.user_id, r.project_id, r.stars, r.review, r.hidden, u.name AS user_name FROM ratings AS r
+JOIN users AS u
+ON u.id = r.user_id
+WHERE r.project_id = ?1
+    AND (r.hidden = FALSE OR CAST(?2 AS BOOLEAN))
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT ?4 OFFSET ?3
+`
+
+type GetRatingsOfProjectParams struct {
+	ProjectID  int64
+	WithHidden bool
+	Offset     int64
+	Limit      int64
+}
+
+type GetRatingsOfProjectRow struct {
+	ID        int64
+	CreatedAt string
+	UpdatedAt string
+	UserID    int64
+	ProjectID int64
+	Stars     int64
+	Review    string
+	Hidden    bool
+	UserName  string
+}
+
+func (q *Queries) GetRatingsOfProject(ctx context.Context, arg GetRatingsOfProjectParams) ([]GetRatingsOfProjectRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRatingsOfProject,
+		arg.ProjectID,
+		arg.WithHidden,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatingsOfProjectRow
+	for rows.Next() {
+		var i GetRatingsOfProjectRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ProjectID,
+			&i.Stars,
+			&i.Review,
+			&i.Hidden,
+			&i.UserName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatingsOfUser = `-- name: GetRatingsOfUser :many
+
+SELECT r.id, r.created_at, r.updated_at, r.user_id, r.project_id, r.stars, r.review, r.hidden, u.name AS user_name FROM ratings AS r
+JOIN users AS u
+ON u.id = r.user_id
+WHERE r.user_id = ?
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetRatingsOfUserParams struct {
+	UserID int64
+	Limit  int64
+	Offset int64
+}
+
+type GetRatingsOfUserRow struct {
+	ID        int64
+	CreatedAt string
+	UpdatedAt string
+	UserID    int64
+	ProjectID int64
+	Stars     int64
+	Review    string
+	Hidden    bool
+	UserName  string
+}
+
+func (q *Queries) GetRatingsOfUser(ctx context.Context, arg GetRatingsOfUserParams) ([]GetRatingsOfUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRatingsOfUser, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatingsOfUserRow
+	for rows.Next() {
+		var i GetRatingsOfUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ProjectID,
+			&i.Stars,
+			&i.Review,
+			&i.Hidden,
+			&i.UserName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rateProject = `-- name: RateProject :exec
+INSERT INTO ratings(user_id, project_id, stars, review)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(user_id, project_id) DO UPDATE
+SET updated_at = CURRENT_TIMESTAMP,
+    stars = excluded.stars,
+    review = excluded.review
+`
+
+type RateProjectParams struct {
+	UserID    int64
+	ProjectID int64
+	Stars     int64
+	Review    string
+}
+
+func (q *Queries) RateProject(ctx context.Context, arg RateProjectParams) error {
+	_, err := q.db.ExecContext(ctx, rateProject,
+		arg.UserID,
+		arg.ProjectID,
+		arg.Stars,
+		arg.Review,
+	)
+	return err
+}
+
+const setRatingHidden = `-- name: SetRatingHidden :exec
+
+UPDATE ratings
+SET updated_at = CURRENT_TIMESTAMP,
+    hidden = ?
+WHERE id = ?
+`
+
+type SetRatingHiddenParams struct {
+	Hidden bool
+	ID     int64
+}
+
+func (q *Queries) SetRatingHidden(ctx context.Context, arg SetRatingHiddenParams) error {
+	_, err := q.db.ExecContext(ctx, setRatingHidden, arg.Hidden, arg.ID)
+	return err
+}
@@ -7,28 +7,65 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createRefreshToken = `-- name: CreateRefreshToken :exec
-INSERT INTO refresh_tokens(token, created_at, updated_at, user_id, expires_at, revoked_at)
+INSERT INTO refresh_tokens(token, created_at, updated_at, user_id, expires_at, revoked_at, family, user_agent, ip, signed_in_at)
 VALUES (
     ?, 
     CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, 
-    ?, NULL
+    ?, NULL, ?, ?, ?, ?
 )
 `
 
 type CreateRefreshTokenParams struct {
-	Token     string
-	UserID    int64
-	ExpiresAt string
+	Token      string
+	UserID     int64
+	ExpiresAt  string
+	Family     string
+	UserAgent  string
+	Ip         string
+	SignedInAt string
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
-	_, err := q.db.ExecContext(ctx, createRefreshToken, arg.Token, arg.UserID, arg.ExpiresAt)
+	_, err := q.db.ExecContext(ctx, createRefreshToken,
+		arg.Token,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.Family,
+		arg.UserAgent,
+		arg.Ip,
+		arg.SignedInAt,
+	)
 	return err
 }
 
+const getRefreshToken = `-- name: GetRefreshToken :one
+
+SELECT token, created_at, updated_at, user_id, expires_at, revoked_at, family, replaced_by, user_agent, ip, signed_in_at FROM refresh_tokens WHERE token = ?
+`
+
+func (q *Queries) GetRefreshToken(ctx context.Context, token string) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshToken, token)
+	var i RefreshToken
+	err := row.Scan(
+		&i.Token,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.Family,
+		&i.ReplacedBy,
+		&i.UserAgent,
+		&i.Ip,
+		&i.SignedInAt,
+	)
+	return i, err
+}
+
 const getRefreshTokenOfUser = `-- name: GetRefreshTokenOfUser :one
 
 SELECT token FROM refresh_tokens
@@ -45,9 +82,62 @@ func (q *Queries) GetRefreshTokenOfUser(ctx context.Context, userID int64) (stri
 	return token, err
 }
 
+const getSessionsOfUser = `-- name: GetSessionsOfUser :many
+
+SELECT family, user_agent, ip, signed_in_at, created_at, expires_at FROM refresh_tokens
+WHERE user_id = ?
+    AND revoked_at IS NULL
+    AND expires_at > ?2
+ORDER BY signed_in_at DESC, family
+`
+
+type GetSessionsOfUserParams struct {
+	UserID int64
+	Now    string
+}
+
+type GetSessionsOfUserRow struct {
+	Family     string
+	UserAgent  string
+	Ip         string
+	SignedInAt string
+	CreatedAt  string
+	ExpiresAt  string
+}
+
+func (q *Queries) GetSessionsOfUser(ctx context.Context, arg GetSessionsOfUserParams) ([]GetSessionsOfUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsOfUser, arg.UserID, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSessionsOfUserRow
+	for rows.Next() {
+		var i GetSessionsOfUserRow
+		if err := rows.Scan(
+			&i.Family,
+			&i.UserAgent,
+			&i.Ip,
+			&i.SignedInAt,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserFromRefreshToken = `-- name: GetUserFromRefreshToken :one
 
-SELECT users.id, users.created_at, users.updated_at, users.name, users.email, users.password_hash, users.date_of_birth, users.phone FROM users
+SELECT users.id, users.created_at, users.updated_at, users.name, users.email, users.password_hash, users.date_of_birth, users.phone, users.email_verified_at, users.totp_secret, users.totp_enabled_at, users.totp_counter FROM users
 JOIN refresh_tokens ON users.id = refresh_tokens.user_id
 WHERE refresh_tokens.token = ?
     AND revoked_at IS NULL
@@ -67,10 +157,95 @@ func (q *Queries) GetUserFromRefreshToken(ctx context.Context, token string) (Us
 		&i.PasswordHash,
 		&i.DateOfBirth,
 		&i.Phone,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpCounter,
 	)
 	return i, err
 }
 
+const isSessionActive = `-- name: IsSessionActive :one
+
+SELECT COUNT(*) > 0 FROM refresh_tokens
+WHERE family = ?
+    AND revoked_at IS NULL
+    AND expires_at > ?2
+`
+
+type IsSessionActiveParams struct {
+	Family string
+	Now    string
+}
+
+func (q *Queries) IsSessionActive(ctx context.Context, arg IsSessionActiveParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isSessionActive, arg.Family, arg.Now)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const revokeOtherSessionsOfUser = `-- name: RevokeOtherSessionsOfUser :exec
+
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = ? AND family != ?2 AND revoked_at IS NULL
+`
+
+type RevokeOtherSessionsOfUserParams struct {
+	UserID  int64
+	Current string
+}
+
+func (q *Queries) RevokeOtherSessionsOfUser(ctx context.Context, arg RevokeOtherSessionsOfUserParams) error {
+	_, err := q.db.ExecContext(ctx, revokeOtherSessionsOfUser, arg.UserID, arg.Current)
+	return err
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE family = ? AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, family string) error {
+	_, err := q.db.ExecContext(ctx, revokeRefreshTokenFamily, family)
+	return err
+}
+
+const revokeSessionOfUser = `-- name: RevokeSessionOfUser :execrows
+
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE family = ? AND user_id = ? AND revoked_at IS NULL
+`
+
+type RevokeSessionOfUserParams struct {
+	Family string
+	UserID int64
+}
+
+func (q *Queries) RevokeSessionOfUser(ctx context.Context, arg RevokeSessionOfUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeSessionOfUser, arg.Family, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeSessionsOfUser = `-- name: RevokeSessionsOfUser :exec
+
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = ? AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeSessionsOfUser(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, revokeSessionsOfUser, userID)
+	return err
+}
+
 const revokeToken = `-- name: RevokeToken :exec
 
 UPDATE refresh_tokens
@@ -82,3 +257,25 @@ func (q *Queries) RevokeToken(ctx context.Context, token string) error {
 	_, err := q.db.ExecContext(ctx, revokeToken, token)
 	return err
 }
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :execrows
+
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP,
+    revoked_at = CURRENT_TIMESTAMP,
+    replaced_by = ?
+WHERE token = ? AND revoked_at IS NULL
+`
+
+type RotateRefreshTokenParams struct {
+	ReplacedBy sql.NullString
+	Token      string
+}
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rotateRefreshToken, arg.ReplacedBy, arg.Token)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
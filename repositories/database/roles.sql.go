@@ -11,19 +11,20 @@ import (
 
 const createRole = `-- name: CreateRole :one
 
-INSERT INTO roles(title, projects, genres, age_categories, types, users, roles)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO roles(title, projects, genres, age_categories, types, users, roles, require_two_factor)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id
 `
 
 type CreateRoleParams struct {
-	Title         string
-	Projects      int64
-	Genres        int64
-	AgeCategories int64
-	Types         int64
-	Users         int64
-	Roles         int64
+	Title            string
+	Projects         int64
+	Genres           int64
+	AgeCategories    int64
+	Types            int64
+	Users            int64
+	Roles            int64
+	RequireTwoFactor bool
 }
 
 func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) (int64, error) {
@@ -35,6 +36,7 @@ func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) (int64,
 		arg.Types,
 		arg.Users,
 		arg.Roles,
+		arg.RequireTwoFactor,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -53,7 +55,7 @@ func (q *Queries) DeleteRole(ctx context.Context, id int64) error {
 
 const getRoleById = `-- name: GetRoleById :one
 
-SELECT id, title, projects, genres, age_categories, types, users, roles FROM roles WHERE id = ?
+SELECT id, title, projects, genres, age_categories, types, users, roles, require_two_factor FROM roles WHERE id = ?
 `
 
 func (q *Queries) GetRoleById(ctx context.Context, id int64) (Role, error) {
@@ -68,12 +70,13 @@ func (q *Queries) GetRoleById(ctx context.Context, id int64) (Role, error) {
 		&i.Types,
 		&i.Users,
 		&i.Roles,
+		&i.RequireTwoFactor,
 	)
 	return i, err
 }
 
 const getRoles = `-- name: GetRoles :many
-SELECT id, title, projects, genres, age_categories, types, users, roles FROM roles
+SELECT id, title, projects, genres, age_categories, types, users, roles, require_two_factor FROM roles
 `
 
 func (q *Queries) GetRoles(ctx context.Context) ([]Role, error) {
@@ -94,6 +97,7 @@ func (q *Queries) GetRoles(ctx context.Context) ([]Role, error) {
 			&i.Types,
 			&i.Users,
 			&i.Roles,
+			&i.RequireTwoFactor,
 		); err != nil {
 			return nil, err
 		}
@@ -110,7 +114,7 @@ func (q *Queries) GetRoles(ctx context.Context) ([]Role, error) {
 
 const getRolesOfUser = `-- name: GetRolesOfUser :many
 
-SELECT r.id, r.title, r.projects, r.genres, r.age_categories, r.types, r.users, r.roles
+SELECT r.id, r.title, r.projects, r.genres, r.age_categories, r.types, r.users, r.roles, r.require_two_factor
 FROM roles AS r
 JOIN users_roles AS ur
 ON r.id = ur.role_id
@@ -135,6 +139,7 @@ func (q *Queries) GetRolesOfUser(ctx context.Context, userID int64) ([]Role, err
 			&i.Types,
 			&i.Users,
 			&i.Roles,
+			&i.RequireTwoFactor,
 		); err != nil {
 			return nil, err
 		}
@@ -158,19 +163,21 @@ SET title = ?,
     age_categories = ?,
     types = ?,
     users = ?,
-    roles = ?
+    roles = ?,
+    require_two_factor = ?
 WHERE id = ?
 `
 
 type UpdateRoleParams struct {
-	Title         string
-	Projects      int64
-	Genres        int64
-	AgeCategories int64
-	Types         int64
-	Users         int64
-	Roles         int64
-	ID            int64
+	Title            string
+	Projects         int64
+	Genres           int64
+	AgeCategories    int64
+	Types            int64
+	Users            int64
+	Roles            int64
+	RequireTwoFactor bool
+	ID               int64
 }
 
 func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) error {
@@ -182,6 +189,7 @@ func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) error {
 		arg.Types,
 		arg.Users,
 		arg.Roles,
+		arg.RequireTwoFactor,
 		arg.ID,
 	)
 	return err
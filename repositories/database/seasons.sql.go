@@ -0,0 +1,197 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: seasons.sql
+
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+const createSeason = `-- name: CreateSeason :one
+INSERT INTO seasons(project_id, number, title, synopsis)
+VALUES (?, ?, ?, ?)
+RETURNING id
+`
+
+type CreateSeasonParams struct {
+	ProjectID int64
+	Number    int64
+	Title     string
+	Synopsis  string
+}
+
+func (q *Queries) CreateSeason(ctx context.Context, arg CreateSeasonParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createSeason,
+		arg.ProjectID,
+		arg.Number,
+		arg.Title,
+		arg.Synopsis,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteSeason = `-- name: DeleteSeason :exec
+
+DELETE FROM seasons WHERE id = ?
+`
+
+func (q *Queries) DeleteSeason(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSeason, id)
+	return err
+}
+
+const deleteSeasonsOfProject = `-- name: DeleteSeasonsOfProject :exec
+
+DELETE FROM seasons WHERE project_id = ?
+`
+
+func (q *Queries) DeleteSeasonsOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSeasonsOfProject, projectID)
+	return err
+}
+
+const ensureSeason = `-- name: EnsureSeason :one
+
+INSERT INTO seasons(project_id, number)
+VALUES (?, ?)
+ON CONFLICT(project_id, number) DO UPDATE
+SET number = excluded.number
+RETURNING id
+`
+
+type EnsureSeasonParams struct {
+	ProjectID int64
+	Number    int64
+}
+
+func (q *Queries) EnsureSeason(ctx context.Context, arg EnsureSeasonParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, ensureSeason, arg.ProjectID, arg.Number)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getSeasonById = `-- name: GetSeasonById :one
+
+SELECT id, created_at, updated_at, project_id, number, title, synopsis FROM seasons WHERE id = ?
+`
+
+func (q *Queries) GetSeasonById(ctx context.Context, id int64) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getSeasonById, id)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectID,
+		&i.Number,
+		&i.Title,
+		&i.Synopsis,
+	)
+	return i, err
+}
+
+const getSeasonByNumber = `-- name: GetSeasonByNumber :one
+
+SELECT id, created_at, updated_at, project_id, number, title, synopsis FROM seasons WHERE project_id = ? AND number = ?
+`
+
+type GetSeasonByNumberParams struct {
+	ProjectID int64
+	Number    int64
+}
+
+func (q *Queries) GetSeasonByNumber(ctx context.Context, arg GetSeasonByNumberParams) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getSeasonByNumber, arg.ProjectID, arg.Number)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectID,
+		&i.Number,
+		&i.Title,
+		&i.Synopsis,
+	)
+	return i, err
+}
+
+const getSeasonsOfProjects = `-- name: GetSeasonsOfProjects :many
+
+SELECT id, created_at, updated_at, project_id, number, title, synopsis FROM seasons
+WHERE project_id IN (/*SLICE:ids*/?)
+ORDER BY project_id, number
+`
+
+func (q *Queries) GetSeasonsOfProjects(ctx context.Context, ids []int64) ([]Season, error) {
+	query := getSeasonsOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Season
+	for rows.Next() {
+		var i Season
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProjectID,
+			&i.Number,
+			&i.Title,
+			&i.Synopsis,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateSeason = `-- name: UpdateSeason :exec
+
+UPDATE seasons
+SET updated_at = CURRENT_TIMESTAMP,
+    number = ?,
+    title = ?,
+    synopsis = ?
+WHERE id = ?
+`
+
+type UpdateSeasonParams struct {
+	Number   int64
+	Title    string
+	Synopsis string
+	ID       int64
+}
+
+func (q *Queries) UpdateSeason(ctx context.Context, arg UpdateSeasonParams) error {
+	_, err := q.db.ExecContext(ctx, updateSeason,
+		arg.Number,
+		arg.Title,
+		arg.Synopsis,
+		arg.ID,
+	)
+	return err
+}
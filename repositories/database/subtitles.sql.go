@@ -0,0 +1,276 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: subtitles.sql
+
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+const addSubtitle = `-- name: AddSubtitle :one
+INSERT INTO subtitles(video_id, language, label, is_default, file)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type AddSubtitleParams struct {
+	VideoID   string
+	Language  string
+	Label     string
+	IsDefault bool
+	File      string
+}
+
+func (q *Queries) AddSubtitle(ctx context.Context, arg AddSubtitleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, addSubtitle,
+		arg.VideoID,
+		arg.Language,
+		arg.Label,
+		arg.IsDefault,
+		arg.File,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteSubtitle = `-- name: DeleteSubtitle :exec
+
+DELETE FROM subtitles WHERE id = ?
+`
+
+func (q *Queries) DeleteSubtitle(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSubtitle, id)
+	return err
+}
+
+const deleteSubtitlesOfProject = `-- name: DeleteSubtitlesOfProject :exec
+
+DELETE FROM subtitles
+WHERE video_id IN (SELECT id FROM videos WHERE project_id = ?)
+`
+
+func (q *Queries) DeleteSubtitlesOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSubtitlesOfProject, projectID)
+	return err
+}
+
+const deleteSubtitlesOfVideo = `-- name: DeleteSubtitlesOfVideo :exec
+
+DELETE FROM subtitles WHERE video_id = ?
+`
+
+func (q *Queries) DeleteSubtitlesOfVideo(ctx context.Context, videoID string) error {
+	_, err := q.db.ExecContext(ctx, deleteSubtitlesOfVideo, videoID)
+	return err
+}
+
+const getSubtitleById = `-- name: GetSubtitleById :one
+
+SELECT id, created_at, updated_at, video_id, language, label, is_default, file FROM subtitles WHERE id = ?
+`
+
+func (q *Queries) GetSubtitleById(ctx context.Context, id int64) (Subtitle, error) {
+	row := q.db.QueryRowContext(ctx, getSubtitleById, id)
+	var i Subtitle
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VideoID,
+		&i.Language,
+		&i.Label,
+		&i.IsDefault,
+		&i.File,
+	)
+	return i, err
+}
+
+const getSubtitles = `-- name: GetSubtitles :many
+
+SELECT id, created_at, updated_at, video_id, language, label, is_default, file FROM subtitles
+ORDER BY id
+`
+
+func (q *Queries) GetSubtitles(ctx context.Context) ([]Subtitle, error) {
+	rows, err := q.db.QueryContext(ctx, getSubtitles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subtitle
+	for rows.Next() {
+		var i Subtitle
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VideoID,
+			&i.Language,
+			&i.Label,
+			&i.IsDefault,
+			&i.File,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubtitlesOfProjects = `-- name: GetSubtitlesOfProjects :many
+
+SELECT s.id, s.created_at, s.updated_at, s.video_id, s.language, s.label, s.is_default, s.file, v.project_id FROM subtitles AS s
+JOIN videos AS v
+ON v.id = s.video_id
+WHERE v.project_id IN (/*SLICE:ids*/?)
+ORDER BY v.project_id, v.season, v.serie, s.is_default DESC, s.language, s.label, s.id
+`
+
+type GetSubtitlesOfProjectsRow struct {
+	ID        int64
+	CreatedAt string
+	UpdatedAt string
+	VideoID   string
+	Language  string
+	Label     string
+	IsDefault bool
+	File      string
+	ProjectID int64
+}
+
+func (q *Queries) GetSubtitlesOfProjects(ctx context.Context, ids []int64) ([]GetSubtitlesOfProjectsRow, error) {
+	query := getSubtitlesOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSubtitlesOfProjectsRow
+	for rows.Next() {
+		var i GetSubtitlesOfProjectsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VideoID,
+			&i.Language,
+			&i.Label,
+			&i.IsDefault,
+			&i.File,
+			&i.ProjectID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubtitlesOfVideo = `-- name: GetSubtitlesOfVideo :many
+
+SELECT id, created_at, updated_at, video_id, language, label, is_default, file FROM subtitles
+WHERE video_id = ?
+ORDER BY is_default DESC, language, label, id
+`
+
+func (q *Queries) GetSubtitlesOfVideo(ctx context.Context, videoID string) ([]Subtitle, error) {
+	rows, err := q.db.QueryContext(ctx, getSubtitlesOfVideo, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subtitle
+	for rows.Next() {
+		var i Subtitle
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VideoID,
+			&i.Language,
+			&i.Label,
+			&i.IsDefault,
+			&i.File,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setDefaultSubtitle = `-- name: SetDefaultSubtitle :exec
+
+UPDATE subtitles
+SET updated_at = CURRENT_TIMESTAMP,
+    is_default = (id = ?1)
+WHERE video_id = ?2
+`
+
+type SetDefaultSubtitleParams struct {
+	ID      int64
+	VideoID string
+}
+
+func (q *Queries) SetDefaultSubtitle(ctx context.Context, arg SetDefaultSubtitleParams) error {
+	_, err := q.db.ExecContext(ctx, setDefaultSubtitle, arg.ID, arg.VideoID)
+	return err
+}
+
+const updateSubtitle = `-- name: UpdateSubtitle :exec
+
+UPDATE subtitles
+SET updated_at = CURRENT_TIMESTAMP,
+    language = ?,
+    label = ?,
+    is_default = ?
+WHERE id = ?
+`
+
+type UpdateSubtitleParams struct {
+	Language  string
+	Label     string
+	IsDefault bool
+	ID        int64
+}
+
+func (q *Queries) UpdateSubtitle(ctx context.Context, arg UpdateSubtitleParams) error {
+	_, err := q.db.ExecContext(ctx, updateSubtitle,
+		arg.Language,
+		arg.Label,
+		arg.IsDefault,
+		arg.ID,
+	)
+	return err
+}
@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: two_factor.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+const addRecoveryCode = `-- name: AddRecoveryCode :exec
+
+INSERT INTO recovery_codes(code_hash, user_id)
+VALUES (?, ?)
+`
+
+type AddRecoveryCodeParams struct {
+	CodeHash string
+	UserID   int64
+}
+
+func (q *Queries) AddRecoveryCode(ctx context.Context, arg AddRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, addRecoveryCode, arg.CodeHash, arg.UserID)
+	return err
+}
+
+const deleteRecoveryCodesOfUser = `-- name: DeleteRecoveryCodesOfUser :exec
+
+DELETE FROM recovery_codes WHERE user_id = ?
+`
+
+func (q *Queries) DeleteRecoveryCodesOfUser(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRecoveryCodesOfUser, userID)
+	return err
+}
+
+const disableTotp = `-- name: DisableTotp :exec
+
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    totp_secret = NULL,
+    totp_enabled_at = NULL,
+    totp_counter = 0
+WHERE id = ?
+`
+
+func (q *Queries) DisableTotp(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, disableTotp, id)
+	return err
+}
+
+const enableTotp = `-- name: EnableTotp :execrows
+
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    totp_enabled_at = CURRENT_TIMESTAMP,
+    totp_counter = ?
+WHERE id = ? AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL
+`
+
+type EnableTotpParams struct {
+	TotpCounter int64
+	ID          int64
+}
+
+func (q *Queries) EnableTotp(ctx context.Context, arg EnableTotpParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enableTotp, arg.TotpCounter, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setTotpSecret = `-- name: SetTotpSecret :execrows
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    totp_secret = ?,
+    totp_counter = 0
+WHERE id = ? AND totp_enabled_at IS NULL
+`
+
+type SetTotpSecretParams struct {
+	TotpSecret sql.NullString
+	ID         int64
+}
+
+func (q *Queries) SetTotpSecret(ctx context.Context, arg SetTotpSecretParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setTotpSecret, arg.TotpSecret, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+
+UPDATE recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE code_hash = ? AND user_id = ? AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	CodeHash string
+	UserID   int64
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useRecoveryCode, arg.CodeHash, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const useTotpCounter = `-- name: UseTotpCounter :execrows
+
+UPDATE users
+SET totp_counter = ?1
+WHERE id = ?2 AND totp_counter < ?1
+`
+
+type UseTotpCounterParams struct {
+	Counter int64
+	ID      int64
+}
+
+func (q *Queries) UseTotpCounter(ctx context.Context, arg UseTotpCounterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useTotpCounter, arg.Counter, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
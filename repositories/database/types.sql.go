@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"strings"
 )
 
 const createType = `-- name: CreateType :one
@@ -72,6 +73,44 @@ func (q *Queries) GetTypes(ctx context.Context) ([]Type, error) {
 	return items, nil
 }
 
+const getTypesByIds = `-- name: GetTypesByIds :many
+
+SELECT id, title FROM types WHERE id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) GetTypesByIds(ctx context.Context, ids []int64) ([]Type, error) {
+	query := getTypesByIds
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Type
+	for rows.Next() {
+		var i Type
+		if err := rows.Scan(&i.ID, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateType = `-- name: UpdateType :exec
 
 UPDATE types 
@@ -0,0 +1,184 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: uploads.sql
+
+package database
+
+import (
+	"context"
+)
+
+const appendUploadPart = `-- name: AppendUploadPart :execrows
+
+UPDATE uploads
+SET received = received + ?1, parts = parts || ?2 || char(10)
+WHERE id = ?3 AND received = ?4 AND media_id = ''
+`
+
+type AppendUploadPartParams struct {
+	Size   int64
+	Part   string
+	ID     string
+	Offset int64
+}
+
+func (q *Queries) AppendUploadPart(ctx context.Context, arg AppendUploadPartParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, appendUploadPart,
+		arg.Size,
+		arg.Part,
+		arg.ID,
+		arg.Offset,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const completeUpload = `-- name: CompleteUpload :exec
+
+UPDATE uploads
+SET media_id = ?
+WHERE id = ?
+`
+
+type CompleteUploadParams struct {
+	MediaID string
+	ID      string
+}
+
+func (q *Queries) CompleteUpload(ctx context.Context, arg CompleteUploadParams) error {
+	_, err := q.db.ExecContext(ctx, completeUpload, arg.MediaID, arg.ID)
+	return err
+}
+
+const createUpload = `-- name: CreateUpload :exec
+INSERT INTO uploads(id, expires_at, user_id, length, metadata)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateUploadParams struct {
+	ID        string
+	ExpiresAt string
+	UserID    int64
+	Length    int64
+	Metadata  string
+}
+
+func (q *Queries) CreateUpload(ctx context.Context, arg CreateUploadParams) error {
+	_, err := q.db.ExecContext(ctx, createUpload,
+		arg.ID,
+		arg.ExpiresAt,
+		arg.UserID,
+		arg.Length,
+		arg.Metadata,
+	)
+	return err
+}
+
+const deleteExpiredUploads = `-- name: DeleteExpiredUploads :exec
+
+DELETE FROM uploads
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) DeleteExpiredUploads(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredUploads)
+	return err
+}
+
+const deleteUpload = `-- name: DeleteUpload :exec
+
+DELETE FROM uploads WHERE id = ?
+`
+
+func (q *Queries) DeleteUpload(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteUpload, id)
+	return err
+}
+
+const getExpiredUploads = `-- name: GetExpiredUploads :many
+
+SELECT id FROM uploads
+WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) GetExpiredUploads(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredUploads)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUploadIds = `-- name: GetUploadIds :many
+
+SELECT id FROM uploads
+`
+
+func (q *Queries) GetUploadIds(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getUploadIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUploadOfUser = `-- name: GetUploadOfUser :one
+
+SELECT id, created_at, expires_at, user_id, length, received, metadata, media_id, parts FROM uploads
+WHERE id = ? AND user_id = ? AND expires_at > CURRENT_TIMESTAMP
+`
+
+type GetUploadOfUserParams struct {
+	ID     string
+	UserID int64
+}
+
+func (q *Queries) GetUploadOfUser(ctx context.Context, arg GetUploadOfUserParams) (Upload, error) {
+	row := q.db.QueryRowContext(ctx, getUploadOfUser, arg.ID, arg.UserID)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.UserID,
+		&i.Length,
+		&i.Received,
+		&i.Metadata,
+		&i.MediaID,
+		&i.Parts,
+	)
+	return i, err
+}
@@ -57,7 +57,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
 
 const getUserByEmail = `-- name: GetUserByEmail :one
 
-SELECT id, created_at, updated_at, name, email, password_hash, date_of_birth, phone FROM users WHERE email = ?
+SELECT id, created_at, updated_at, name, email, password_hash, date_of_birth, phone, email_verified_at, totp_secret, totp_enabled_at, totp_counter FROM users WHERE email = ?
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -72,13 +72,17 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordHash,
 		&i.DateOfBirth,
 		&i.Phone,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpCounter,
 	)
 	return i, err
 }
 
 const getUserById = `-- name: GetUserById :one
 
-SELECT id, created_at, updated_at, name, email, password_hash, date_of_birth, phone FROM users WHERE id = ?
+SELECT id, created_at, updated_at, name, email, password_hash, date_of_birth, phone, email_verified_at, totp_secret, totp_enabled_at, totp_counter FROM users WHERE id = ?
 `
 
 func (q *Queries) GetUserById(ctx context.Context, id int64) (User, error) {
@@ -93,13 +97,46 @@ func (q *Queries) GetUserById(ctx context.Context, id int64) (User, error) {
 		&i.PasswordHash,
 		&i.DateOfBirth,
 		&i.Phone,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpCounter,
+	)
+	return i, err
+}
+
+const getUserBySignInEmail = `-- name: GetUserBySignInEmail :one
+
+SELECT id, created_at, updated_at, name, email, password_hash, date_of_birth, phone, email_verified_at, totp_secret, totp_enabled_at, totp_counter FROM users WHERE lower(email) = ?
+ORDER BY id
+LIMIT 1
+`
+
+// the email is trimmed and lowercased as the key of the sign in attempts,
+// of the accounts differing only in case the oldest one signs in
+func (q *Queries) GetUserBySignInEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserBySignInEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Email,
+		&i.PasswordHash,
+		&i.DateOfBirth,
+		&i.Phone,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpCounter,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
 
-SELECT id, created_at, updated_at, name, email, password_hash, date_of_birth, phone FROM users
+SELECT id, created_at, updated_at, name, email, password_hash, date_of_birth, phone, email_verified_at, totp_secret, totp_enabled_at, totp_counter FROM users
 `
 
 func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
@@ -120,6 +157,10 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.PasswordHash,
 			&i.DateOfBirth,
 			&i.Phone,
+			&i.EmailVerifiedAt,
+			&i.TotpSecret,
+			&i.TotpEnabledAt,
+			&i.TotpCounter,
 		); err != nil {
 			return nil, err
 		}
@@ -136,7 +177,7 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 
 const getUsersOfRole = `-- name: GetUsersOfRole :many
 
-SELECT u.id, u.created_at, u.updated_at, u.name, u.email, u.password_hash, u.date_of_birth, u.phone
+SELECT u.id, u.created_at, u.updated_at, u.name, u.email, u.password_hash, u.date_of_birth, u.phone, u.email_verified_at, u.totp_secret, u.totp_enabled_at, u.totp_counter
 FROM users AS u
 JOIN users_roles AS ur
 ON u.id = ur.user_id
@@ -161,6 +202,10 @@ func (q *Queries) GetUsersOfRole(ctx context.Context, roleID int64) ([]User, err
 			&i.PasswordHash,
 			&i.DateOfBirth,
 			&i.Phone,
+			&i.EmailVerifiedAt,
+			&i.TotpSecret,
+			&i.TotpEnabledAt,
+			&i.TotpCounter,
 		); err != nil {
 			return nil, err
 		}
@@ -179,16 +224,18 @@ const updateUser = `-- name: UpdateUser :exec
 
 UPDATE users
 SET updated_at = CURRENT_TIMESTAMP,
-    name = ?,
-    email = ?,
-    date_of_birth = ?,
-    phone = ?
-WHERE id = ?
+    -- a new email has to be verified again
+    email_verified_at = CASE WHEN email = ?1 THEN email_verified_at END,
+    name = ?2,
+    email = ?1,
+    date_of_birth = ?3,
+    phone = ?4
+WHERE id = ?5
 `
 
 type UpdateUserParams struct {
-	Name        string
 	Email       string
+	Name        string
 	DateOfBirth string
 	Phone       string
 	ID          int64
@@ -196,11 +243,32 @@ type UpdateUserParams struct {
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
 	_, err := q.db.ExecContext(ctx, updateUser,
-		arg.Name,
 		arg.Email,
+		arg.Name,
 		arg.DateOfBirth,
 		arg.Phone,
 		arg.ID,
 	)
 	return err
 }
+
+const verifyEmail = `-- name: VerifyEmail :execrows
+
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    email_verified_at = CURRENT_TIMESTAMP
+WHERE id = ? AND email = ? AND email_verified_at IS NULL
+`
+
+type VerifyEmailParams struct {
+	ID    int64
+	Email string
+}
+
+func (q *Queries) VerifyEmail(ctx context.Context, arg VerifyEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, verifyEmail, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"strings"
 )
 
 const addVideo2Movie = `-- name: AddVideo2Movie :exec
@@ -27,16 +28,19 @@ func (q *Queries) AddVideo2Movie(ctx context.Context, arg AddVideo2MovieParams)
 
 const addVideo2Series = `-- name: AddVideo2Series :exec
 
-INSERT INTO videos(id, project_id, season, serie, href)
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO videos(id, project_id, season, serie, href, duration_in_secs, width, height)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type AddVideo2SeriesParams struct {
-	ID        string
-	ProjectID int64
-	Season    int64
-	Serie     int64
-	Href      string
+	ID             string
+	ProjectID      int64
+	Season         int64
+	Serie          int64
+	Href           string
+	DurationInSecs int64
+	Width          int64
+	Height         int64
 }
 
 func (q *Queries) AddVideo2Series(ctx context.Context, arg AddVideo2SeriesParams) error {
@@ -46,6 +50,9 @@ func (q *Queries) AddVideo2Series(ctx context.Context, arg AddVideo2SeriesParams
 		arg.Season,
 		arg.Serie,
 		arg.Href,
+		arg.DurationInSecs,
+		arg.Width,
+		arg.Height,
 	)
 	return err
 }
@@ -60,9 +67,47 @@ func (q *Queries) DeleteVideo(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteVideosOfProject = `-- name: DeleteVideosOfProject :exec
+
+DELETE FROM videos WHERE project_id = ?
+`
+
+func (q *Queries) DeleteVideosOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteVideosOfProject, projectID)
+	return err
+}
+
+const getNextPendingVideo = `-- name: GetNextPendingVideo :one
+
+SELECT id, created_at, updated_at, project_id, season, serie, href, hls_status, hls_error, duration_in_secs, width, height FROM videos
+WHERE hls_status = 'pending'
+ORDER BY created_at, id
+LIMIT 1
+`
+
+func (q *Queries) GetNextPendingVideo(ctx context.Context) (Video, error) {
+	row := q.db.QueryRowContext(ctx, getNextPendingVideo)
+	var i Video
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectID,
+		&i.Season,
+		&i.Serie,
+		&i.Href,
+		&i.HlsStatus,
+		&i.HlsError,
+		&i.DurationInSecs,
+		&i.Width,
+		&i.Height,
+	)
+	return i, err
+}
+
 const getVideo = `-- name: GetVideo :one
 
-SELECT id, created_at, updated_at, project_id, season, serie, href FROM videos
+SELECT id, created_at, updated_at, project_id, season, serie, href, hls_status, hls_error, duration_in_secs, width, height FROM videos
 WHERE project_id = ? AND
     season = ? AND
     serie = ?
@@ -85,13 +130,44 @@ func (q *Queries) GetVideo(ctx context.Context, arg GetVideoParams) (Video, erro
 		&i.Season,
 		&i.Serie,
 		&i.Href,
+		&i.HlsStatus,
+		&i.HlsError,
+		&i.DurationInSecs,
+		&i.Width,
+		&i.Height,
+	)
+	return i, err
+}
+
+const getVideoById = `-- name: GetVideoById :one
+
+SELECT id, created_at, updated_at, project_id, season, serie, href, hls_status, hls_error, duration_in_secs, width, height FROM videos
+WHERE id = ?
+`
+
+func (q *Queries) GetVideoById(ctx context.Context, id string) (Video, error) {
+	row := q.db.QueryRowContext(ctx, getVideoById, id)
+	var i Video
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectID,
+		&i.Season,
+		&i.Serie,
+		&i.Href,
+		&i.HlsStatus,
+		&i.HlsError,
+		&i.DurationInSecs,
+		&i.Width,
+		&i.Height,
 	)
 	return i, err
 }
 
 const getVideos = `-- name: GetVideos :many
 
-SELECT id, created_at, updated_at, project_id, season, serie, href FROM videos
+SELECT id, created_at, updated_at, project_id, season, serie, href, hls_status, hls_error, duration_in_secs, width, height FROM videos
 `
 
 func (q *Queries) GetVideos(ctx context.Context) ([]Video, error) {
@@ -111,6 +187,11 @@ func (q *Queries) GetVideos(ctx context.Context) ([]Video, error) {
 			&i.Season,
 			&i.Serie,
 			&i.Href,
+			&i.HlsStatus,
+			&i.HlsError,
+			&i.DurationInSecs,
+			&i.Width,
+			&i.Height,
 		); err != nil {
 			return nil, err
 		}
@@ -127,8 +208,9 @@ func (q *Queries) GetVideos(ctx context.Context) ([]Video, error) {
 
 const getVideosOfProject = `-- name: GetVideosOfProject :many
 
-SELECT id, created_at, updated_at, project_id, season, serie, href FROM videos
+SELECT id, created_at, updated_at, project_id, season, serie, href, hls_status, hls_error, duration_in_secs, width, height FROM videos
 WHERE project_id = ?
+ORDER BY season, serie
 `
 
 func (q *Queries) GetVideosOfProject(ctx context.Context, projectID int64) ([]Video, error) {
@@ -148,6 +230,64 @@ func (q *Queries) GetVideosOfProject(ctx context.Context, projectID int64) ([]Vi
 			&i.Season,
 			&i.Serie,
 			&i.Href,
+			&i.HlsStatus,
+			&i.HlsError,
+			&i.DurationInSecs,
+			&i.Width,
+			&i.Height,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideosOfProjects = `-- name: GetVideosOfProjects :many
+
+SELECT id, created_at, updated_at, project_id, season, serie, href, hls_status, hls_error, duration_in_secs, width, height FROM videos
+WHERE project_id IN (/*SLICE:ids*/?)
+ORDER BY project_id, season, serie
+`
+
+func (q *Queries) GetVideosOfProjects(ctx context.Context, ids []int64) ([]Video, error) {
+	query := getVideosOfProjects
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProjectID,
+			&i.Season,
+			&i.Serie,
+			&i.Href,
+			&i.HlsStatus,
+			&i.HlsError,
+			&i.DurationInSecs,
+			&i.Width,
+			&i.Height,
 		); err != nil {
 			return nil, err
 		}
@@ -162,6 +302,81 @@ func (q *Queries) GetVideosOfProject(ctx context.Context, projectID int64) ([]Vi
 	return items, nil
 }
 
+const renumberSeasonOfVideos = `-- name: RenumberSeasonOfVideos :exec
+
+UPDATE videos
+SET updated_at = CURRENT_TIMESTAMP,
+    season = ?1
+WHERE project_id = ?2 AND season = ?3
+`
+
+type RenumberSeasonOfVideosParams struct {
+	NewSeason int64
+	ProjectID int64
+	OldSeason int64
+}
+
+func (q *Queries) RenumberSeasonOfVideos(ctx context.Context, arg RenumberSeasonOfVideosParams) error {
+	_, err := q.db.ExecContext(ctx, renumberSeasonOfVideos, arg.NewSeason, arg.ProjectID, arg.OldSeason)
+	return err
+}
+
+const renumberSerieOfVideos = `-- name: RenumberSerieOfVideos :exec
+
+UPDATE videos
+SET updated_at = CURRENT_TIMESTAMP,
+    serie = ?1
+WHERE project_id = ?2 AND season = ?3 AND serie = ?4
+`
+
+type RenumberSerieOfVideosParams struct {
+	NewSerie  int64
+	ProjectID int64
+	Season    int64
+	OldSerie  int64
+}
+
+func (q *Queries) RenumberSerieOfVideos(ctx context.Context, arg RenumberSerieOfVideosParams) error {
+	_, err := q.db.ExecContext(ctx, renumberSerieOfVideos,
+		arg.NewSerie,
+		arg.ProjectID,
+		arg.Season,
+		arg.OldSerie,
+	)
+	return err
+}
+
+const resetProcessingVideos = `-- name: ResetProcessingVideos :exec
+
+UPDATE videos
+SET hls_status = 'pending'
+WHERE hls_status = 'processing'
+`
+
+func (q *Queries) ResetProcessingVideos(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, resetProcessingVideos)
+	return err
+}
+
+const setVideoHlsStatus = `-- name: SetVideoHlsStatus :exec
+
+UPDATE videos
+SET hls_status = ?,
+    hls_error = ?
+WHERE id = ?
+`
+
+type SetVideoHlsStatusParams struct {
+	HlsStatus string
+	HlsError  string
+	ID        string
+}
+
+func (q *Queries) SetVideoHlsStatus(ctx context.Context, arg SetVideoHlsStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setVideoHlsStatus, arg.HlsStatus, arg.HlsError, arg.ID)
+	return err
+}
+
 const updateVideo = `-- name: UpdateVideo :exec
 
 UPDATE videos
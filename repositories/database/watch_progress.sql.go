@@ -0,0 +1,128 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: watch_progress.sql
+
+package database
+
+import (
+	"context"
+)
+
+const deleteWatchProgressOfProject = `-- name: DeleteWatchProgressOfProject :exec
+
+DELETE FROM watch_progress
+WHERE video_id IN (SELECT id FROM videos WHERE project_id = ?)
+`
+
+func (q *Queries) DeleteWatchProgressOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteWatchProgressOfProject, projectID)
+	return err
+}
+
+const getWatchProgress = `-- name: GetWatchProgress :one
+
+SELECT created_at, updated_at, user_id, video_id, position_in_secs, duration_in_secs, completed FROM watch_progress
+WHERE user_id = ? AND video_id = ?
+`
+
+type GetWatchProgressParams struct {
+	UserID  int64
+	VideoID string
+}
+
+func (q *Queries) GetWatchProgress(ctx context.Context, arg GetWatchProgressParams) (WatchProgress, error) {
+	row := q.db.QueryRowContext(ctx, getWatchProgress, arg.UserID, arg.VideoID)
+	var i WatchProgress
+	err := row.Scan(
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.VideoID,
+		&i.PositionInSecs,
+		&i.DurationInSecs,
+		&i.Completed,
+	)
+	return i, err
+}
+
+const getWatchProgressOfUser = `-- name: GetWatchProgressOfUser :many
+
+SELECT wp.created_at, wp.updated_at, wp.user_id, wp.video_id, wp.position_in_secs, wp.duration_in_secs, wp.completed, v.project_id FROM watch_progress AS wp
+JOIN videos AS v
+ON v.id = wp.video_id
+WHERE wp.user_id = ?
+ORDER BY wp.updated_at DESC
+`
+
+type GetWatchProgressOfUserRow struct {
+	CreatedAt      string
+	UpdatedAt      string
+	UserID         int64
+	VideoID        string
+	PositionInSecs int64
+	DurationInSecs int64
+	Completed      bool
+	ProjectID      int64
+}
+
+func (q *Queries) GetWatchProgressOfUser(ctx context.Context, userID int64) ([]GetWatchProgressOfUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWatchProgressOfUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWatchProgressOfUserRow
+	for rows.Next() {
+		var i GetWatchProgressOfUserRow
+		if err := rows.Scan(
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.VideoID,
+			&i.PositionInSecs,
+			&i.DurationInSecs,
+			&i.Completed,
+			&i.ProjectID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveWatchProgress = `-- name: SaveWatchProgress :exec
+INSERT INTO watch_progress(user_id, video_id, position_in_secs, duration_in_secs, completed)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(user_id, video_id) DO UPDATE
+SET updated_at = CURRENT_TIMESTAMP,
+    position_in_secs = excluded.position_in_secs,
+    duration_in_secs = excluded.duration_in_secs,
+    completed = excluded.completed
+`
+
+type SaveWatchProgressParams struct {
+	UserID         int64
+	VideoID        string
+	PositionInSecs int64
+	DurationInSecs int64
+	Completed      bool
+}
+
+func (q *Queries) SaveWatchProgress(ctx context.Context, arg SaveWatchProgressParams) error {
+	_, err := q.db.ExecContext(ctx, saveWatchProgress,
+		arg.UserID,
+		arg.VideoID,
+		arg.PositionInSecs,
+		arg.DurationInSecs,
+		arg.Completed,
+	)
+	return err
+}
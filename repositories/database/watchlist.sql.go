@@ -12,6 +12,7 @@ import (
 const addProject2Watchlist = `-- name: AddProject2Watchlist :exec
 INSERT INTO watchlist(user_id, project_id)
 VALUES (?, ?)
+ON CONFLICT(user_id, project_id) DO NOTHING
 `
 
 type AddProject2WatchlistParams struct {
@@ -38,3 +39,58 @@ func (q *Queries) DeleteProjectFromWatchlist(ctx context.Context, arg DeleteProj
 	_, err := q.db.ExecContext(ctx, deleteProjectFromWatchlist, arg.UserID, arg.ProjectID)
 	return err
 }
+
+const deleteWatchlistOfProject = `-- name: DeleteWatchlistOfProject :exec
+
+DELETE FROM watchlist WHERE project_id = ?
+`
+
+func (q *Queries) DeleteWatchlistOfProject(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteWatchlistOfProject, projectID)
+	return err
+}
+
+const getWatchlistProjectsOfUser = `-- name: GetWatchlistProjectsOfUser :many
+
+SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords FROM projects AS p
+JOIN watchlist AS w
+ON p.id = w.project_id
+WHERE w.user_id = ?
+ORDER BY w.added_at DESC, w.rowid DESC
+`
+
+func (q *Queries) GetWatchlistProjectsOfUser(ctx context.Context, userID int64) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, getWatchlistProjectsOfUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Description,
+			&i.TypeID,
+			&i.DurationInMins,
+			&i.ReleaseYear,
+			&i.Director,
+			&i.Producer,
+			&i.Cover,
+			&i.Keywords,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,65 @@
+// Package dbtest opens the SQLite databases of the tests
+package dbtest
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Open opens a fresh SQLite database
+// with all the goose Up migrations of repositories/sql/schema applied
+func Open(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(tb.TempDir(), "ozinshe.db")+"?_busy_timeout=5000")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	// the schema is found next to this file, so the tests of any package can open the database
+	_, file, _, _ := runtime.Caller(0)
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(file), "..", "sql", "schema", "*.sql"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if len(files) == 0 {
+		tb.Fatal("no migrations found")
+	}
+	sort.Strings(files)
+	fts5 := HasFTS5(tb, db)
+	for _, file := range files {
+		dat, err := os.ReadFile(file)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		up := strings.Split(string(dat), "-- +goose Down")[0]
+		if !fts5 && strings.Contains(up, "USING fts5") {
+			// go-sqlite3 has FTS5 with the sqlite_fts5 build tag only
+			continue
+		}
+		if _, err := db.Exec(strings.TrimPrefix(up, "-- +goose Up")); err != nil {
+			tb.Fatalf("%s: %v", filepath.Base(file), err)
+		}
+	}
+
+	return db
+}
+
+// HasFTS5 reports whether go-sqlite3 is built with FTS5
+func HasFTS5(tb testing.TB, db *sql.DB) bool {
+	tb.Helper()
+
+	var used bool
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&used); err != nil {
+		tb.Fatal(err)
+	}
+	return used
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+type PeopleRepository struct {
+	Conn *sql.DB
+	DB   *database.Queries
+}
+
+func NewPeopleRepository(db *sql.DB) *PeopleRepository {
+	return &PeopleRepository{
+		Conn: db,
+		DB:   database.New(db),
+	}
+}
+
+// GetById returns the person with the filmography,
+// the latest projects first
+func (pr *PeopleRepository) GetById(ctx context.Context, id int64) (views.Person, error) {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return views.Person{}, err
+	}
+	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
+
+	person, err := qtx.GetPersonById(ctx, id)
+	if err != nil {
+		return views.Person{}, err
+	}
+
+	credits, err := qtx.GetCreditsOfPerson(ctx, id)
+	if err != nil {
+		return views.Person{}, err
+	}
+
+	projectIds := []int64{}
+	for _, c := range credits {
+		projectIds = append(projectIds, c.ProjectID)
+	}
+	dProjects, err := qtx.GetProjectsByIds(ctx, projectIds)
+	if err != nil {
+		return views.Person{}, err
+	}
+	vProjects, err := projects2views(ctx, qtx, dProjects)
+	if err != nil {
+		return views.Person{}, err
+	}
+	projects := map[int64]views.Project{}
+	for _, p := range vProjects {
+		projects[p.ID] = p
+	}
+
+	filmography := []views.FilmographyEntry{}
+	for _, c := range credits {
+		filmography = append(filmography, views.FilmographyEntry{
+			CreditID:  c.ID,
+			Role:      c.Role,
+			Character: c.Character,
+			Project:   projects[c.ProjectID],
+		})
+	}
+	sort.SliceStable(filmography, func(i, j int) bool {
+		return filmography[i].Project.ReleaseYear > filmography[j].Project.ReleaseYear
+	})
+
+	return views.Person{
+		ID:          person.ID,
+		CreatedAt:   person.CreatedAt,
+		UpdatedAt:   person.UpdatedAt,
+		Name:        person.Name,
+		Photo:       person.Photo,
+		Bio:         person.Bio,
+		Filmography: filmography,
+	}, tx.Commit()
+}
+
+// Delete removes the person with the credits
+func (pr *PeopleRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
+
+	err = qtx.DeleteCreditsOfPerson(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = qtx.DeletePerson(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
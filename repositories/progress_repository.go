@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+// completedShare is the share (in percents) of the video
+// after which it is considered watched
+const completedShare = 95
+
+type ProgressRepository struct {
+	Conn *sql.DB
+	DB   *database.Queries
+}
+
+func NewProgressRepository(db *sql.DB) *ProgressRepository {
+	return &ProgressRepository{
+		Conn: db,
+		DB:   database.New(db),
+	}
+}
+
+func (pr *ProgressRepository) Save(ctx context.Context, userID int64, videoID string, spr views.SaveProgressRequest) error {
+	return pr.DB.SaveWatchProgress(ctx, database.SaveWatchProgressParams{
+		UserID:         userID,
+		VideoID:        videoID,
+		PositionInSecs: spr.PositionInSecs,
+		DurationInSecs: spr.DurationInSecs,
+		Completed:      isCompleted(spr),
+	})
+}
+
+// ContinueWatching returns for every project the user started
+// (the most recently watched first)
+// the video to watch next with its progress.
+// Projects with all the videos watched are skipped.
+func (pr *ProgressRepository) ContinueWatching(ctx context.Context, userID int64) ([]views.ContinueWatching, error) {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
+
+	rows, err := qtx.GetWatchProgressOfUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := map[string]views.WatchProgress{}
+	// the most recently watched video of every project
+	lastVideos := map[int64]string{}
+	projectIds := []int64{}
+	for _, row := range rows {
+		progress[row.VideoID] = views.WatchProgress{
+			VideoID:        row.VideoID,
+			UpdatedAt:      row.UpdatedAt,
+			PositionInSecs: row.PositionInSecs,
+			DurationInSecs: row.DurationInSecs,
+			Completed:      row.Completed,
+		}
+		if _, ok := lastVideos[row.ProjectID]; !ok {
+			lastVideos[row.ProjectID] = row.VideoID
+			projectIds = append(projectIds, row.ProjectID)
+		}
+	}
+
+	dProjects, err := qtx.GetProjectsByIds(ctx, projectIds)
+	if err != nil {
+		return nil, err
+	}
+	vProjects, err := projects2views(ctx, qtx, dProjects)
+	if err != nil {
+		return nil, err
+	}
+	projects := map[int64]views.Project{}
+	for _, project := range vProjects {
+		projects[project.ID] = project
+	}
+
+	continueWatching := []views.ContinueWatching{}
+	for _, projectID := range projectIds {
+		project, ok := projects[projectID]
+		if !ok {
+			continue
+		}
+
+		video, ok := nextVideo(project.Videos, progress, lastVideos[projectID])
+		if !ok {
+			continue
+		}
+
+		vProgress, ok := progress[video.ID]
+		if !ok {
+			vProgress = views.WatchProgress{VideoID: video.ID}
+		}
+
+		continueWatching = append(continueWatching, views.ContinueWatching{
+			Project:  project,
+			Video:    video,
+			Progress: vProgress,
+		})
+	}
+
+	return continueWatching, tx.Commit()
+}
+
+func isCompleted(spr views.SaveProgressRequest) bool {
+	if spr.Completed {
+		return true
+	}
+	return spr.DurationInSecs > 0 && spr.PositionInSecs*100 >= spr.DurationInSecs*completedShare
+}
+
+// nextVideo returns the last watched video if it isn't completed,
+// otherwise the first not completed video after it.
+// videos are ordered by season and serie.
+func nextVideo(videos []database.Video, progress map[string]views.WatchProgress, lastVideoID string) (database.Video, bool) {
+	after := false
+	for _, video := range videos {
+		if video.ID == lastVideoID {
+			if !progress[video.ID].Completed {
+				return video, true
+			}
+			after = true
+			continue
+		}
+		if after && !progress[video.ID].Completed {
+			return video, true
+		}
+	}
+	return database.Video{}, false
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/views"
+)
+
+func TestNextVideo(t *testing.T) {
+	videos := []database.Video{
+		{ID: "s1e1", Season: 1, Serie: 1},
+		{ID: "s1e2", Season: 1, Serie: 2},
+		{ID: "s1e3", Season: 1, Serie: 3},
+		{ID: "s2e1", Season: 2, Serie: 1},
+	}
+	watched := views.WatchProgress{Completed: true}
+	started := views.WatchProgress{PositionInSecs: 60, DurationInSecs: 1200}
+
+	tests := []struct {
+		name      string
+		progress  map[string]views.WatchProgress
+		last      string
+		wantVideo string
+		wantOk    bool
+	}{
+		{
+			name:      "Resume started video",
+			progress:  map[string]views.WatchProgress{"s1e2": started},
+			last:      "s1e2",
+			wantVideo: "s1e2",
+			wantOk:    true,
+		},
+		{
+			name:      "Next after watched video",
+			progress:  map[string]views.WatchProgress{"s1e1": watched},
+			last:      "s1e1",
+			wantVideo: "s1e2",
+			wantOk:    true,
+		},
+		{
+			name:      "Skip already watched videos",
+			progress:  map[string]views.WatchProgress{"s1e1": watched, "s1e2": watched, "s1e3": watched},
+			last:      "s1e1",
+			wantVideo: "s2e1",
+			wantOk:    true,
+		},
+		{
+			name:      "Next season",
+			progress:  map[string]views.WatchProgress{"s1e3": watched},
+			last:      "s1e3",
+			wantVideo: "s2e1",
+			wantOk:    true,
+		},
+		{
+			name:     "Last video watched",
+			progress: map[string]views.WatchProgress{"s2e1": watched},
+			last:     "s2e1",
+			wantOk:   false,
+		},
+		{
+			name:     "Unknown video",
+			progress: map[string]views.WatchProgress{},
+			last:     "deleted",
+			wantOk:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, ok := nextVideo(videos, tt.progress, tt.last)
+			if ok != tt.wantOk {
+				t.Fatalf("nextVideo() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if video.ID != tt.wantVideo {
+				t.Errorf("nextVideo() = %v, want %v", video.ID, tt.wantVideo)
+			}
+		})
+	}
+}
+
+func TestIsCompleted(t *testing.T) {
+	tests := []struct {
+		name string
+		spr  views.SaveProgressRequest
+		want bool
+	}{
+		{
+			name: "Marked completed",
+			spr:  views.SaveProgressRequest{Completed: true},
+			want: true,
+		},
+		{
+			name: "Beginning",
+			spr:  views.SaveProgressRequest{PositionInSecs: 10, DurationInSecs: 1000},
+			want: false,
+		},
+		{
+			name: "Credits",
+			spr:  views.SaveProgressRequest{PositionInSecs: 960, DurationInSecs: 1000},
+			want: true,
+		},
+		{
+			name: "Unknown duration",
+			spr:  views.SaveProgressRequest{PositionInSecs: 960},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCompleted(tt.spr); got != tt.want {
+				t.Errorf("isCompleted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"unicode"
 
 	"github.com/Bayan2019/go-ozinshe/repositories/database"
 	"github.com/Bayan2019/go-ozinshe/views"
@@ -20,27 +22,77 @@ func NewProjectsRepository(db *sql.DB) *ProjectsRepository {
 	}
 }
 
-func (pr *ProjectsRepository) GetAll(ctx context.Context) ([]views.Project, error) {
+func (pr *ProjectsRepository) GetAll(ctx context.Context, filter views.ProjectsFilter) ([]views.Project, int64, error) {
 	tx, err := pr.Conn.Begin()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer tx.Rollback()
 	qtx := pr.DB.WithTx(tx)
 
-	dProjects, err := qtx.GetProjects(ctx)
+	total, err := qtx.CountProjects(ctx, database.CountProjectsParams{
+		TypeID:        filter.TypeID,
+		GenreID:       filter.GenreID,
+		AgeCategoryID: filter.AgeCategoryID,
+		YearFrom:      filter.YearFrom,
+		YearTo:        filter.YearTo,
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	projects, err := pr.DatabaseProjects2viewsProjects(ctx, dProjects)
+	dProjects, err := qtx.GetProjectsPage(ctx, database.GetProjectsPageParams{
+		SortBy:        filter.SortBy,
+		Descending:    filter.Descending,
+		TypeID:        filter.TypeID,
+		GenreID:       filter.GenreID,
+		AgeCategoryID: filter.AgeCategoryID,
+		YearFrom:      filter.YearFrom,
+		YearTo:        filter.YearTo,
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	// projects := []views.Project{}
+	projects, err := projects2views(ctx, qtx, dProjects)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return projects, tx.Commit()
+	return projects, total, tx.Commit()
+}
+
+// GetAllOfGenres returns a page of the projects of any of the genres and the total number of them
+func (pr *ProjectsRepository) GetAllOfGenres(ctx context.Context, genreIds []int64, limit, offset int64) ([]views.Project, int64, error) {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
+
+	total, err := qtx.CountProjectsOfGenres(ctx, genreIds)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dProjects, err := qtx.GetProjectsOfGenrers(ctx, database.GetProjectsOfGenrersParams{
+		Ids:    genreIds,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	projects, err := projects2views(ctx, qtx, dProjects)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return projects, total, tx.Commit()
 }
 
 func (pr *ProjectsRepository) GetById(ctx context.Context, id int64) (views.Project, error) {
@@ -56,12 +108,12 @@ func (pr *ProjectsRepository) GetById(ctx context.Context, id int64) (views.Proj
 		return views.Project{}, err
 	}
 
-	project, err := pr.DatabaseProject2viewsProject(ctx, dProject)
+	projects, err := projects2views(ctx, qtx, []database.Project{dProject})
 	if err != nil {
 		return views.Project{}, err
 	}
 
-	return project, tx.Commit()
+	return projects[0], tx.Commit()
 }
 
 func (pr *ProjectsRepository) Create(ctx context.Context, cpr views.CreateProjectRequest) (int64, error) {
@@ -79,8 +131,6 @@ func (pr *ProjectsRepository) Create(ctx context.Context, cpr views.CreateProjec
 		TypeID:         cpr.TypeID,
 		DurationInMins: cpr.DurationInMins,
 		ReleaseYear:    cpr.ReleaseYear,
-		Director:       cpr.Director,
-		Producer:       cpr.Producer,
 		Keywords:       cpr.Keywords,
 	})
 	if err != nil {
@@ -126,8 +176,6 @@ func (pr *ProjectsRepository) Update(ctx context.Context, id int64, upr views.Up
 		TypeID:         upr.TypeID,
 		DurationInMins: upr.DurationInMins,
 		ReleaseYear:    upr.ReleaseYear,
-		Director:       upr.Director,
-		Producer:       upr.Producer,
 		Keywords:       upr.Keywords,
 	})
 	if err != nil {
@@ -167,7 +215,7 @@ func (pr *ProjectsRepository) Update(ctx context.Context, id int64, upr views.Up
 	return tx.Commit()
 }
 
-func (pr *ProjectsRepository) UploadCover(ctx context.Context, project int64, cover string) error {
+func (pr *ProjectsRepository) UploadCover(ctx context.Context, project int64, cover string, width, height int64) error {
 	tx, err := pr.Conn.Begin()
 	if err != nil {
 		return err
@@ -179,6 +227,8 @@ func (pr *ProjectsRepository) UploadCover(ctx context.Context, project int64, co
 	err = qtx.AddImage2Movie(ctx, database.AddImage2MovieParams{
 		ID:        cover,
 		ProjectID: project,
+		Width:     width,
+		Height:    height,
 	})
 	if err != nil {
 		return err
@@ -198,67 +248,184 @@ func (pr *ProjectsRepository) UploadCover(ctx context.Context, project int64, co
 	return tx.Commit()
 }
 
-func (pr *ProjectsRepository) DatabaseProject2viewsProject(ctx context.Context, dProject database.Project) (views.Project, error) {
-	vProject := views.Project{}
+// AddVideo saves the video as the episode of the season, both are created if needed,
+// and fills the duration of the project if it isn't set yet
+func (pr *ProjectsRepository) AddVideo(ctx context.Context, video database.AddVideo2SeriesParams, durationInMins int64) error {
 	tx, err := pr.Conn.Begin()
 	if err != nil {
-		return vProject, err
+		return err
 	}
 	defer tx.Rollback()
 
 	qtx := pr.DB.WithTx(tx)
 
-	typ, err := qtx.GetTypeById(ctx, dProject.TypeID)
+	err = qtx.AddVideo2Series(ctx, video)
 	if err != nil {
-		return vProject, err
+		return err
 	}
-	vProject.Type = typ
 
-	// var image database.Image
-	if dProject.Cover.Valid {
-		image, err := qtx.GetImage(ctx, dProject.Cover.String)
+	season_id, err := qtx.EnsureSeason(ctx, database.EnsureSeasonParams{
+		ProjectID: video.ProjectID,
+		Number:    video.Season,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.SetEpisodeVideo(ctx, database.SetEpisodeVideoParams{
+		SeasonID: season_id,
+		Number:   video.Serie,
+		VideoID: sql.NullString{
+			String: video.ID,
+			Valid:  true,
+		},
+		DurationInSecs: video.DurationInSecs,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = qtx.FillProjectDuration(ctx, database.FillProjectDurationParams{
+		DurationInMins: durationInMins,
+		ID:             video.ProjectID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateSeason renumbers the videos of the season too,
+// so the next upload to the old number doesn't create the season again
+func (pr *ProjectsRepository) UpdateSeason(ctx context.Context, season database.Season, usr views.UpdateSeasonRequest) error {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := pr.DB.WithTx(tx)
+
+	err = qtx.UpdateSeason(ctx, database.UpdateSeasonParams{
+		ID:       season.ID,
+		Number:   usr.Number,
+		Title:    usr.Title,
+		Synopsis: usr.Synopsis,
+	})
+	if err != nil {
+		return err
+	}
+
+	if usr.Number != season.Number {
+		err = qtx.RenumberSeasonOfVideos(ctx, database.RenumberSeasonOfVideosParams{
+			NewSeason: usr.Number,
+			ProjectID: season.ProjectID,
+			OldSeason: season.Number,
+		})
 		if err != nil {
-			return vProject, err
+			return err
 		}
-		vProject.Cover = image
 	}
 
-	genres, err := qtx.GetAllGenresOfProject(ctx, dProject.ID)
+	return tx.Commit()
+}
+
+// UpdateEpisode renumbers the videos of the episode too
+func (pr *ProjectsRepository) UpdateEpisode(ctx context.Context, season database.Season, episode database.Episode, params database.UpdateEpisodeParams) error {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := pr.DB.WithTx(tx)
+
+	err = qtx.UpdateEpisode(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if params.Number != episode.Number {
+		err = qtx.RenumberSerieOfVideos(ctx, database.RenumberSerieOfVideosParams{
+			NewSerie:  params.Number,
+			ProjectID: season.ProjectID,
+			Season:    season.Number,
+			OldSerie:  episode.Number,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the project with all its rows
+// and returns its images and videos, so their files can be removed
+func (pr *ProjectsRepository) Delete(ctx context.Context, id int64) ([]database.Image, []database.Video, error) {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := pr.DB.WithTx(tx)
+
+	_, err = qtx.GetProjectById(ctx, id)
 	if err != nil {
-		return vProject, err
+		return nil, nil, err
 	}
-	vProject.Genres = genres
 
-	ageCategories, err := pr.DB.GetAllAgeCategoriesOfProject(ctx, dProject.ID)
+	images, err := qtx.GetImagesOfProject(ctx, id)
 	if err != nil {
-		return vProject, err
+		return nil, nil, err
 	}
-	vProject.AgeCategories = ageCategories
 
-	images, err := pr.DB.GetImagesOfProject(ctx, dProject.ID)
+	videos, err := qtx.GetVideosOfProject(ctx, id)
 	if err != nil {
-		return vProject, err
+		return nil, nil, err
 	}
-	vProject.Images = images
 
-	videos, err := pr.DB.GetVideosOfProject(ctx, dProject.ID)
+	// the rows are deleted explicitly
+	// in case foreign keys aren't enforced by the connection
+	err = qtx.DeleteWatchProgressOfProject(ctx, id)
 	if err != nil {
-		return vProject, err
+		return nil, nil, err
 	}
-	vProject.Videos = videos
 
-	vProject.ID = dProject.ID
-	vProject.CreatedAt = dProject.CreatedAt
-	vProject.UpdatedAt = dProject.UpdatedAt
-	vProject.Title = dProject.Title
-	vProject.Description = dProject.Description
-	vProject.DurationInMins = dProject.DurationInMins
-	vProject.ReleaseYear = dProject.ReleaseYear
-	vProject.Director = dProject.Director
-	vProject.Producer = dProject.Producer
-	vProject.Keywords = dProject.Keywords
+	err = qtx.DeleteProject(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, deleteOfProject := range []func(context.Context, int64) error{
+		qtx.DeleteEpisodesOfProject,
+		qtx.DeleteSeasonsOfProject,
+		qtx.DeleteSubtitlesOfProject,
+		qtx.DeleteVideosOfProject,
+		qtx.DeleteImagesOfProject,
+		qtx.DeleteGenresOfProject,
+		qtx.DeleteAgeCategoriesOfProject,
+		qtx.DeleteFavouritesOfProject,
+		qtx.DeleteWatchlistOfProject,
+		qtx.DeleteRatingsOfProject,
+		qtx.DeleteCreditsOfProject,
+	} {
+		err = deleteOfProject(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return images, videos, tx.Commit()
+}
 
-	return vProject, tx.Commit()
+func (pr *ProjectsRepository) DatabaseProject2viewsProject(ctx context.Context, dProject database.Project) (views.Project, error) {
+	vProjects, err := pr.DatabaseProjects2viewsProjects(ctx, []database.Project{dProject})
+	if err != nil {
+		return views.Project{}, err
+	}
+	return vProjects[0], nil
 }
 
 func (pr *ProjectsRepository) DatabaseProjects2viewsProjects(ctx context.Context, dprojects []database.Project) ([]views.Project, error) {
@@ -267,15 +434,325 @@ func (pr *ProjectsRepository) DatabaseProjects2viewsProjects(ctx context.Context
 		return nil, err
 	}
 	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
 
-	// qtx := pr.DB.WithTx(tx)
+	vProjects, err := projects2views(ctx, qtx, dprojects)
+	if err != nil {
+		return nil, err
+	}
+	return vProjects, tx.Commit()
+}
+
+// projects2views loads the related rows of all the projects at once,
+// so the number of queries doesn't depend on the number of projects
+func projects2views(ctx context.Context, q *database.Queries, dProjects []database.Project) ([]views.Project, error) {
 	vProjects := []views.Project{}
-	for _, p := range dprojects {
-		vProject, err := pr.DatabaseProject2viewsProject(ctx, p)
+	if len(dProjects) == 0 {
+		return vProjects, nil
+	}
+
+	ids := []int64{}
+	typeIds := []int64{}
+	coverIds := []string{}
+	for _, p := range dProjects {
+		ids = append(ids, p.ID)
+		typeIds = append(typeIds, p.TypeID)
+		if p.Cover.Valid {
+			coverIds = append(coverIds, p.Cover.String)
+		}
+	}
+
+	dTypes, err := q.GetTypesByIds(ctx, typeIds)
+	if err != nil {
+		return nil, err
+	}
+	types := map[int64]database.Type{}
+	for _, t := range dTypes {
+		types[t.ID] = t
+	}
+
+	covers := map[string]database.Image{}
+	if len(coverIds) > 0 {
+		dCovers, err := q.GetImagesByIds(ctx, coverIds)
 		if err != nil {
-			return vProjects, err
+			return nil, err
+		}
+		for _, c := range dCovers {
+			covers[c.ID] = c
+		}
+	}
+
+	stats, err := q.GetRatingStatsOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	ratings := map[int64]views.RatingStats{}
+	for _, s := range stats {
+		ratings[s.ProjectID] = views.RatingStats{
+			Average: s.Average,
+			Count:   s.Count,
+		}
+	}
+
+	genreRows, err := q.GetGenresOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	genres := map[int64][]database.Genre{}
+	for _, row := range genreRows {
+		genres[row.ProjectID] = append(genres[row.ProjectID], row.Genre)
+	}
+
+	ageCategoryRows, err := q.GetAgeCategoriesOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	ageCategories := map[int64][]database.AgeCategory{}
+	for _, row := range ageCategoryRows {
+		ageCategories[row.ProjectID] = append(ageCategories[row.ProjectID], row.AgeCategory)
+	}
+
+	dImages, err := q.GetImagesOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	images := map[int64][]database.Image{}
+	for _, i := range dImages {
+		images[i.ProjectID] = append(images[i.ProjectID], i)
+	}
+
+	dVideos, err := q.GetVideosOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	videos := map[int64][]database.Video{}
+	for _, v := range dVideos {
+		videos[v.ProjectID] = append(videos[v.ProjectID], v)
+	}
+
+	subtitleRows, err := q.GetSubtitlesOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	subtitles := map[int64][]views.Subtitle{}
+	for _, s := range subtitleRows {
+		subtitles[s.ProjectID] = append(subtitles[s.ProjectID], views.Subtitle{
+			ID:        s.ID,
+			VideoID:   s.VideoID,
+			Language:  s.Language,
+			Label:     s.Label,
+			IsDefault: s.IsDefault,
+		})
+	}
+
+	dSeasons, err := q.GetSeasonsOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	episodeRows, err := q.GetEpisodesOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	episodes := map[int64][]database.Episode{}
+	for _, row := range episodeRows {
+		episodes[row.Episode.SeasonID] = append(episodes[row.Episode.SeasonID], row.Episode)
+	}
+	seasons := map[int64][]views.Season{}
+	for _, s := range dSeasons {
+		seasons[s.ProjectID] = append(seasons[s.ProjectID], views.Season{
+			ID:        s.ID,
+			ProjectID: s.ProjectID,
+			Number:    s.Number,
+			Title:     s.Title,
+			Synopsis:  s.Synopsis,
+			Episodes:  episodes2views(episodes[s.ID], images[s.ProjectID], videos[s.ProjectID]),
+		})
+	}
+
+	creditRows, err := q.GetCreditsOfProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	credits := map[int64][]views.Credit{}
+	for _, c := range creditRows {
+		credits[c.ProjectID] = append(credits[c.ProjectID], views.Credit{
+			ID:        c.ID,
+			PersonID:  c.PersonID,
+			Name:      c.Name,
+			Photo:     c.Photo,
+			Role:      c.Role,
+			Character: c.Character,
+			Position:  c.Position,
+		})
+	}
+
+	for _, dProject := range dProjects {
+		vProject := views.Project{
+			ID:             dProject.ID,
+			CreatedAt:      dProject.CreatedAt,
+			UpdatedAt:      dProject.UpdatedAt,
+			Title:          dProject.Title,
+			Description:    dProject.Description,
+			Type:           types[dProject.TypeID],
+			DurationInMins: dProject.DurationInMins,
+			ReleaseYear:    dProject.ReleaseYear,
+			Director:       creditNames(credits[dProject.ID], "director"),
+			Producer:       creditNames(credits[dProject.ID], "producer"),
+			Keywords:       dProject.Keywords,
+			Cover:          covers[dProject.Cover.String],
+			Rating:         ratings[dProject.ID],
+			Genres:         genres[dProject.ID],
+			AgeCategories:  ageCategories[dProject.ID],
+			Images:         images[dProject.ID],
+			Videos:         videos[dProject.ID],
+			Subtitles:      subtitles[dProject.ID],
+			Seasons:        seasons[dProject.ID],
+			Credits:        credits[dProject.ID],
 		}
 		vProjects = append(vProjects, vProject)
 	}
-	return vProjects, tx.Commit()
+
+	return vProjects, nil
+}
+
+// creditNames lists the names of the role in the order of the credits,
+// they replace the director and producer columns of the projects
+func creditNames(credits []views.Credit, role string) string {
+	names := []string{}
+	for _, c := range credits {
+		if c.Role == role {
+			names = append(names, c.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// episodes2views attaches the thumbnails and the videos
+// from the images and the videos of the project
+func episodes2views(dEpisodes []database.Episode, images []database.Image, videos []database.Video) []views.Episode {
+	vEpisodes := []views.Episode{}
+	for _, e := range dEpisodes {
+		vEpisode := views.Episode{
+			ID:             e.ID,
+			SeasonID:       e.SeasonID,
+			Number:         e.Number,
+			Title:          e.Title,
+			Synopsis:       e.Synopsis,
+			DurationInSecs: e.DurationInSecs,
+			ReleaseDate:    e.ReleaseDate,
+		}
+		for _, i := range images {
+			if e.Thumbnail.Valid && i.ID == e.Thumbnail.String {
+				vEpisode.Thumbnail = i
+			}
+		}
+		for _, v := range videos {
+			if e.VideoID.Valid && v.ID == e.VideoID.String {
+				vEpisode.Video = v
+			}
+		}
+		vEpisodes = append(vEpisodes, vEpisode)
+	}
+	return vEpisodes
+}
+
+func (pr *ProjectsRepository) GetFavouritesOfUser(ctx context.Context, userID int64) ([]views.Project, error) {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
+
+	dProjects, err := qtx.GetFavouriteProjectsOfUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := projects2views(ctx, qtx, dProjects)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, tx.Commit()
+}
+
+func (pr *ProjectsRepository) GetWatchlistOfUser(ctx context.Context, userID int64) ([]views.Project, error) {
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
+
+	dProjects, err := qtx.GetWatchlistProjectsOfUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := projects2views(ctx, qtx, dProjects)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, tx.Commit()
+}
+
+// Search finds projects by the words of the term ordered by relevance,
+// if genreIds isn't empty only projects of any of the genres are found
+func (pr *ProjectsRepository) Search(ctx context.Context, term string, genreIds []int64, limit, offset int64) ([]views.ProjectSearchResult, int64, error) {
+	results := []views.ProjectSearchResult{}
+	query := searchQuery(term)
+	if query == "" {
+		return results, 0, nil
+	}
+
+	tx, err := pr.Conn.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+	qtx := pr.DB.WithTx(tx)
+
+	total, err := queryCountSearchProjects(ctx, tx, query, genreIds)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := querySearchProjects(ctx, tx, query, genreIds, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dProjects := []database.Project{}
+	for _, row := range rows {
+		dProjects = append(dProjects, row.Project)
+	}
+	projects, err := projects2views(ctx, qtx, dProjects)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, row := range rows {
+		results = append(results, views.ProjectSearchResult{
+			Project:            projects[i],
+			TitleHighlight:     markMatches(row.TitleHighlight),
+			DescriptionSnippet: markMatches(row.DescriptionSnippet),
+		})
+	}
+
+	return results, total, tx.Commit()
+}
+
+// searchQuery turns the term typed by the user into an FTS5 query:
+// every word is quoted, so FTS5 syntax isn't interpreted,
+// and matched as a prefix, so "қала" finds "қаласы" and "фильм" finds "фильмы"
+func searchQuery(term string) string {
+	words := strings.FieldsFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = `"` + word + `"*`
+	}
+	return strings.Join(words, " ")
 }
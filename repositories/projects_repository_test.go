@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	"github.com/Bayan2019/go-ozinshe/repositories/dbtest"
+)
+
+// createTestProjects creates n projects with all the related rows
+func createTestProjects(tb testing.TB, db *sql.DB, n int) []database.Project {
+	tb.Helper()
+	ctx := context.Background()
+	q := database.New(db)
+
+	projects := []database.Project{}
+	for i := 0; i < n; i++ {
+		id, err := q.CreateProject(ctx, database.CreateProjectParams{
+			Title:       fmt.Sprintf("Project %d", i),
+			TypeID:      int64(i%4 + 1),
+			ReleaseYear: 2000 + int64(i),
+		})
+		if err != nil {
+			tb.Fatal(err)
+		}
+		for _, genreID := range []int64{1, 2} {
+			if err := q.AddGenre2Project(ctx, database.AddGenre2ProjectParams{ProjectID: id, GenreID: genreID}); err != nil {
+				tb.Fatal(err)
+			}
+		}
+		for _, ageCategoryID := range []int64{1, 2} {
+			if err := q.AddAgeCategory2Project(ctx, database.AddAgeCategory2ProjectParams{ProjectID: id, AgeCategoryID: ageCategoryID}); err != nil {
+				tb.Fatal(err)
+			}
+		}
+		cover := fmt.Sprintf("cover%d.png", id)
+		if err := q.AddImage2Movie(ctx, database.AddImage2MovieParams{ID: cover, ProjectID: id}); err != nil {
+			tb.Fatal(err)
+		}
+		if err := q.SetCover(ctx, database.SetCoverParams{ID: id, Cover: sql.NullString{String: cover, Valid: true}}); err != nil {
+			tb.Fatal(err)
+		}
+		for serie := int64(1); serie <= 2; serie++ {
+			if err := q.AddVideo2Series(ctx, database.AddVideo2SeriesParams{
+				ID:        fmt.Sprintf("p%ds1e%d.mp4", id, serie),
+				ProjectID: id,
+				Season:    1,
+				Serie:     serie,
+			}); err != nil {
+				tb.Fatal(err)
+			}
+		}
+		if err := q.RateProject(ctx, database.RateProjectParams{UserID: 1, ProjectID: id, Stars: 4}); err != nil {
+			tb.Fatal(err)
+		}
+
+		project, err := q.GetProjectById(ctx, id)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		projects = append(projects, project)
+	}
+	return projects
+}
+
+// countingDB counts the statements sent to the database
+type countingDB struct {
+	database.DBTX
+	count int
+}
+
+func (c *countingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.count++
+	return c.DBTX.ExecContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.count++
+	return c.DBTX.QueryContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.count++
+	return c.DBTX.QueryRowContext(ctx, query, args...)
+}
+
+func TestProjects2views(t *testing.T) {
+	db := dbtest.Open(t)
+	dProjects := createTestProjects(t, db, 100)
+
+	counts := map[int]int{}
+	for _, n := range []int{1, 10, 100} {
+		counter := &countingDB{DBTX: db}
+		vProjects, err := projects2views(t.Context(), database.New(counter), dProjects[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vProjects) != n {
+			t.Fatalf("got %d projects, want %d", len(vProjects), n)
+		}
+		counts[n] = counter.count
+	}
+	if counts[1] != counts[10] || counts[1] != counts[100] {
+		t.Errorf("queries by number of projects = %v, want the same number", counts)
+	}
+
+	vProjects, err := projects2views(t.Context(), database.New(db), dProjects[2:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := vProjects[0]
+	if p.ID != dProjects[2].ID || p.Type.ID != 3 || p.Cover.ID != dProjects[2].Cover.String {
+		t.Errorf("project = %+v", p)
+	}
+	if len(p.Genres) != 2 || len(p.AgeCategories) != 2 || len(p.Images) != 1 || len(p.Videos) != 2 {
+		t.Errorf("related rows of project = %+v", p)
+	}
+	if p.Videos[0].Serie != 1 || p.Videos[1].Serie != 2 {
+		t.Errorf("videos aren't ordered: %+v", p.Videos)
+	}
+	if p.Rating.Count != 1 || p.Rating.Average != 4 {
+		t.Errorf("rating = %+v", p.Rating)
+	}
+
+	if vProjects, err := projects2views(t.Context(), database.New(db), nil); err != nil || len(vProjects) != 0 {
+		t.Errorf("no projects: %v, %v", vProjects, err)
+	}
+}
+
+func BenchmarkProjects2views(b *testing.B) {
+	db := dbtest.Open(b)
+	dProjects := createTestProjects(b, db, 100)
+
+	for _, n := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("projects=%d", n), func(b *testing.B) {
+			counter := &countingDB{DBTX: db}
+			q := database.New(counter)
+			for b.Loop() {
+				if _, err := projects2views(b.Context(), q, dProjects[:n]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(counter.count)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{term: "", want: ""},
+		{term: "  ", want: ""},
+		{term: "Айдар", want: `"Айдар"*`},
+		{term: "қала  балалары", want: `"қала"* "балалары"*`},
+		{term: `off-line 2`, want: `"off"* "line"* "2"*`},
+		{term: `"Айдар" OR NEAR(x`, want: `"Айдар"* "OR"* "NEAR"* "x"*`},
+		{term: `*^:`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			if got := searchQuery(tt.term); got != tt.want {
+				t.Errorf("searchQuery(%q) = %s, want %s", tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkMatches(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "\x02Айдар\x03", want: "<mark>Айдар</mark>"},
+		{text: "...the \x02cinema\x03 & <b>more</b>", want: "...the <mark>cinema</mark> &amp; &lt;b&gt;more&lt;/b&gt;"},
+		{text: `<mark>x</mark> "quoted"`, want: "&lt;mark&gt;x&lt;/mark&gt; &#34;quoted&#34;"},
+	}
+
+	for _, tt := range tests {
+		if got := markMatches(tt.text); got != tt.want {
+			t.Errorf("markMatches(%q) = %s, want %s", tt.text, got, tt.want)
+		}
+	}
+}
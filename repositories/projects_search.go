@@ -0,0 +1,115 @@
+package repositories
+
+// Written by hand: sqlc can't parse MATCH against an FTS5 table.
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"html"
+	"strings"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+)
+
+// Matched words are wrapped in control characters, which can't come from the search term,
+// and turned into <mark></mark> after the text is escaped
+const (
+	matchStart = "\x02"
+	matchEnd   = "\x03"
+)
+
+var matchMarks = strings.NewReplacer(matchStart, "<mark>", matchEnd, "</mark>")
+
+// markMatches escapes the text of the project for HTML,
+// only the <mark></mark> tags of the matched words are markup
+func markMatches(text string) string {
+	return matchMarks.Replace(html.EscapeString(text))
+}
+
+const searchProjects = `
+SELECT p.id, p.created_at, p.updated_at, p.title, p.description, p.type_id, p.duration_in_mins, p.release_year, p.director, p.producer, p.cover, p.keywords,
+    highlight(projects_search, 0, char(2), char(3)) AS title_highlight,
+    snippet(projects_search, 1, char(2), char(3), '...', 24) AS description_snippet
+FROM projects_search
+JOIN projects AS p
+ON p.id = projects_search.rowid
+WHERE projects_search MATCH ?1
+    AND (json_array_length(?2) = 0 OR EXISTS (
+        SELECT 1 FROM projects_genres AS pg
+        WHERE pg.project_id = p.id AND pg.genre_id IN (SELECT value FROM json_each(?2))))
+ORDER BY bm25(projects_search, 10.0, 1.0, 5.0), p.id
+LIMIT ?3 OFFSET ?4
+`
+
+type searchProjectsRow struct {
+	Project            database.Project
+	TitleHighlight     string
+	DescriptionSnippet string
+}
+
+func querySearchProjects(ctx context.Context, tx *sql.Tx, query string, genreIds []int64, limit, offset int64) ([]searchProjectsRow, error) {
+	ids, err := genreIdsJSON(genreIds)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, searchProjects, query, ids, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []searchProjectsRow{}
+	for rows.Next() {
+		var i searchProjectsRow
+		if err := rows.Scan(
+			&i.Project.ID,
+			&i.Project.CreatedAt,
+			&i.Project.UpdatedAt,
+			&i.Project.Title,
+			&i.Project.Description,
+			&i.Project.TypeID,
+			&i.Project.DurationInMins,
+			&i.Project.ReleaseYear,
+			&i.Project.Director,
+			&i.Project.Producer,
+			&i.Project.Cover,
+			&i.Project.Keywords,
+			&i.TitleHighlight,
+			&i.DescriptionSnippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+const countSearchProjects = `
+SELECT COUNT(*) FROM projects_search
+JOIN projects AS p
+ON p.id = projects_search.rowid
+WHERE projects_search MATCH ?1
+    AND (json_array_length(?2) = 0 OR EXISTS (
+        SELECT 1 FROM projects_genres AS pg
+        WHERE pg.project_id = p.id AND pg.genre_id IN (SELECT value FROM json_each(?2))))
+`
+
+func queryCountSearchProjects(ctx context.Context, tx *sql.Tx, query string, genreIds []int64) (int64, error) {
+	ids, err := genreIdsJSON(genreIds)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = tx.QueryRowContext(ctx, countSearchProjects, query, ids).Scan(&count)
+	return count, err
+}
+
+// genreIdsJSON passes the genre filter as a JSON array for json_each,
+// an empty array disables the filter
+func genreIdsJSON(ids []int64) (string, error) {
+	if ids == nil {
+		ids = []int64{}
+	}
+	dat, err := json.Marshal(ids)
+	return string(dat), err
+}
@@ -28,4 +28,12 @@ SELECT ac.* FROM age_categories AS ac
 JOIN projects_age_categories AS pac
 ON ac.id = pac.age_category_id
 WHERE pac.project_id = ?;
+--
+
+-- name: GetAgeCategoriesOfProjects :many
+SELECT pac.project_id, sqlc.embed(ac) FROM age_categories AS ac
+JOIN projects_age_categories AS pac
+ON ac.id = pac.age_category_id
+WHERE pac.project_id IN (sqlc.slice('ids'))
+ORDER BY pac.project_id, ac.id;
 --
\ No newline at end of file
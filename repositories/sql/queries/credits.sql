@@ -0,0 +1,42 @@
+-- name: AddCredit :one
+INSERT INTO credits(project_id, person_id, role, character, position)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id;
+--
+
+-- name: GetCreditById :one
+SELECT * FROM credits WHERE id = ?;
+--
+
+-- name: GetCredit :one
+SELECT * FROM credits
+WHERE project_id = ? AND person_id = ? AND role = ? AND character = ?;
+--
+
+-- name: GetCreditsOfProjects :many
+SELECT c.*, p.name, p.photo FROM credits AS c
+JOIN people AS p
+ON p.id = c.person_id
+WHERE c.project_id IN (sqlc.slice('ids'))
+ORDER BY c.project_id,
+    CASE c.role WHEN 'director' THEN 0 WHEN 'writer' THEN 1 WHEN 'producer' THEN 2 ELSE 3 END,
+    c.position, c.id;
+--
+
+-- name: GetCreditsOfPerson :many
+SELECT * FROM credits
+WHERE person_id = ?
+ORDER BY project_id, role, id;
+--
+
+-- name: DeleteCredit :exec
+DELETE FROM credits WHERE id = ?;
+--
+
+-- name: DeleteCreditsOfPerson :exec
+DELETE FROM credits WHERE person_id = ?;
+--
+
+-- name: DeleteCreditsOfProject :exec
+DELETE FROM credits WHERE project_id = ?;
+--
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens(token_hash, user_id, email, expires_at)
+VALUES (?, ?, ?, ?);
+--
+
+-- name: UseEmailVerificationToken :one
+UPDATE email_verification_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = ?
+    AND used_at IS NULL
+    AND expires_at > sqlc.arg(now)
+RETURNING user_id, email;
+--
+
+-- name: DeleteEmailVerificationTokensOfUser :exec
+DELETE FROM email_verification_tokens
+WHERE user_id = ? AND used_at IS NULL;
+--
\ No newline at end of file
@@ -0,0 +1,65 @@
+-- name: CreateEpisode :one
+INSERT INTO episodes(season_id, number, title, synopsis, duration_in_secs, release_date, thumbnail, video_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+--
+
+-- name: GetEpisodeById :one
+SELECT * FROM episodes WHERE id = ?;
+--
+
+-- name: GetEpisodeByNumber :one
+SELECT * FROM episodes WHERE season_id = ? AND number = ?;
+--
+
+-- name: GetEpisodesOfProjects :many
+SELECT s.project_id, sqlc.embed(e) FROM episodes AS e
+JOIN seasons AS s
+ON s.id = e.season_id
+WHERE s.project_id IN (sqlc.slice('ids'))
+ORDER BY s.project_id, s.number, e.number;
+--
+
+-- name: UpdateEpisode :exec
+UPDATE episodes
+SET updated_at = CURRENT_TIMESTAMP,
+    number = ?,
+    title = ?,
+    synopsis = ?,
+    duration_in_secs = ?,
+    release_date = ?,
+    thumbnail = ?,
+    video_id = ?
+WHERE id = ?;
+--
+
+-- name: SetEpisodeVideo :exec
+INSERT INTO episodes(season_id, number, video_id, duration_in_secs)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(season_id, number) DO UPDATE
+SET updated_at = CURRENT_TIMESTAMP,
+    video_id = excluded.video_id,
+    duration_in_secs = CASE WHEN episodes.duration_in_secs = 0
+        THEN excluded.duration_in_secs
+        ELSE episodes.duration_in_secs END;
+--
+
+-- name: DeleteEpisode :exec
+DELETE FROM episodes WHERE id = ?;
+--
+
+-- name: DeleteEpisodesOfSeason :exec
+DELETE FROM episodes WHERE season_id = ?;
+--
+
+-- name: DeleteEpisodesOfProject :exec
+DELETE FROM episodes
+WHERE season_id IN (SELECT id FROM seasons WHERE project_id = ?);
+--
+
+-- name: UnsetVideoOfEpisodes :exec
+UPDATE episodes
+SET updated_at = CURRENT_TIMESTAMP,
+    video_id = NULL
+WHERE video_id = ?;
+--
\ No newline at end of file
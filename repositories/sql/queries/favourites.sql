@@ -1,8 +1,21 @@
 -- name: AddProject2Favourites :exec
 INSERT INTO favourites(user_id, project_id)
-VALUES (?, ?);
+VALUES (?, ?)
+ON CONFLICT(user_id, project_id) DO NOTHING;
 --
 
 -- name: DeleteProjectFromFavourites :exec
 DELETE FROM favourites WHERE user_id = ? AND project_id = ?;
+--
+
+-- name: GetFavouriteProjectsOfUser :many
+SELECT p.* FROM projects AS p
+JOIN favourites AS f
+ON p.id = f.project_id
+WHERE f.user_id = ?
+ORDER BY f.added_at DESC, f.rowid DESC;
+--
+
+-- name: DeleteFavouritesOfProject :exec
+DELETE FROM favourites WHERE project_id = ?;
 --
\ No newline at end of file
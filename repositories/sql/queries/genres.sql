@@ -27,4 +27,12 @@ SELECT g.* FROM genres AS g
 JOIN projects_genres AS mg
 ON g.id = mg.genre_id
 WHERE mg.project_id = ?;
+--
+
+-- name: GetGenresOfProjects :many
+SELECT mg.project_id, sqlc.embed(g) FROM genres AS g
+JOIN projects_genres AS mg
+ON g.id = mg.genre_id
+WHERE mg.project_id IN (sqlc.slice('ids'))
+ORDER BY mg.project_id, g.id;
 --
\ No newline at end of file
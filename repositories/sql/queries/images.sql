@@ -1,6 +1,6 @@
 -- name: AddImage2Movie :exec
-INSERT INTO images(id, project_id, href)
-VALUES (?, ?, ?);
+INSERT INTO images(id, project_id, href, width, height)
+VALUES (?, ?, ?, ?, ?);
 --
 
 -- name: GetImage :one
@@ -27,4 +27,19 @@ WHERE id = ?;
 
 -- name: DeleteImage :exec
 DELETE FROM images WHERE id = ?;
+--
+
+-- name: GetImagesByIds :many
+SELECT * FROM images
+WHERE id IN (sqlc.slice('ids'));
+--
+
+-- name: GetImagesOfProjects :many
+SELECT * FROM images
+WHERE project_id IN (sqlc.slice('ids'))
+ORDER BY project_id, created_at, id;
+--
+
+-- name: DeleteImagesOfProject :exec
+DELETE FROM images WHERE project_id = ?;
 --
\ No newline at end of file
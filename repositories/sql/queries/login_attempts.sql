@@ -0,0 +1,34 @@
+-- name: GetLoginAttempts :one
+SELECT failures, last_failed_at FROM login_attempts WHERE key = ?;
+--
+
+-- name: AddFirstLoginAttempt :execrows
+INSERT INTO login_attempts(key, failures, last_failed_at)
+VALUES (?, 1, ?)
+ON CONFLICT(key) DO NOTHING;
+--
+
+-- name: AddLoginAttempt :execrows
+-- the attempt is counted only if the failures are still the read ones
+UPDATE login_attempts
+SET failures = failures + 1,
+    previous_failed_at = last_failed_at,
+    last_failed_at = sqlc.arg(last_failed_at)
+WHERE key = sqlc.arg(key) AND failures = sqlc.arg(failures);
+--
+
+-- name: ForgiveLoginAttempt :exec
+-- the wait is as if the attempt wasn't made
+UPDATE login_attempts
+SET failures = failures - 1,
+    last_failed_at = previous_failed_at
+WHERE key = ? AND failures > 0;
+--
+
+-- name: DeleteLoginAttempts :exec
+DELETE FROM login_attempts WHERE key = ?;
+--
+
+-- name: DeleteStaleLoginAttempts :exec
+DELETE FROM login_attempts WHERE last_failed_at < ?;
+--
\ No newline at end of file
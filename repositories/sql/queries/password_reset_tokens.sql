@@ -0,0 +1,18 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens(token_hash, user_id, expires_at)
+VALUES (?, ?, ?);
+--
+
+-- name: UsePasswordResetToken :one
+UPDATE password_reset_tokens
+SET used_at = CURRENT_TIMESTAMP
+WHERE token_hash = ?
+    AND used_at IS NULL
+    AND expires_at > sqlc.arg(now)
+RETURNING user_id;
+--
+
+-- name: DeletePasswordResetTokensOfUser :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = ? AND used_at IS NULL;
+--
\ No newline at end of file
@@ -0,0 +1,46 @@
+-- name: CreatePerson :one
+INSERT INTO people(name, bio)
+VALUES (?, ?)
+RETURNING id;
+--
+
+-- name: GetPersonById :one
+SELECT * FROM people WHERE id = ?;
+--
+
+-- name: GetPeople :many
+SELECT * FROM people
+WHERE name LIKE @search
+ORDER BY name, id
+LIMIT @limit OFFSET @offset;
+--
+
+-- name: CountPeople :one
+SELECT COUNT(*) FROM people
+WHERE name LIKE @search;
+--
+
+-- name: UpdatePerson :exec
+UPDATE people
+SET updated_at = CURRENT_TIMESTAMP,
+    name = ?,
+    bio = ?
+WHERE id = ?;
+--
+
+-- name: SetPersonPhoto :exec
+UPDATE people
+SET updated_at = CURRENT_TIMESTAMP,
+    photo = ?
+WHERE id = ?;
+--
+
+-- name: DeletePerson :exec
+DELETE FROM people WHERE id = ?;
+--
+
+-- name: GetPeoplePhotos :many
+SELECT id, photo FROM people
+WHERE photo != ''
+ORDER BY id;
+--
\ No newline at end of file
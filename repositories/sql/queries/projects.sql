@@ -2,38 +2,82 @@
 SELECT * FROM projects;
 --
 
--- name: GetProjectsOfGenrers :many
+-- name: GetProjectsPage :many
 SELECT p.* FROM projects AS p
-JOIN projects_genres AS pg 
-ON p.id = pg.project_id
-WHERE pg.genre_id IN (sqlc.slice('ids'));
+JOIN (
+    SELECT pr.id,
+        CASE CAST(@sort_by AS TEXT)
+            WHEN 'title' THEN pr.title
+            WHEN 'release_year' THEN pr.release_year
+            WHEN 'rating' THEN (SELECT COALESCE(AVG(r.stars), 0) FROM ratings AS r WHERE r.project_id = pr.id)
+            ELSE pr.created_at
+        END AS sort_key,
+        CAST(@descending AS BOOLEAN) AS descending
+    FROM projects AS pr
+) AS s
+ON s.id = p.id
+WHERE (p.type_id = sqlc.narg('type_id') OR sqlc.narg('type_id') IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_genres AS pg
+            WHERE pg.project_id = p.id AND pg.genre_id = sqlc.narg('genre_id'))
+        OR sqlc.narg('genre_id') IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_age_categories AS pac
+            WHERE pac.project_id = p.id AND pac.age_category_id = sqlc.narg('age_category_id'))
+        OR sqlc.narg('age_category_id') IS NULL)
+    AND (p.release_year >= sqlc.narg('year_from') OR sqlc.narg('year_from') IS NULL)
+    AND (p.release_year <= sqlc.narg('year_to') OR sqlc.narg('year_to') IS NULL)
+ORDER BY
+    CASE WHEN NOT s.descending THEN s.sort_key END ASC,
+    CASE WHEN s.descending THEN s.sort_key END DESC,
+    CASE WHEN NOT s.descending THEN p.id END ASC,
+    p.id DESC
+LIMIT @limit OFFSET @offset;
 --
 
--- -- name: PragmaCaseSensitiveOFF :exec
--- PRAGMA case_sensitive_like = OFF;
--- --
+-- name: CountProjects :one
+SELECT COUNT(*) FROM projects AS p
+WHERE (p.type_id = sqlc.narg('type_id') OR sqlc.narg('type_id') IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_genres AS pg
+            WHERE pg.project_id = p.id AND pg.genre_id = sqlc.narg('genre_id'))
+        OR sqlc.narg('genre_id') IS NULL)
+    AND (EXISTS (
+            SELECT 1 FROM projects_age_categories AS pac
+            WHERE pac.project_id = p.id AND pac.age_category_id = sqlc.narg('age_category_id'))
+        OR sqlc.narg('age_category_id') IS NULL)
+    AND (p.release_year >= sqlc.narg('year_from') OR sqlc.narg('year_from') IS NULL)
+    AND (p.release_year <= sqlc.narg('year_to') OR sqlc.narg('year_to') IS NULL);
+--
 
--- name: GetProjectsOfGenresAndSearch :many
+-- name: GetProjectsOfGenrers :many
 SELECT p.* FROM projects AS p
-JOIN projects_genres AS pg 
-ON p.id = pg.project_id
-WHERE pg.genre_id IN (sqlc.slice('ids')) 
-    AND ((LOWER(p.title) LIKE LOWER(@search)) 
-        OR (LOWER(p.description) LIKE LOWER(@search))
-        OR (LOWER(p.keywords) LIKE LOWER(@search)));
+WHERE EXISTS (
+    SELECT 1 FROM projects_genres AS pg
+    WHERE pg.project_id = p.id AND pg.genre_id IN (sqlc.slice('ids')))
+ORDER BY p.id
+LIMIT ? OFFSET ?;
 --
 
--- name: GetProjectsSearch :many
-SELECT * FROM projects
-WHERE ((LOWER(title) LIKE LOWER(@search)) 
-        OR (LOWER(description) LIKE LOWER(@search))
-        OR (LOWER(keywords) LIKE LOWER(@search)));
+-- name: CountProjectsOfGenres :one
+SELECT COUNT(*) FROM projects AS p
+WHERE EXISTS (
+    SELECT 1 FROM projects_genres AS pg
+    WHERE pg.project_id = p.id AND pg.genre_id IN (sqlc.slice('ids')));
 --
 
+-- -- name: PragmaCaseSensitiveOFF :exec
+-- PRAGMA case_sensitive_like = OFF;
+-- --
+
 -- name: GetProjectById :one
 SELECT * FROM projects WHERE id = ?;
 --
 
+-- name: GetProjectsByIds :many
+SELECT * FROM projects WHERE id IN (sqlc.slice('ids'));
+--
+
 -- name: GetProjectsOfGenre :many
 SELECT p.* FROM projects AS p
 JOIN projects_genres AS pg 
@@ -54,8 +98,8 @@ WHERE type_id = ?;
 --
 
 -- name: CreateProject :one
-INSERT INTO projects(title, description, type_id, duration_in_mins, release_year, director, producer, keywords)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO projects(title, description, type_id, duration_in_mins, release_year, keywords)
+VALUES (?, ?, ?, ?, ?, ?)
 RETURNING id;
 --
 
@@ -67,8 +111,6 @@ SET updated_at = CURRENT_TIMESTAMP,
     type_id = ?,
     duration_in_mins = ?,
     release_year = ?,
-    director = ?,
-    producer = ?,
     keywords = ?
 WHERE id = ?;
 --
@@ -80,6 +122,13 @@ SET updated_at = CURRENT_TIMESTAMP,
 WHERE id = ?;
 --
 
+-- name: FillProjectDuration :exec
+UPDATE projects
+SET updated_at = CURRENT_TIMESTAMP,
+    duration_in_mins = ?
+WHERE id = ? AND duration_in_mins = 0;
+--
+
 -- name: DeleteProject :exec
 DELETE FROM projects WHERE id = ?;
 --
\ No newline at end of file
@@ -0,0 +1,73 @@
+-- name: RateProject :exec
+INSERT INTO ratings(user_id, project_id, stars, review)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(user_id, project_id) DO UPDATE
+SET updated_at = CURRENT_TIMESTAMP,
+    stars = excluded.stars,
+    review = excluded.review;
+--
+
+-- name: DeleteRatingOfUser :exec
+DELETE FROM ratings WHERE user_id = ? AND project_id = ?;
+--
+
+-- name: GetRatingById :one
+SELECT * FROM ratings WHERE id = ?;
+--
+
+-- name: GetRatingOfUser :one
+SELECT * FROM ratings WHERE user_id = ? AND project_id = ?;
+--
+
+-- name: GetRatingsOfProject :many
+SELECT r.*, u.name AS user_name FROM ratings AS r
+JOIN users AS u
+ON u.id = r.user_id
+WHERE r.project_id = @project_id
+    AND (r.hidden = FALSE OR CAST(@with_hidden AS BOOLEAN))
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT @limit OFFSET @offset;
+--
+
+-- name: CountRatingsOfProject :one
+SELECT COUNT(*) FROM ratings
+WHERE project_id = @project_id
+    AND (hidden = FALSE OR CAST(@with_hidden AS BOOLEAN));
+--
+
+-- name: GetRatingsOfUser :many
+SELECT r.*, u.name AS user_name FROM ratings AS r
+JOIN users AS u
+ON u.id = r.user_id
+WHERE r.user_id = ?
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT ? OFFSET ?;
+--
+
+-- name: CountRatingsOfUser :one
+SELECT COUNT(*) FROM ratings WHERE user_id = ?;
+--
+
+-- name: GetRatingStatsOfProject :one
+SELECT CAST(COALESCE(AVG(stars), 0) AS REAL) AS average, COUNT(*) AS count
+FROM ratings
+WHERE project_id = ?;
+--
+
+-- name: GetRatingStatsOfProjects :many
+SELECT project_id, CAST(AVG(stars) AS REAL) AS average, COUNT(*) AS count
+FROM ratings
+WHERE project_id IN (sqlc.slice('ids'))
+GROUP BY project_id;
+--
+
+-- name: SetRatingHidden :exec
+UPDATE ratings
+SET updated_at = CURRENT_TIMESTAMP,
+    hidden = ?
+WHERE id = ?;
+--
+
+-- name: DeleteRatingsOfProject :exec
+DELETE FROM ratings WHERE project_id = ?;
+--
\ No newline at end of file
@@ -1,9 +1,9 @@
 -- name: CreateRefreshToken :exec
-INSERT INTO refresh_tokens(token, created_at, updated_at, user_id, expires_at, revoked_at)
+INSERT INTO refresh_tokens(token, created_at, updated_at, user_id, expires_at, revoked_at, family, user_agent, ip, signed_in_at)
 VALUES (
     ?, 
     CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, 
-    ?, NULL
+    ?, NULL, ?, ?, ?, ?
 );
 --
 
@@ -28,4 +28,55 @@ WHERE user_id = ?
     AND revoked_at IS NULL
     AND expires_at > CURRENT_TIMESTAMP
 ORDER BY created_at DESC;
+--
+
+-- name: GetRefreshToken :one
+SELECT * FROM refresh_tokens WHERE token = ?;
+--
+
+-- name: RotateRefreshToken :execrows
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP,
+    revoked_at = CURRENT_TIMESTAMP,
+    replaced_by = ?
+WHERE token = ? AND revoked_at IS NULL;
+--
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE family = ? AND revoked_at IS NULL;
+--
+
+-- name: GetSessionsOfUser :many
+SELECT family, user_agent, ip, signed_in_at, created_at, expires_at FROM refresh_tokens
+WHERE user_id = ?
+    AND revoked_at IS NULL
+    AND expires_at > sqlc.arg(now)
+ORDER BY signed_in_at DESC, family;
+--
+
+-- name: IsSessionActive :one
+SELECT COUNT(*) > 0 FROM refresh_tokens
+WHERE family = ?
+    AND revoked_at IS NULL
+    AND expires_at > sqlc.arg(now);
+--
+
+-- name: RevokeSessionOfUser :execrows
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE family = ? AND user_id = ? AND revoked_at IS NULL;
+--
+
+-- name: RevokeOtherSessionsOfUser :exec
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = ? AND family != sqlc.arg(current) AND revoked_at IS NULL;
+--
+
+-- name: RevokeSessionsOfUser :exec
+UPDATE refresh_tokens
+SET updated_at = CURRENT_TIMESTAMP, revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = ? AND revoked_at IS NULL;
 --
\ No newline at end of file
@@ -7,8 +7,8 @@ SELECT * FROM roles WHERE id = ?;
 --
 
 -- name: CreateRole :one
-INSERT INTO roles(title, projects, genres, age_categories, types, users, roles)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO roles(title, projects, genres, age_categories, types, users, roles, require_two_factor)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id;
 --
 
@@ -20,7 +20,8 @@ SET title = ?,
     age_categories = ?,
     types = ?,
     users = ?,
-    roles = ?
+    roles = ?,
+    require_two_factor = ?
 WHERE id = ?;
 --
 
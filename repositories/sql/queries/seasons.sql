@@ -0,0 +1,44 @@
+-- name: CreateSeason :one
+INSERT INTO seasons(project_id, number, title, synopsis)
+VALUES (?, ?, ?, ?)
+RETURNING id;
+--
+
+-- name: GetSeasonById :one
+SELECT * FROM seasons WHERE id = ?;
+--
+
+-- name: GetSeasonByNumber :one
+SELECT * FROM seasons WHERE project_id = ? AND number = ?;
+--
+
+-- name: GetSeasonsOfProjects :many
+SELECT * FROM seasons
+WHERE project_id IN (sqlc.slice('ids'))
+ORDER BY project_id, number;
+--
+
+-- name: UpdateSeason :exec
+UPDATE seasons
+SET updated_at = CURRENT_TIMESTAMP,
+    number = ?,
+    title = ?,
+    synopsis = ?
+WHERE id = ?;
+--
+
+-- name: EnsureSeason :one
+INSERT INTO seasons(project_id, number)
+VALUES (?, ?)
+ON CONFLICT(project_id, number) DO UPDATE
+SET number = excluded.number
+RETURNING id;
+--
+
+-- name: DeleteSeason :exec
+DELETE FROM seasons WHERE id = ?;
+--
+
+-- name: DeleteSeasonsOfProject :exec
+DELETE FROM seasons WHERE project_id = ?;
+--
\ No newline at end of file
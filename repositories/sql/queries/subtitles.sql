@@ -0,0 +1,57 @@
+-- name: AddSubtitle :one
+INSERT INTO subtitles(video_id, language, label, is_default, file)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id;
+--
+
+-- name: GetSubtitleById :one
+SELECT * FROM subtitles WHERE id = ?;
+--
+
+-- name: GetSubtitlesOfVideo :many
+SELECT * FROM subtitles
+WHERE video_id = ?
+ORDER BY is_default DESC, language, label, id;
+--
+
+-- name: GetSubtitlesOfProjects :many
+SELECT s.*, v.project_id FROM subtitles AS s
+JOIN videos AS v
+ON v.id = s.video_id
+WHERE v.project_id IN (sqlc.slice('ids'))
+ORDER BY v.project_id, v.season, v.serie, s.is_default DESC, s.language, s.label, s.id;
+--
+
+-- name: UpdateSubtitle :exec
+UPDATE subtitles
+SET updated_at = CURRENT_TIMESTAMP,
+    language = ?,
+    label = ?,
+    is_default = ?
+WHERE id = ?;
+--
+
+-- name: SetDefaultSubtitle :exec
+UPDATE subtitles
+SET updated_at = CURRENT_TIMESTAMP,
+    is_default = (id = sqlc.arg(id))
+WHERE video_id = sqlc.arg(video_id);
+--
+
+-- name: DeleteSubtitle :exec
+DELETE FROM subtitles WHERE id = ?;
+--
+
+-- name: DeleteSubtitlesOfVideo :exec
+DELETE FROM subtitles WHERE video_id = ?;
+--
+
+-- name: DeleteSubtitlesOfProject :exec
+DELETE FROM subtitles
+WHERE video_id IN (SELECT id FROM videos WHERE project_id = ?);
+--
+
+-- name: GetSubtitles :many
+SELECT * FROM subtitles
+ORDER BY id;
+--
\ No newline at end of file
@@ -0,0 +1,45 @@
+-- name: SetTotpSecret :execrows
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    totp_secret = ?,
+    totp_counter = 0
+WHERE id = ? AND totp_enabled_at IS NULL;
+--
+
+-- name: EnableTotp :execrows
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    totp_enabled_at = CURRENT_TIMESTAMP,
+    totp_counter = ?
+WHERE id = ? AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL;
+--
+
+-- name: UseTotpCounter :execrows
+UPDATE users
+SET totp_counter = sqlc.arg(counter)
+WHERE id = sqlc.arg(id) AND totp_counter < sqlc.arg(counter);
+--
+
+-- name: DisableTotp :exec
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    totp_secret = NULL,
+    totp_enabled_at = NULL,
+    totp_counter = 0
+WHERE id = ?;
+--
+
+-- name: AddRecoveryCode :exec
+INSERT INTO recovery_codes(code_hash, user_id)
+VALUES (?, ?);
+--
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE code_hash = ? AND user_id = ? AND used_at IS NULL;
+--
+
+-- name: DeleteRecoveryCodesOfUser :exec
+DELETE FROM recovery_codes WHERE user_id = ?;
+--
\ No newline at end of file
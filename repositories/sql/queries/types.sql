@@ -20,4 +20,8 @@ WHERE id = ?;
 
 -- name: DeleteType :exec
 DELETE FROM types WHERE id = ?;
+--
+
+-- name: GetTypesByIds :many
+SELECT * FROM types WHERE id IN (sqlc.slice('ids'));
 --
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- name: CreateUpload :exec
+INSERT INTO uploads(id, expires_at, user_id, length, metadata)
+VALUES (?, ?, ?, ?, ?);
+--
+
+-- name: GetUploadOfUser :one
+SELECT * FROM uploads
+WHERE id = ? AND user_id = ? AND expires_at > CURRENT_TIMESTAMP;
+--
+
+-- name: AppendUploadPart :execrows
+UPDATE uploads
+SET received = received + sqlc.arg(size), parts = parts || sqlc.arg(part) || char(10)
+WHERE id = sqlc.arg(id) AND received = sqlc.arg(offset) AND media_id = '';
+--
+
+-- name: CompleteUpload :exec
+UPDATE uploads
+SET media_id = ?
+WHERE id = ?;
+--
+
+-- name: DeleteUpload :exec
+DELETE FROM uploads WHERE id = ?;
+--
+
+-- name: GetExpiredUploads :many
+SELECT id FROM uploads
+WHERE expires_at <= CURRENT_TIMESTAMP;
+--
+
+-- name: DeleteExpiredUploads :exec
+DELETE FROM uploads
+WHERE expires_at <= CURRENT_TIMESTAMP;
+--
+
+-- name: GetUploadIds :many
+SELECT id FROM uploads;
+--
\ No newline at end of file
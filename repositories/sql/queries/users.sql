@@ -24,14 +24,24 @@ SELECT * FROM users WHERE id = ?;
 SELECT * FROM users WHERE email = ?;
 --
 
+-- name: GetUserBySignInEmail :one
+-- the email is trimmed and lowercased as the key of the sign in attempts,
+-- of the accounts differing only in case the oldest one signs in
+SELECT * FROM users WHERE lower(email) = ?
+ORDER BY id
+LIMIT 1;
+--
+
 -- name: UpdateUser :exec
 UPDATE users
 SET updated_at = CURRENT_TIMESTAMP,
-    name = ?,
-    email = ?,
-    date_of_birth = ?,
-    phone = ?
-WHERE id = ?;
+    -- a new email has to be verified again
+    email_verified_at = CASE WHEN email = sqlc.arg(email) THEN email_verified_at END,
+    name = sqlc.arg(name),
+    email = sqlc.arg(email),
+    date_of_birth = sqlc.arg(date_of_birth),
+    phone = sqlc.arg(phone)
+WHERE id = sqlc.arg(id);
 --
 
 -- name: ChangePassword :exec
@@ -42,4 +52,11 @@ WHERE id = ?;
 
 -- name: DeleteUser :exec
 DELETE FROM users WHERE id = ?;
+--
+
+-- name: VerifyEmail :execrows
+UPDATE users
+SET updated_at = CURRENT_TIMESTAMP,
+    email_verified_at = CURRENT_TIMESTAMP
+WHERE id = ? AND email = ? AND email_verified_at IS NULL;
 --
\ No newline at end of file
@@ -4,8 +4,8 @@ VALUES (?, ?, ?);
 --
 
 -- name: AddVideo2Series :exec
-INSERT INTO videos(id, project_id, season, serie, href)
-VALUES (?, ?, ?, ?, ?);
+INSERT INTO videos(id, project_id, season, serie, href, duration_in_secs, width, height)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 --
 
 -- name: GetVideo :one
@@ -15,9 +15,15 @@ WHERE project_id = ? AND
     serie = ?;
 --
 
+-- name: GetVideoById :one
+SELECT * FROM videos
+WHERE id = ?;
+--
+
 -- name: GetVideosOfProject :many
 SELECT * FROM videos
-WHERE project_id = ?;
+WHERE project_id = ?
+ORDER BY season, serie;
 --
 
 -- name: GetVideos :many
@@ -36,4 +42,48 @@ WHERE id = ?;
 
 -- name: DeleteVideo :exec
 DELETE FROM videos WHERE id = ?;
+--
+
+-- name: GetVideosOfProjects :many
+SELECT * FROM videos
+WHERE project_id IN (sqlc.slice('ids'))
+ORDER BY project_id, season, serie;
+--
+
+-- name: DeleteVideosOfProject :exec
+DELETE FROM videos WHERE project_id = ?;
+--
+
+-- name: GetNextPendingVideo :one
+SELECT * FROM videos
+WHERE hls_status = 'pending'
+ORDER BY created_at, id
+LIMIT 1;
+--
+
+-- name: SetVideoHlsStatus :exec
+UPDATE videos
+SET hls_status = ?,
+    hls_error = ?
+WHERE id = ?;
+--
+
+-- name: ResetProcessingVideos :exec
+UPDATE videos
+SET hls_status = 'pending'
+WHERE hls_status = 'processing';
+--
+
+-- name: RenumberSeasonOfVideos :exec
+UPDATE videos
+SET updated_at = CURRENT_TIMESTAMP,
+    season = sqlc.arg(new_season)
+WHERE project_id = sqlc.arg(project_id) AND season = sqlc.arg(old_season);
+--
+
+-- name: RenumberSerieOfVideos :exec
+UPDATE videos
+SET updated_at = CURRENT_TIMESTAMP,
+    serie = sqlc.arg(new_serie)
+WHERE project_id = sqlc.arg(project_id) AND season = sqlc.arg(season) AND serie = sqlc.arg(old_serie);
 --
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- name: SaveWatchProgress :exec
+INSERT INTO watch_progress(user_id, video_id, position_in_secs, duration_in_secs, completed)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(user_id, video_id) DO UPDATE
+SET updated_at = CURRENT_TIMESTAMP,
+    position_in_secs = excluded.position_in_secs,
+    duration_in_secs = excluded.duration_in_secs,
+    completed = excluded.completed;
+--
+
+-- name: GetWatchProgress :one
+SELECT * FROM watch_progress
+WHERE user_id = ? AND video_id = ?;
+--
+
+-- name: GetWatchProgressOfUser :many
+SELECT wp.*, v.project_id FROM watch_progress AS wp
+JOIN videos AS v
+ON v.id = wp.video_id
+WHERE wp.user_id = ?
+ORDER BY wp.updated_at DESC;
+--
+
+-- name: DeleteWatchProgressOfProject :exec
+DELETE FROM watch_progress
+WHERE video_id IN (SELECT id FROM videos WHERE project_id = ?);
+--
\ No newline at end of file
@@ -1,8 +1,21 @@
 -- name: AddProject2Watchlist :exec
 INSERT INTO watchlist(user_id, project_id)
-VALUES (?, ?);
+VALUES (?, ?)
+ON CONFLICT(user_id, project_id) DO NOTHING;
 --
 
 -- name: DeleteProjectFromWatchlist :exec
 DELETE FROM watchlist WHERE user_id = ? AND project_id = ?;
+--
+
+-- name: GetWatchlistProjectsOfUser :many
+SELECT p.* FROM projects AS p
+JOIN watchlist AS w
+ON p.id = w.project_id
+WHERE w.user_id = ?
+ORDER BY w.added_at DESC, w.rowid DESC;
+--
+
+-- name: DeleteWatchlistOfProject :exec
+DELETE FROM watchlist WHERE project_id = ?;
 --
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE watch_progress(
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    video_id TEXT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    position_in_secs INTEGER NOT NULL DEFAULT 0,
+    duration_in_secs INTEGER NOT NULL DEFAULT 0,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE(user_id, video_id)
+);
+
+-- +goose Down
+DROP TABLE watch_progress;
\ No newline at end of file
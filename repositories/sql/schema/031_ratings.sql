@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE ratings(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    stars INTEGER NOT NULL CHECK(stars BETWEEN 1 AND 5),
+    review TEXT NOT NULL DEFAULT '',
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE(user_id, project_id)
+);
+
+-- +goose Down
+DROP TABLE ratings;
\ No newline at end of file
@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE VIRTUAL TABLE projects_search USING fts5(
+    title,
+    description,
+    keywords,
+    content='projects',
+    content_rowid='id',
+    tokenize='unicode61 remove_diacritics 2',
+    prefix='2 3'
+);
+
+INSERT INTO projects_search(projects_search) VALUES('rebuild');
+
+-- +goose StatementBegin
+CREATE TRIGGER projects_search_insert AFTER INSERT ON projects BEGIN
+    INSERT INTO projects_search(rowid, title, description, keywords)
+    VALUES (new.id, new.title, new.description, new.keywords);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER projects_search_delete AFTER DELETE ON projects BEGIN
+    INSERT INTO projects_search(projects_search, rowid, title, description, keywords)
+    VALUES ('delete', old.id, old.title, old.description, old.keywords);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER projects_search_update AFTER UPDATE OF title, description, keywords ON projects BEGIN
+    INSERT INTO projects_search(projects_search, rowid, title, description, keywords)
+    VALUES ('delete', old.id, old.title, old.description, old.keywords);
+    INSERT INTO projects_search(rowid, title, description, keywords)
+    VALUES (new.id, new.title, new.description, new.keywords);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER projects_search_update;
+DROP TRIGGER projects_search_delete;
+DROP TRIGGER projects_search_insert;
+DROP TABLE projects_search;
\ No newline at end of file
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE seasons(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL DEFAULT 0,
+    title TEXT NOT NULL DEFAULT '',
+    synopsis TEXT NOT NULL DEFAULT '',
+    UNIQUE(project_id, number)
+);
+
+CREATE TABLE episodes(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL DEFAULT 0,
+    title TEXT NOT NULL DEFAULT '',
+    synopsis TEXT NOT NULL DEFAULT '',
+    duration_in_secs INTEGER NOT NULL DEFAULT 0,
+    release_date TEXT NOT NULL DEFAULT '',
+    thumbnail TEXT REFERENCES images(id) ON DELETE SET NULL,
+    video_id TEXT REFERENCES videos(id) ON DELETE SET NULL,
+    UNIQUE(season_id, number)
+);
+
+INSERT INTO seasons(project_id, number)
+SELECT DISTINCT project_id, season FROM videos;
+
+INSERT INTO episodes(season_id, number, video_id)
+SELECT s.id, v.serie, v.id FROM videos AS v
+JOIN seasons AS s
+ON s.project_id = v.project_id AND s.number = v.season;
+
+-- +goose Down
+DROP TABLE episodes;
+DROP TABLE seasons;
\ No newline at end of file
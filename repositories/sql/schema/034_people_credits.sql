@@ -0,0 +1,53 @@
+-- +goose Up
+CREATE TABLE people(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name TEXT NOT NULL,
+    photo TEXT NOT NULL DEFAULT '',
+    bio TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE credits(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('director', 'producer', 'writer', 'actor')),
+    character TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(project_id, person_id, role, character)
+);
+
+CREATE INDEX credits_person_id ON credits(person_id);
+
+-- director and producer of projects are lists of names separated by commas
+CREATE TEMP TABLE names_of_projects AS
+WITH RECURSIVE split(project_id, role, position, name, rest) AS (
+    SELECT id, 'director', 0, '', director || ',' FROM projects
+    UNION ALL
+    SELECT id, 'producer', 0, '', producer || ',' FROM projects
+    UNION ALL
+    SELECT project_id, role, position + 1,
+        TRIM(SUBSTR(rest, 1, INSTR(rest, ',') - 1)),
+        SUBSTR(rest, INSTR(rest, ',') + 1)
+    FROM split
+    WHERE rest != ''
+)
+SELECT project_id, role, position, name FROM split WHERE name != '';
+
+INSERT INTO people(name)
+SELECT DISTINCT name FROM names_of_projects;
+
+INSERT OR IGNORE INTO credits(project_id, person_id, role, position)
+SELECT n.project_id, MIN(p.id), n.role, n.position FROM names_of_projects AS n
+JOIN people AS p
+ON p.name = n.name
+GROUP BY n.project_id, n.name, n.role;
+
+DROP TABLE names_of_projects;
+
+-- +goose Down
+DROP INDEX credits_person_id;
+DROP TABLE credits;
+DROP TABLE people;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- +goose Up
+-- status of HLS packaging: pending, processing, ready or failed
+ALTER TABLE videos ADD COLUMN hls_status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE videos ADD COLUMN hls_error TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE videos DROP COLUMN hls_error;
+ALTER TABLE videos DROP COLUMN hls_status;
\ No newline at end of file
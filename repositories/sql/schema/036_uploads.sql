@@ -0,0 +1,17 @@
+-- +goose Up
+-- resumable uploads of the tus protocol, the received bytes are kept on the local disk
+CREATE TABLE uploads(
+    id TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TEXT NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    length INTEGER NOT NULL,
+    received INTEGER NOT NULL DEFAULT 0,
+    -- Upload-Metadata header
+    metadata TEXT NOT NULL DEFAULT '',
+    -- the video or image created of the completed upload
+    media_id TEXT NOT NULL DEFAULT ''
+);
+
+-- +goose Down
+DROP TABLE uploads;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN width INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE images ADD COLUMN height INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN height;
+ALTER TABLE images DROP COLUMN width;
\ No newline at end of file
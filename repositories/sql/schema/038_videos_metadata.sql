@@ -0,0 +1,10 @@
+-- +goose Up
+-- metadata read from the uploaded file
+ALTER TABLE videos ADD COLUMN duration_in_secs INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE videos ADD COLUMN width INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE videos ADD COLUMN height INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE videos DROP COLUMN height;
+ALTER TABLE videos DROP COLUMN width;
+ALTER TABLE videos DROP COLUMN duration_in_secs;
\ No newline at end of file
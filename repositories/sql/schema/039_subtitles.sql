@@ -0,0 +1,16 @@
+-- +goose Up
+-- subtitle tracks of videos, the files are stored as WebVTT
+CREATE TABLE subtitles(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    video_id TEXT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    language TEXT NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    file TEXT NOT NULL,
+    UNIQUE(video_id, language, label)
+);
+
+-- +goose Down
+DROP TABLE subtitles;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- +goose Up
+-- a sign in starts a family of refresh tokens, every refresh rotates
+-- the token to a new one of the family and sets replaced_by of the old one
+ALTER TABLE refresh_tokens ADD COLUMN family TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN replaced_by TEXT;
+UPDATE refresh_tokens SET family = token;
+CREATE UNIQUE INDEX refresh_tokens_token ON refresh_tokens(token);
+CREATE INDEX refresh_tokens_family ON refresh_tokens(family);
+
+-- +goose Down
+DROP INDEX refresh_tokens_family;
+DROP INDEX refresh_tokens_token;
+ALTER TABLE refresh_tokens DROP COLUMN replaced_by;
+ALTER TABLE refresh_tokens DROP COLUMN family;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- +goose Up
+-- the device and the time of the sign in are copied to the rotated tokens,
+-- the family of the active token is a session of the user
+ALTER TABLE refresh_tokens ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN signed_in_at TEXT NOT NULL DEFAULT '';
+UPDATE refresh_tokens SET signed_in_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at);
+CREATE INDEX refresh_tokens_user_id ON refresh_tokens(user_id);
+
+-- +goose Down
+DROP INDEX refresh_tokens_user_id;
+ALTER TABLE refresh_tokens DROP COLUMN signed_in_at;
+ALTER TABLE refresh_tokens DROP COLUMN ip;
+ALTER TABLE refresh_tokens DROP COLUMN user_agent;
\ No newline at end of file
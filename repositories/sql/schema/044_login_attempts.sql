@@ -0,0 +1,12 @@
+-- +goose Up
+-- failed sign in attempts shared by the instances,
+-- last_failed_at is in unix nanoseconds
+CREATE TABLE login_attempts (
+    key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failed_at INTEGER NOT NULL
+);
+CREATE INDEX login_attempts_last_failed_at ON login_attempts(last_failed_at);
+
+-- +goose Down
+DROP TABLE login_attempts;
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- +goose Up
+-- the secret is pending until the first code confirms it, then totp_enabled_at is set;
+-- totp_counter is the period of the last accepted code, the codes are accepted once
+ALTER TABLE users ADD COLUMN totp_secret TEXT;
+ALTER TABLE users ADD COLUMN totp_enabled_at TEXT;
+ALTER TABLE users ADD COLUMN totp_counter INTEGER NOT NULL DEFAULT 0;
+
+-- the roles requiring 2FA are granted only to the users with 2FA on
+ALTER TABLE roles ADD COLUMN require_two_factor BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- only the SHA-256 of a recovery code is kept
+CREATE TABLE recovery_codes (
+    code_hash TEXT PRIMARY KEY,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    used_at TEXT
+);
+CREATE INDEX recovery_codes_user_id ON recovery_codes(user_id);
+
+-- +goose Down
+DROP TABLE recovery_codes;
+ALTER TABLE roles DROP COLUMN require_two_factor;
+ALTER TABLE users DROP COLUMN totp_counter;
+ALTER TABLE users DROP COLUMN totp_enabled_at;
+ALTER TABLE users DROP COLUMN totp_secret;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- +goose Up
+-- the received bytes are kept in the storage, a part per PATCH request;
+-- parts are the names of the parts in order, one per line
+ALTER TABLE uploads ADD COLUMN parts TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE uploads DROP COLUMN parts;
\ No newline at end of file
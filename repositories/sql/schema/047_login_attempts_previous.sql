@@ -0,0 +1,7 @@
+-- +goose Up
+-- previous_failed_at is the last_failed_at before the last attempt,
+-- it is restored when the attempt is forgiven
+ALTER TABLE login_attempts ADD COLUMN previous_failed_at INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE login_attempts DROP COLUMN previous_failed_at;
\ No newline at end of file
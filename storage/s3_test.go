@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeObject struct {
+	content []byte
+	etag    string
+	modTime time.Time
+}
+
+// fakeS3 serves a path-style bucket in memory
+// and rejects requests with wrong signatures
+type fakeS3 struct {
+	bucket  string
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.validSignature(r) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	object, exists := f.objects[key]
+
+	switch {
+	case r.Method == http.MethodPut:
+		content, _ := io.ReadAll(r.Body)
+		if int64(len(content)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		sum := md5.Sum(content)
+		f.objects[key] = fakeObject{content: content, etag: `"` + hex.EncodeToString(sum[:]) + `"`, modTime: time.Now()}
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && key == "" && r.URL.Query().Get("list-type") == "2":
+		f.list(w, r)
+	case !exists:
+		http.Error(w, "NoSuchKey", http.StatusNotFound)
+	case r.Method == http.MethodHead:
+		w.Header().Set("ETag", object.etag)
+		w.Header().Set("Last-Modified", object.modTime.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.content)))
+	case r.Method == http.MethodGet:
+		if match := r.Header.Get("If-Match"); match != "" && match != object.etag {
+			http.Error(w, "PreconditionFailed", http.StatusPreconditionFailed)
+			return
+		}
+		start := 0
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(object.content)-1, len(object.content)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		w.Write(object.content[start:])
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// list returns at most 2 keys per page to check continuation
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	keys := []string{}
+	for key := range f.objects {
+		if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	// the token is the last listed key like in S3 the listing continues after it
+	start := sort.SearchStrings(keys, r.URL.Query().Get("continuation-token"))
+	if start < len(keys) && keys[start] == r.URL.Query().Get("continuation-token") {
+		start++
+	}
+	end := min(start+2, len(keys))
+
+	type content struct {
+		Key          string
+		Size         int
+		LastModified time.Time
+	}
+	result := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Contents              []content
+		IsTruncated           bool
+		NextContinuationToken string
+	}{IsTruncated: end < len(keys)}
+	for _, key := range keys[start:end] {
+		result.Contents = append(result.Contents, content{Key: key, Size: len(f.objects[key].content), LastModified: f.objects[key].modTime})
+		result.NextContinuationToken = key
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) validSignature(r *http.Request) bool {
+	signedAt, err := time.Parse(amzDateLayout, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+	req := r.Clone(r.Context())
+	req.URL.Host = r.Host
+	req.Header = http.Header{}
+	for name, values := range r.Header {
+		if name != "Authorization" {
+			req.Header[name] = values
+		}
+	}
+	signV4(req, "key", "secret", "us-east-1", r.Header.Get("X-Amz-Content-Sha256"), signedAt)
+	return req.Header.Get("Authorization") == r.Header.Get("Authorization")
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	fake := &fakeS3{bucket: "media", objects: map[string]fakeObject{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func TestS3(t *testing.T) {
+	_, server := newFakeS3(t)
+	s, err := NewS3(S3Config{
+		Endpoint:        server.URL,
+		Bucket:          "media",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s)
+}
+
+func TestS3WrongSecret(t *testing.T) {
+	_, server := newFakeS3(t)
+	s, err := NewS3(S3Config{Endpoint: server.URL, Bucket: "media", AccessKeyID: "key", SecretAccessKey: "wrong", PathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Put(t.Context(), "video.mp4", strings.NewReader("0"), 1, "video/mp4")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Put() error = %v, want 403", err)
+	}
+}
+
+func TestS3ObjectURL(t *testing.T) {
+	s, err := NewS3(S3Config{Region: "eu-central-1", Bucket: "media"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.objectURL("hls/a b.ts", nil).String(); got != "https://media.s3.eu-central-1.amazonaws.com/hls/a%20b.ts" {
+		t.Errorf("objectURL() = %s", got)
+	}
+	s.cfg.PathStyle = true
+	if got := s.objectURL("a.mp4", nil).String(); got != "https://s3.eu-central-1.amazonaws.com/media/a.mp4" {
+		t.Errorf("objectURL() path style = %s", got)
+	}
+}
+
+// TestMinIO runs against a local MinIO when MINIO_ENDPOINT is set, e.g.
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	mc mb local/ozinshe-test
+//	MINIO_ENDPOINT=http://localhost:9000 go test ./storage/
+func TestMinIO(t *testing.T) {
+	endpoint := os.Getenv("MINIO_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("MINIO_ENDPOINT isn't set")
+	}
+	env := func(key, fallback string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return fallback
+	}
+	s, err := NewS3(S3Config{
+		Endpoint:        endpoint,
+		Bucket:          env("MINIO_BUCKET", "ozinshe-test"),
+		AccessKeyID:     env("MINIO_ACCESS_KEY", "minioadmin"),
+		SecretAccessKey: env("MINIO_SECRET_KEY", "minioadmin"),
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s)
+}
@@ -0,0 +1,118 @@
+// Package storage keeps the media files (images, videos and HLS packages)
+// on the local disk or in an S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// ErrNotExist is returned for missing objects and invalid names
+var ErrNotExist = fs.ErrNotExist
+
+// Storage keeps objects by names like "video.mp4" or "hls/video.mp4/master.m3u8"
+type Storage interface {
+	// Put writes size bytes read from r as the object, replacing the previous one
+	Put(ctx context.Context, name string, r io.Reader, size int64, contentType string) error
+	// Open returns the object for streaming reads
+	Open(ctx context.Context, name string) (Object, error)
+	// Delete removes the object, a missing object counts as removed
+	Delete(ctx context.Context, name string) error
+	// DeletePrefix removes all the objects under the prefix like "hls/video.mp4/"
+	DeletePrefix(ctx context.Context, prefix string) error
+	// List returns the objects under the prefix ordered by name,
+	// the empty prefix lists all the objects
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// ObjectInfo describes a listed object
+type ObjectInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Object is read lazily, seeking doesn't read the skipped bytes
+type Object interface {
+	io.ReadSeekCloser
+	Size() int64
+	ModTime() time.Time
+	// ETag changes whenever the object is replaced
+	ETag() string
+}
+
+// ValidName reports whether name is a slash-separated path
+// without empty, dot or hidden elements
+func ValidName(name string) bool {
+	if !fs.ValidPath(name) || name == "." {
+		return false
+	}
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+func validPrefix(prefix string) bool {
+	return strings.HasSuffix(prefix, "/") && ValidName(strings.TrimSuffix(prefix, "/"))
+}
+
+func checkListPrefix(prefix string) error {
+	if prefix != "" && !validPrefix(prefix) {
+		return errInvalidName
+	}
+	return nil
+}
+
+// Config selects the driver, Driver is "local" (default) or "s3"
+type Config struct {
+	Driver string
+	// Dir of the local driver
+	Dir string
+	S3  S3Config
+}
+
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocal(cfg.Dir), nil
+	case "s3":
+		s3, err := NewS3(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		return s3, nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// PutFile copies the file to the storage
+func PutFile(ctx context.Context, s Storage, name string, file fs.File, contentType string) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, name, file, info.Size(), contentType)
+}
+
+var errInvalidName = fmt.Errorf("invalid name: %w", ErrNotExist)
+
+func checkName(name string) error {
+	if !ValidName(name) {
+		return errInvalidName
+	}
+	return nil
+}
+
+// IsNotExist reports whether the object is missing
+func IsNotExist(err error) bool {
+	return errors.Is(err, ErrNotExist)
+}
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testStorage checks the behaviour shared by all the drivers
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+	put := func(name, content string) {
+		t.Helper()
+		if err := s.Put(ctx, name, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+			t.Fatalf("Put(%s): %v", name, err)
+		}
+	}
+	read := func(name string) string {
+		t.Helper()
+		object, err := s.Open(ctx, name)
+		if err != nil {
+			t.Fatalf("Open(%s): %v", name, err)
+		}
+		defer object.Close()
+		content, err := io.ReadAll(object)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return string(content)
+	}
+
+	put("video.mp4", "0123456789")
+	put("hls/video.mp4/master.m3u8", "#EXTM3U")
+	put("hls/video.mp4/360p/index.m3u8", "#EXTM3U 360p")
+	put("hls/video.mp4/720p/index.m3u8", "#EXTM3U 720p")
+	put("hls/other.mp4/master.m3u8", "#EXTM3U other")
+	put("empty.txt", "")
+
+	if got := read("video.mp4"); got != "0123456789" {
+		t.Errorf("video.mp4 = %q", got)
+	}
+	if got := read("hls/video.mp4/360p/index.m3u8"); got != "#EXTM3U 360p" {
+		t.Errorf("index.m3u8 = %q", got)
+	}
+	if got := read("empty.txt"); got != "" {
+		t.Errorf("empty.txt = %q", got)
+	}
+
+	list := func(prefix string) string {
+		t.Helper()
+		objects, err := s.List(ctx, prefix)
+		if err != nil {
+			t.Fatalf("List(%q): %v", prefix, err)
+		}
+		names := []string{}
+		for _, object := range objects {
+			if object.ModTime.IsZero() {
+				t.Errorf("%s has no modification time", object.Name)
+			}
+			names = append(names, fmt.Sprintf("%s:%d", object.Name, object.Size))
+		}
+		return strings.Join(names, " ")
+	}
+	if got, want := list(""), "empty.txt:0 hls/other.mp4/master.m3u8:13 hls/video.mp4/360p/index.m3u8:12 "+
+		"hls/video.mp4/720p/index.m3u8:12 hls/video.mp4/master.m3u8:7 video.mp4:10"; got != want {
+		t.Errorf("List() = %s, want %s", got, want)
+	}
+	if got, want := list("hls/video.mp4/"), "hls/video.mp4/360p/index.m3u8:12 hls/video.mp4/720p/index.m3u8:12 hls/video.mp4/master.m3u8:7"; got != want {
+		t.Errorf("List(hls/video.mp4/) = %s, want %s", got, want)
+	}
+	if got := list("hls/missing.mp4/"); got != "" {
+		t.Errorf("List() of missing prefix = %s", got)
+	}
+	for _, prefix := range []string{"/", "hls", "../"} {
+		if _, err := s.List(ctx, prefix); err == nil {
+			t.Errorf("List(%q) is allowed", prefix)
+		}
+	}
+
+	object, err := s.Open(ctx, "video.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag := object.ETag()
+	if object.Size() != 10 || etag == "" || object.ModTime().IsZero() {
+		t.Errorf("size %d, etag %q, modified %v", object.Size(), etag, object.ModTime())
+	}
+	if end, err := object.Seek(0, io.SeekEnd); err != nil || end != 10 {
+		t.Errorf("Seek(0, End) = %d, %v", end, err)
+	}
+	if _, err := object.Seek(3, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	part := make([]byte, 4)
+	if _, err := io.ReadFull(object, part); err != nil || string(part) != "3456" {
+		t.Errorf("read after seek = %q, %v", part, err)
+	}
+	if _, err := object.Seek(-2, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if rest, err := io.ReadAll(object); err != nil || string(rest) != "89" {
+		t.Errorf("read of the end = %q, %v", rest, err)
+	}
+	object.Close()
+
+	put("video.mp4", "replaced")
+	if got := read("video.mp4"); got != "replaced" {
+		t.Errorf("replaced video.mp4 = %q", got)
+	}
+
+	for _, name := range []string{"missing.mp4", "../video.mp4", "/video.mp4", ".env", "hls//x", ""} {
+		if _, err := s.Open(ctx, name); !IsNotExist(err) {
+			t.Errorf("Open(%q) error = %v, want not exist", name, err)
+		}
+	}
+
+	if err := s.Delete(ctx, "video.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "video.mp4"); err != nil {
+		t.Errorf("Delete() of missing object: %v", err)
+	}
+	if _, err := s.Open(ctx, "video.mp4"); !IsNotExist(err) {
+		t.Errorf("deleted object: %v", err)
+	}
+
+	if err := s.DeletePrefix(ctx, "hls/video.mp4/"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"hls/video.mp4/master.m3u8", "hls/video.mp4/360p/index.m3u8", "hls/video.mp4/720p/index.m3u8"} {
+		if _, err := s.Open(ctx, name); !IsNotExist(err) {
+			t.Errorf("object %s of deleted prefix: %v", name, err)
+		}
+	}
+	if got := read("hls/other.mp4/master.m3u8"); got != "#EXTM3U other" {
+		t.Errorf("object of other prefix = %q", got)
+	}
+	if err := s.DeletePrefix(ctx, "hls/missing.mp4/"); err != nil {
+		t.Errorf("DeletePrefix() of missing prefix: %v", err)
+	}
+	for _, prefix := range []string{"", "/", "hls", "../"} {
+		if err := s.DeletePrefix(ctx, prefix); err == nil {
+			t.Errorf("DeletePrefix(%q) is allowed", prefix)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	testStorage(t, NewLocal(t.TempDir()+"/"))
+}
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"video.mp4":                     true,
+		"hls/video.mp4/360p/index.m3u8": true,
+		"":                              false,
+		".":                             false,
+		".env":                          false,
+		"../video.mp4":                  false,
+		"hls/../video.mp4":              false,
+		"/video.mp4":                    false,
+		"hls/":                          false,
+		"hls/.tmp/master.m3u8":          false,
+	} {
+		if got := ValidName(name); got != want {
+			t.Errorf("ValidName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if s, err := New(Config{Dir: "/files/"}); err != nil || s.(*Local).Dir != "/files/" {
+		t.Errorf("New() default = %v, %v", s, err)
+	}
+	if _, err := New(Config{Driver: "s3"}); err == nil {
+		t.Error("New() s3 without bucket is allowed")
+	}
+	if _, err := New(Config{Driver: "ftp"}); err == nil {
+		t.Error("New() unknown driver is allowed")
+	}
+}
@@ -0,0 +1,283 @@
+// Package subtitles parses SRT and WebVTT subtitles,
+// validates the timing of their cues and converts SRT to WebVTT.
+package subtitles
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+// Formats of the subtitles
+const (
+	FormatSRT    = "srt"
+	FormatWebVTT = "vtt"
+)
+
+// ErrInvalid is returned for the files which aren't valid subtitles
+var ErrInvalid = errors.New("invalid subtitles")
+
+// Cue is a text shown from Start to End
+type Cue struct {
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	// Settings of the WebVTT cue, like position and alignment
+	Settings string
+	Text     string
+}
+
+// Prefix of the subtitle files of the video in the storage
+func Prefix(videoID string) string {
+	return "subtitles/" + videoID + "/"
+}
+
+// Remove removes the subtitle files of the video
+func Remove(ctx context.Context, store storage.Storage, videoID string) error {
+	return store.DeletePrefix(ctx, Prefix(videoID))
+}
+
+// DetectFormat returns the format by the file extension,
+// files without .srt or .vtt extension are WebVTT if they start with WEBVTT
+func DetectFormat(fileName string, data []byte) string {
+	switch {
+	case strings.HasSuffix(strings.ToLower(fileName), ".srt"):
+		return FormatSRT
+	case strings.HasSuffix(strings.ToLower(fileName), ".vtt"):
+		return FormatWebVTT
+	case bytes.HasPrefix(bytes.TrimPrefix(data, []byte("\uFEFF")), []byte("WEBVTT")):
+		return FormatWebVTT
+	}
+	return FormatSRT
+}
+
+// ToWebVTT validates the subtitles and returns them as WebVTT.
+// WebVTT is returned as is to keep its styles and regions.
+func ToWebVTT(data []byte, format string) ([]byte, error) {
+	switch format {
+	case FormatSRT:
+		cues, err := ParseSRT(data)
+		if err != nil {
+			return nil, err
+		}
+		return WriteWebVTT(cues), nil
+	case FormatWebVTT:
+		_, err := ParseWebVTT(data)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: unknown format %q", ErrInvalid, format)
+}
+
+// ParseSRT parses SubRip subtitles
+func ParseSRT(data []byte) ([]Cue, error) {
+	blocks, err := splitBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cues := []Cue{}
+	for _, block := range blocks {
+		lines := strings.Split(block, "\n")
+		// the counter of the cue is optional
+		if !strings.Contains(lines[0], "-->") {
+			if _, err := strconv.Atoi(strings.TrimSpace(lines[0])); err != nil || len(lines) < 2 {
+				return nil, fmt.Errorf("%w: cue %d: no timing in %q", ErrInvalid, len(cues)+1, lines[0])
+			}
+			lines = lines[1:]
+		}
+		start, end, _, err := parseTiming(lines[0], srtTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cue %d: %v", ErrInvalid, len(cues)+1, err)
+		}
+		cues = append(cues, Cue{
+			Start: start,
+			End:   end,
+			Text:  srtText(strings.Join(lines[1:], "\n")),
+		})
+	}
+	return cues, validate(cues)
+}
+
+// ParseWebVTT parses WebVTT subtitles skipping the notes, styles and regions
+func ParseWebVTT(data []byte) ([]Cue, error) {
+	blocks, err := splitBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 || !isWebVTTHeader(strings.SplitN(blocks[0], "\n", 2)[0]) {
+		return nil, fmt.Errorf("%w: no WEBVTT header", ErrInvalid)
+	}
+
+	cues := []Cue{}
+	for _, block := range blocks[1:] {
+		lines := strings.Split(block, "\n")
+		if isBlock(lines[0], "NOTE") || isBlock(lines[0], "STYLE") || isBlock(lines[0], "REGION") {
+			continue
+		}
+		cue := Cue{}
+		if !strings.Contains(lines[0], "-->") {
+			if len(lines) < 2 {
+				return nil, fmt.Errorf("%w: cue %d: no timing in %q", ErrInvalid, len(cues)+1, lines[0])
+			}
+			cue.ID = lines[0]
+			lines = lines[1:]
+		}
+		cue.Start, cue.End, cue.Settings, err = parseTiming(lines[0], vttTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cue %d: %v", ErrInvalid, len(cues)+1, err)
+		}
+		cue.Text = strings.Join(lines[1:], "\n")
+		cues = append(cues, cue)
+	}
+	return cues, validate(cues)
+}
+
+// WriteWebVTT encodes the cues as WebVTT
+func WriteWebVTT(cues []Cue) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("WEBVTT\n")
+	for _, cue := range cues {
+		buf.WriteString("\n")
+		if cue.ID != "" {
+			buf.WriteString(cue.ID + "\n")
+		}
+		buf.WriteString(formatTimestamp(cue.Start) + " --> " + formatTimestamp(cue.End))
+		if cue.Settings != "" {
+			buf.WriteString(" " + cue.Settings)
+		}
+		buf.WriteString("\n")
+		if cue.Text != "" {
+			buf.WriteString(cue.Text + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// blankLine separates the blocks, it may have spaces
+var blankLine = regexp.MustCompile(`\n[ \t]*\n`)
+
+// splitBlocks splits the UTF-8 text into the blocks separated by blank lines
+func splitBlocks(data []byte) ([]string, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: not UTF-8 text", ErrInvalid)
+	}
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	blocks := []string{}
+	for _, block := range blankLine.Split(text, -1) {
+		block = strings.Trim(block, "\n")
+		if strings.TrimSpace(block) != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("%w: empty file", ErrInvalid)
+	}
+	return blocks, nil
+}
+
+func isWebVTTHeader(line string) bool {
+	return line == "WEBVTT" || strings.HasPrefix(line, "WEBVTT ") || strings.HasPrefix(line, "WEBVTT\t")
+}
+
+func isBlock(line, name string) bool {
+	return line == name || strings.HasPrefix(line, name+" ") || strings.HasPrefix(line, name+"\t")
+}
+
+var (
+	// 00:01:02,345, a dot is accepted too
+	srtTimestamp = regexp.MustCompile(`^(\d+):(\d{2}):(\d{2})[,.](\d{3})$`)
+	// 01:02.345 or 00:01:02.345
+	vttTimestamp = regexp.MustCompile(`^(?:(\d+):)?(\d{2}):(\d{2})\.(\d{3})$`)
+)
+
+// parseTiming parses "start --> end settings"
+func parseTiming(line string, timestamp *regexp.Regexp) (start, end time.Duration, settings string, err error) {
+	from, rest, ok := strings.Cut(line, "-->")
+	if !ok {
+		return 0, 0, "", fmt.Errorf("no timing in %q", line)
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return 0, 0, "", fmt.Errorf("no end in %q", line)
+	}
+	start, err = parseTimestamp(strings.TrimSpace(from), timestamp)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	end, err = parseTimestamp(fields[0], timestamp)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	// SRT coordinates X1:… Y2:… aren't supported by WebVTT
+	if timestamp == vttTimestamp {
+		settings = strings.Join(fields[1:], " ")
+	}
+	return start, end, settings, nil
+}
+
+func parseTimestamp(s string, timestamp *regexp.Regexp) (time.Duration, error) {
+	match := timestamp.FindStringSubmatch(s)
+	if match == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	hours, _ := strconv.Atoi("0" + match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+	millis, _ := strconv.Atoi(match[4])
+	if minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second + time.Duration(millis)*time.Millisecond, nil
+}
+
+func formatTimestamp(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		d/time.Hour, d/time.Minute%60, d/time.Second%60, d/time.Millisecond%1000)
+}
+
+// validate checks that every cue ends after it starts
+// and the cues are ordered by the start
+func validate(cues []Cue) error {
+	if len(cues) == 0 {
+		return fmt.Errorf("%w: no cues", ErrInvalid)
+	}
+	for i, cue := range cues {
+		if cue.End <= cue.Start {
+			return fmt.Errorf("%w: cue %d ends at %s before it starts at %s", ErrInvalid, i+1, formatTimestamp(cue.End), formatTimestamp(cue.Start))
+		}
+		if i > 0 && cue.Start < cues[i-1].Start {
+			return fmt.Errorf("%w: cue %d starts at %s before the previous cue", ErrInvalid, i+1, formatTimestamp(cue.Start))
+		}
+	}
+	return nil
+}
+
+var (
+	// <font color="…"> isn't supported by WebVTT
+	srtFontTag = regexp.MustCompile(`(?i)</?font[^>]*>`)
+	// {\an8} positioning of SubStation Alpha
+	srtOverride = regexp.MustCompile(`\{\\[^}]*\}`)
+)
+
+// srtText converts the text of the SRT cue to WebVTT,
+// "-->" isn't allowed in WebVTT cues
+func srtText(text string) string {
+	text = srtFontTag.ReplaceAllString(text, "")
+	text = srtOverride.ReplaceAllString(text, "")
+	return strings.ReplaceAll(text, "-->", "--&gt;")
+}
@@ -0,0 +1,115 @@
+package subtitles
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/storage"
+)
+
+func TestToWebVTT(t *testing.T) {
+	srt := "\uFEFF1\r\n00:00:01,000 --> 00:00:02,500\r\n<font color=\"red\">Сәлем</font>\r\n\r\n" +
+		"2\r\n00:00:03,000 --> 00:01:04,005 X1:10 Y1:20\r\n{\\an8}first line\r\nsecond --> line\r\n"
+	vtt := "WEBVTT - Ozinshe\n\nNOTE a comment\n\nSTYLE\n::cue { color: yellow }\n\n" +
+		"intro\n00:01.000 --> 00:02.500 align:start\nHello\n\n00:00:03.000 --> 00:00:04.000\nBye\n"
+
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "SRT",
+			data:   srt,
+			format: FormatSRT,
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nСәлем\n\n" +
+				"00:00:03.000 --> 00:01:04.005\nfirst line\nsecond --&gt; line\n",
+		},
+		{name: "WebVTT as is", data: vtt, format: FormatWebVTT, want: vtt},
+		{name: "SRT without counters", data: "00:00:01.000 --> 00:00:02.000\nA\n", format: FormatSRT, want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nA\n"},
+		{name: "Empty", data: "\n\n", format: FormatSRT, wantErr: "empty"},
+		{name: "Not UTF-8", data: "1\n00:00:01,000 --> 00:00:02,000\n\xff\xfe\n", format: FormatSRT, wantErr: "UTF-8"},
+		{name: "Ends before start", data: "1\n00:00:05,000 --> 00:00:02,000\nA\n", format: FormatSRT, wantErr: "cue 1 ends"},
+		{name: "Out of order", data: "1\n00:00:05,000 --> 00:00:06,000\nA\n\n2\n00:00:01,000 --> 00:00:02,000\nB\n", format: FormatSRT, wantErr: "cue 2 starts"},
+		{name: "Invalid timestamp", data: "1\n00:00:61,000 --> 00:01:02,000\nA\n", format: FormatSRT, wantErr: "cue 1: invalid timestamp"},
+		{name: "No timing", data: "1\nA\n", format: FormatSRT, wantErr: "cue 1"},
+		{name: "No header", data: "00:00:01.000 --> 00:00:02.000\nA\n", format: FormatWebVTT, wantErr: "WEBVTT"},
+		{name: "No cues", data: "WEBVTT\n\nNOTE nothing\n", format: FormatWebVTT, wantErr: "no cues"},
+		{name: "Unknown format", data: "text", format: "ass", wantErr: "unknown format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToWebVTT([]byte(tt.data), tt.format)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ToWebVTT() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ToWebVTT() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWebVTT(t *testing.T) {
+	cues, err := ParseWebVTT([]byte("WEBVTT\n\nintro\n01:01.000 --> 1:00:00.000 line:0\nHello\nworld\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Cue{ID: "intro", Start: time.Minute + time.Second, End: time.Hour, Settings: "line:0", Text: "Hello\nworld"}
+	if len(cues) != 1 || cues[0] != want {
+		t.Errorf("ParseWebVTT() = %+v, want %+v", cues, want)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		fileName string
+		data     string
+		want     string
+	}{
+		{fileName: "movie.srt", data: "WEBVTT", want: FormatSRT},
+		{fileName: "movie.VTT", data: "1", want: FormatWebVTT},
+		{fileName: "movie.txt", data: "\uFEFFWEBVTT\n", want: FormatWebVTT},
+		{fileName: "movie.txt", data: "1\n", want: FormatSRT},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat(tt.fileName, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectFormat(%q, %q) = %q, want %q", tt.fileName, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewLocal(t.TempDir())
+	for _, name := range []string{Prefix("video") + "kk.vtt", Prefix("video2") + "kk.vtt"} {
+		if err := store.Put(ctx, name, strings.NewReader("WEBVTT\n"), 7, "text/vtt"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Remove(ctx, store, "video"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Open(ctx, Prefix("video")+"kk.vtt"); !storage.IsNotExist(err) {
+		t.Errorf("subtitles of the video aren't removed: %v", err)
+	}
+	object, err := store.Open(ctx, Prefix("video2")+"kk.vtt")
+	if err != nil {
+		t.Fatalf("subtitles of another video are removed: %v", err)
+	}
+	object.Close()
+}
@@ -0,0 +1,86 @@
+package throttle
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+)
+
+// Database keeps the attempts in the login_attempts table
+// shared by the instances of the server
+type Database struct {
+	DB *database.Queries
+}
+
+func NewDatabase(db *database.Queries) *Database {
+	return &Database{
+		DB: db,
+	}
+}
+
+func (d *Database) Get(ctx context.Context, key string) (Attempts, error) {
+	row, err := d.DB.GetLoginAttempts(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Attempts{}, nil
+	}
+	if err != nil {
+		return Attempts{}, err
+	}
+	return Attempts{
+		Failures:   int(row.Failures),
+		LastFailed: time.Unix(0, row.LastFailedAt),
+	}, nil
+}
+
+func (d *Database) Attempt(ctx context.Context, key string, now time.Time, policy Policy) (time.Duration, error) {
+	// the forgotten attempts of all the keys are removed,
+	// so the failures of the key start over after the window
+	err := d.DB.DeleteStaleLoginAttempts(ctx, now.Add(-policy.Window).UnixNano())
+	if err != nil {
+		return 0, err
+	}
+
+	// the attempt is counted only if no other one was counted since the failures were read,
+	// otherwise they are read again
+	for {
+		var counted int64
+		row, err := d.DB.GetLoginAttempts(ctx, key)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			counted, err = d.DB.AddFirstLoginAttempt(ctx, database.AddFirstLoginAttemptParams{
+				Key:          key,
+				LastFailedAt: now.UnixNano(),
+			})
+		case err == nil:
+			attempts := Attempts{
+				Failures:   int(row.Failures),
+				LastFailed: time.Unix(0, row.LastFailedAt),
+			}
+			if wait := policy.wait(attempts, now); wait > 0 {
+				return wait, nil
+			}
+			counted, err = d.DB.AddLoginAttempt(ctx, database.AddLoginAttemptParams{
+				LastFailedAt: now.UnixNano(),
+				Key:          key,
+				Failures:     row.Failures,
+			})
+		}
+		if err != nil {
+			return 0, err
+		}
+		if counted == 1 {
+			return 0, nil
+		}
+	}
+}
+
+func (d *Database) Forgive(ctx context.Context, key string) error {
+	return d.DB.ForgiveLoginAttempt(ctx, key)
+}
+
+func (d *Database) Reset(ctx context.Context, key string) error {
+	return d.DB.DeleteLoginAttempts(ctx, key)
+}
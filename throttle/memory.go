@@ -0,0 +1,74 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps the attempts of a single instance
+type Memory struct {
+	mu       sync.Mutex
+	attempts map[string]Attempts
+	// swept is the time the forgotten attempts were last removed
+	swept time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		attempts: map[string]Attempts{},
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (Attempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.attempts[key], nil
+}
+
+func (m *Memory) Attempt(ctx context.Context, key string, now time.Time, policy Policy) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the map doesn't grow with the attempts of the past windows
+	if now.Sub(m.swept) > policy.Window {
+		for k, attempts := range m.attempts {
+			if now.Sub(attempts.LastFailed) > policy.Window {
+				delete(m.attempts, k)
+			}
+		}
+		m.swept = now
+	}
+
+	attempts := m.attempts[key]
+	if wait := policy.wait(attempts, now); wait > 0 {
+		return wait, nil
+	}
+	if now.Sub(attempts.LastFailed) > policy.Window {
+		attempts.Failures = 0
+	}
+	attempts.Failures++
+	attempts.previousFailed = attempts.LastFailed
+	attempts.LastFailed = now
+	m.attempts[key] = attempts
+	return 0, nil
+}
+
+func (m *Memory) Forgive(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempts, ok := m.attempts[key]
+	if ok && attempts.Failures > 0 {
+		attempts.Failures--
+		attempts.LastFailed = attempts.previousFailed
+		m.attempts[key] = attempts
+	}
+	return nil
+}
+
+func (m *Memory) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package throttle slows down the guessing of the passwords:
+// every failed attempt beyond the free ones doubles the wait
+// before the next attempt of the same account or address,
+// up to a temporary lockout. An attempt is counted before it is made
+// and forgiven when it succeeds, so the concurrent attempts can't pass the limit.
+package throttle
+
+import (
+	"context"
+	"time"
+)
+
+// Attempts are the recent failures of a key,
+// the attempts in progress count as failures
+type Attempts struct {
+	Failures   int
+	LastFailed time.Time
+	// previousFailed is restored when the last attempt is forgiven
+	previousFailed time.Time
+}
+
+// Store keeps the attempts by key, like "account:user@test.com" or "ip:192.0.2.1"
+type Store interface {
+	// Get returns the attempts of the key, zero for the unknown keys
+	Get(ctx context.Context, key string) (Attempts, error)
+	// Attempt counts an attempt at now unless the policy makes the key wait,
+	// then the wait is returned; the check and the count are atomic.
+	// The failures before now-window are forgotten.
+	Attempt(ctx context.Context, key string, now time.Time, policy Policy) (time.Duration, error)
+	// Forgive uncounts the last attempt which succeeded, the wait is as if it wasn't made
+	Forgive(ctx context.Context, key string) error
+	// Reset forgets the failures of the key
+	Reset(ctx context.Context, key string) error
+}
+
+type Policy struct {
+	// Free failures don't delay the next attempt
+	Free int
+	// Delay after the first failure beyond the free ones, doubled by every next one
+	Delay time.Duration
+	// Lockout is the longest delay
+	Lockout time.Duration
+	// Window forgets the failures older than it
+	Window time.Duration
+}
+
+// Limiter applies the policy to the keys with the prefix
+type Limiter struct {
+	Store  Store
+	Prefix string
+	Policy Policy
+	now    func() time.Time
+}
+
+func NewLimiter(store Store, prefix string, policy Policy) *Limiter {
+	return &Limiter{
+		Store:  store,
+		Prefix: prefix,
+		Policy: policy,
+		now:    time.Now,
+	}
+}
+
+// delay after the failures
+func (p Policy) delay(failures int) time.Duration {
+	if failures <= p.Free {
+		return 0
+	}
+	delay := p.Delay
+	for i := p.Free + 1; i < failures && delay < p.Lockout; i++ {
+		delay *= 2
+	}
+	return min(delay, p.Lockout)
+}
+
+// wait before the next attempt after the attempts
+func (p Policy) wait(attempts Attempts, now time.Time) time.Duration {
+	if attempts.Failures == 0 || now.Sub(attempts.LastFailed) > p.Window {
+		return 0
+	}
+	wait := attempts.LastFailed.Add(p.delay(attempts.Failures)).Sub(now)
+	return max(wait, 0)
+}
+
+// Wait returns how long the key has to wait before the next attempt, zero if it can try now
+func (l *Limiter) Wait(ctx context.Context, key string) (time.Duration, error) {
+	attempts, err := l.Store.Get(ctx, l.Prefix+key)
+	if err != nil {
+		return 0, err
+	}
+	return l.Policy.wait(attempts, l.now()), nil
+}
+
+// Attempt counts an attempt of the key before it is made as a failure,
+// it returns how long the key has to wait instead, zero if the attempt is counted
+func (l *Limiter) Attempt(ctx context.Context, key string) (time.Duration, error) {
+	return l.Store.Attempt(ctx, l.Prefix+key, l.now(), l.Policy)
+}
+
+// Forgive uncounts the attempt of the key which succeeded
+func (l *Limiter) Forgive(ctx context.Context, key string) error {
+	return l.Store.Forgive(ctx, l.Prefix+key)
+}
+
+// Reset unlocks the key
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.Store.Reset(ctx, l.Prefix+key)
+}
@@ -0,0 +1,217 @@
+package throttle
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var testPolicy = Policy{
+	Free:    3,
+	Delay:   time.Second,
+	Lockout: 10 * time.Second,
+	Window:  time.Hour,
+}
+
+func TestPolicyDelay(t *testing.T) {
+	for failures, want := range map[int]time.Duration{
+		0: 0,
+		3: 0,
+		4: time.Second,
+		5: 2 * time.Second,
+		6: 4 * time.Second,
+		7: 8 * time.Second,
+		8: 10 * time.Second,
+		// no overflow of the doubling
+		1000: 10 * time.Second,
+	} {
+		if got := testPolicy.delay(failures); got != want {
+			t.Errorf("delay(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+var stores = map[string]func(t *testing.T) Store{
+	"memory":   func(t *testing.T) Store { return NewMemory() },
+	"database": func(t *testing.T) Store { return newDatabase(t) },
+}
+
+func newDatabase(t *testing.T) *Database {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "throttle.db")+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for _, name := range []string{"044_login_attempts.sql", "047_login_attempts_previous.sql"} {
+		migration, err := os.ReadFile(filepath.Join("../repositories/sql/schema", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, _, _ := strings.Cut(string(migration), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewDatabase(database.New(db))
+}
+
+func TestLimiter(t *testing.T) {
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			limiter := NewLimiter(store(t), "account:", testPolicy)
+			limiter.now = func() time.Time { return now }
+			other := NewLimiter(limiter.Store, "ip:", testPolicy)
+			other.now = limiter.now
+
+			wait := func(key string) time.Duration {
+				t.Helper()
+				wait, err := limiter.Wait(ctx, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return wait
+			}
+			attempt := func(key string, times int) time.Duration {
+				t.Helper()
+				var wait time.Duration
+				for range times {
+					var err error
+					wait, err = limiter.Attempt(ctx, key)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				return wait
+			}
+
+			if got := attempt("user", 4); got != 0 {
+				t.Errorf("attempt after free failures = %s, want 0", got)
+			}
+			if got := wait("user"); got != time.Second {
+				t.Errorf("wait after 4 failures = %s, want 1s", got)
+			}
+			// a waiting attempt isn't counted
+			if got := attempt("user", 3); got != time.Second {
+				t.Errorf("attempt while waiting = %s, want 1s", got)
+			}
+			now = now.Add(time.Second)
+			if got := attempt("user", 1); got != 0 {
+				t.Errorf("attempt after the delay = %s, want 0", got)
+			}
+			if got := wait("user"); got != 2*time.Second {
+				t.Errorf("wait after 5 failures = %s, want 2s", got)
+			}
+			now = now.Add(500 * time.Millisecond)
+			if got := wait("user"); got != 1500*time.Millisecond {
+				t.Errorf("wait after 500ms = %s, want 1.5s", got)
+			}
+			now = now.Add(2 * time.Second)
+			if got := wait("user"); got != 0 {
+				t.Errorf("wait after the delay = %s, want 0", got)
+			}
+
+			// the keys and the prefixes are apart
+			if got := wait("other"); got != 0 {
+				t.Errorf("wait of other key = %s, want 0", got)
+			}
+			if got, err := other.Wait(ctx, "user"); err != nil || got != 0 {
+				t.Errorf("wait of other prefix = %s, %v, want 0", got, err)
+			}
+
+			for range 10 {
+				now = now.Add(testPolicy.Lockout)
+				if got := attempt("user", 1); got != 0 {
+					t.Fatalf("attempt after the lockout = %s, want 0", got)
+				}
+			}
+			if got := wait("user"); got != testPolicy.Lockout {
+				t.Errorf("wait after 15 failures = %s, want lockout", got)
+			}
+			if err := limiter.Reset(ctx, "user"); err != nil {
+				t.Fatal(err)
+			}
+			if got := wait("user"); got != 0 {
+				t.Errorf("wait after reset = %s, want 0", got)
+			}
+
+			// the succeeded attempt is uncounted
+			attempt("user", 4)
+			if err := limiter.Forgive(ctx, "user"); err != nil {
+				t.Fatal(err)
+			}
+			if got := wait("user"); got != 0 {
+				t.Errorf("wait after forgiven failure = %s, want 0", got)
+			}
+			// and it doesn't delay the next one
+			attempt("user", 2)
+			now = now.Add(time.Second)
+			attempt("user", 1)
+			if err := limiter.Forgive(ctx, "user"); err != nil {
+				t.Fatal(err)
+			}
+			if got := wait("user"); got != 0 {
+				t.Errorf("wait after forgiven delayed failure = %s, want 0", got)
+			}
+
+			// the failures of the past window are forgotten
+			now = now.Add(testPolicy.Window + time.Second)
+			if got := wait("user"); got != 0 {
+				t.Errorf("wait after the window = %s, want 0", got)
+			}
+			if got := attempt("user", 4); got != 0 {
+				t.Errorf("attempt after the window = %s, want 0", got)
+			}
+			if got := wait("user"); got != time.Second {
+				t.Errorf("wait after 4 new failures = %s, want 1s", got)
+			}
+		})
+	}
+}
+
+func TestLimiterConcurrentAttempts(t *testing.T) {
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			limiter := NewLimiter(store(t), "account:", testPolicy)
+			for range testPolicy.Free {
+				if _, err := limiter.Attempt(ctx, "user"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// only one of the attempts is counted, the others have to wait for it
+			var counted atomic.Int32
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					wait, err := limiter.Attempt(ctx, "user")
+					if err != nil {
+						t.Error(err)
+					}
+					if wait == 0 {
+						counted.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+			if got := counted.Load(); got != 1 {
+				t.Errorf("counted attempts = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the test vectors of RFC 6238
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestHOTPVectors(t *testing.T) {
+	key, err := decodeSecret(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for unix, want := range map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	} {
+		if got := hotp(key, Counter(time.Unix(unix, 0)), 8); got != want {
+			t.Errorf("hotp at %d = %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := Code(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "050471" {
+		t.Fatalf("Code() = %s, want 050471", code)
+	}
+
+	counter, ok := Validate(rfcSecret, code, now, 0)
+	if !ok || counter != Counter(now) {
+		t.Fatalf("Validate() = %d, %v, want the counter of now", counter, ok)
+	}
+	// the code of the previous period is still accepted, but once
+	if _, ok := Validate(rfcSecret, code, now.Add(Period), 0); !ok {
+		t.Error("code of previous period is rejected")
+	}
+	if _, ok := Validate(rfcSecret, code, now, counter); ok {
+		t.Error("used code is accepted")
+	}
+	for _, tt := range []struct {
+		name string
+		code string
+		at   time.Time
+	}{
+		{"expired", code, now.Add(2 * Period)},
+		{"wrong", "000000", now},
+		{"short", code[1:], now},
+	} {
+		if _, ok := Validate(rfcSecret, tt.code, tt.at, 0); ok {
+			t.Errorf("%s code is accepted", tt.name)
+		}
+	}
+	if _, ok := Validate("not base32!", code, now, 0); ok {
+		t.Error("code of invalid secret is accepted")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret %q, want 32 base32 characters", secret)
+	}
+	if _, err := Code(secret, time.Now()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("Ozinshe", "admin@admin.com", "JBSWY3DPEHPK3PXP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Ozinshe:admin@admin.com" {
+		t.Errorf("URI %s", uri)
+	}
+	query := uri.Query()
+	if query.Get("secret") != "JBSWY3DPEHPK3PXP" || query.Get("issuer") != "Ozinshe" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("query %v", query)
+	}
+}
@@ -7,3 +7,13 @@ type ResponseIdStr struct {
 type ImageIdRequest struct {
 	ImageId string `json:"image_id"`
 }
+
+// SignedURLResponse is a URL of the media for <video> and <img> tags,
+// it works without the Authorization header until expires_at
+type SignedURLResponse struct {
+	URL string `json:"url"`
+	// HlsURL is the master playlist of a video, its playlists and segments
+	// are loaded by the relative URLs under the same signed prefix
+	HlsURL    string `json:"hls_url,omitempty"`
+	ExpiresAt string `json:"expires_at"`
+}
@@ -0,0 +1,49 @@
+package views
+
+// Person is returned with the projects the person took part in
+type Person struct {
+	ID          int64              `json:"id"`
+	CreatedAt   string             `json:"created_at"`
+	UpdatedAt   string             `json:"updated_at"`
+	Name        string             `json:"name"`
+	Photo       string             `json:"photo"`
+	Bio         string             `json:"bio"`
+	Filmography []FilmographyEntry `json:"filmography"`
+}
+
+type FilmographyEntry struct {
+	CreditID  int64   `json:"credit_id"`
+	Role      string  `json:"role"`
+	Character string  `json:"character"`
+	Project   Project `json:"project"`
+}
+
+// Credit is a person taking part in a project,
+// role is one of director, writer, producer and actor
+type Credit struct {
+	ID        int64  `json:"id"`
+	PersonID  int64  `json:"person_id"`
+	Name      string `json:"name"`
+	Photo     string `json:"photo"`
+	Role      string `json:"role"`
+	Character string `json:"character"`
+	Position  int64  `json:"position"`
+}
+
+type CreatePersonRequest struct {
+	Name string `json:"name"`
+	Bio  string `json:"bio"`
+}
+
+type UpdatePersonRequest struct {
+	Name string `json:"name"`
+	Bio  string `json:"bio"`
+}
+
+// AddCreditRequest has character for actors only
+type AddCreditRequest struct {
+	PersonID  int64  `json:"person_id"`
+	Role      string `json:"role"`
+	Character string `json:"character"`
+	Position  int64  `json:"position"`
+}
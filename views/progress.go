@@ -0,0 +1,25 @@
+package views
+
+import (
+	"github.com/Bayan2019/go-ozinshe/repositories/database"
+)
+
+type SaveProgressRequest struct {
+	PositionInSecs int64 `json:"position_in_secs"`
+	DurationInSecs int64 `json:"duration_in_secs"`
+	Completed      bool  `json:"completed"`
+}
+
+type WatchProgress struct {
+	VideoID        string `json:"video_id"`
+	UpdatedAt      string `json:"updated_at"`
+	PositionInSecs int64  `json:"position_in_secs"`
+	DurationInSecs int64  `json:"duration_in_secs"`
+	Completed      bool   `json:"completed"`
+}
+
+type ContinueWatching struct {
+	Project  Project        `json:"project"`
+	Video    database.Video `json:"video"`
+	Progress WatchProgress  `json:"progress"`
+}
@@ -1,6 +1,8 @@
 package views
 
 import (
+	"database/sql"
+
 	"github.com/Bayan2019/go-ozinshe/repositories/database"
 )
 
@@ -28,14 +30,18 @@ type Project struct {
 	Type           database.Type          `json:"type"`
 	DurationInMins int64                  `json:"duration_in_mins"`
 	ReleaseYear    int64                  `json:"release_year"`
-	Director       string                 `json:"director"`
-	Producer       string                 `json:"producer"`
+	Director       string                 `json:"director"` // names of the director credits
+	Producer       string                 `json:"producer"` // names of the producer credits
 	Keywords       string                 `json:"keywords"`
 	Cover          database.Image         `json:"cover"`
+	Rating         RatingStats            `json:"rating"`
 	Genres         []database.Genre       `json:"genres"`
 	AgeCategories  []database.AgeCategory `json:"age_categories"`
 	Images         []database.Image       `json:"images"`
 	Videos         []database.Video       `json:"videos"`
+	Subtitles      []Subtitle             `json:"subtitles"`
+	Seasons        []Season               `json:"seasons"`
+	Credits        []Credit               `json:"credits"`
 }
 
 type CreateProjectRequest struct {
@@ -44,8 +50,8 @@ type CreateProjectRequest struct {
 	TypeID         int64   `json:"type_id"`
 	DurationInMins int64   `json:"duration_in_mins"`
 	ReleaseYear    int64   `json:"release_year"`
-	Director       string  `json:"director"`
-	Producer       string  `json:"producer"`
+	Director       string  `json:"director"` // deprecated: ignored, add the director credits instead
+	Producer       string  `json:"producer"` // deprecated: ignored, add the producer credits instead
 	Keywords       string  `json:"keywords"`
 	GenreIds       []int64 `json:"genre_ids"`
 	AgeCategoryIds []int64 `json:"age_category_ids"`
@@ -57,9 +63,40 @@ type UpdateProjectRequest struct {
 	TypeID         int64   `json:"type_id"`
 	DurationInMins int64   `json:"duration_in_mins"`
 	ReleaseYear    int64   `json:"release_year"`
-	Director       string  `json:"director"`
-	Producer       string  `json:"producer"`
+	Director       string  `json:"director"` // deprecated: ignored, add the director credits instead
+	Producer       string  `json:"producer"` // deprecated: ignored, add the producer credits instead
 	Keywords       string  `json:"keywords"`
 	GenreIds       []int64 `json:"genre_ids"`
 	AgeCategoryIds []int64 `json:"age_category_ids"`
 }
+
+// ProjectsFilter narrows, orders and pages the list of projects,
+// unset filters are NULL
+type ProjectsFilter struct {
+	TypeID        sql.NullInt64
+	GenreID       sql.NullInt64
+	AgeCategoryID sql.NullInt64
+	YearFrom      sql.NullInt64
+	YearTo        sql.NullInt64
+	SortBy        string
+	Descending    bool
+	Limit         int64
+	Offset        int64
+}
+
+// ProjectSearchResult is a found project,
+// matched words are wrapped in <mark></mark> and the rest of the text is HTML-escaped
+type ProjectSearchResult struct {
+	Project
+	TitleHighlight     string `json:"title_highlight"`
+	DescriptionSnippet string `json:"description_snippet"`
+}
+
+// DeletedProject reports what was removed with the project,
+// files that couldn't be removed are listed in failed_files
+type DeletedProject struct {
+	ID          int64    `json:"id"`
+	Images      []string `json:"images"`
+	Videos      []string `json:"videos"`
+	FailedFiles []string `json:"failed_files"`
+}
@@ -0,0 +1,27 @@
+package views
+
+type RateProjectRequest struct {
+	Stars  int64  `json:"stars"`
+	Review string `json:"review"`
+}
+
+type HideRatingRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+type Rating struct {
+	ID        int64  `json:"id"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	UserID    int64  `json:"user_id"`
+	UserName  string `json:"user_name"`
+	ProjectID int64  `json:"project_id"`
+	Stars     int64  `json:"stars"`
+	Review    string `json:"review"`
+	Hidden    bool   `json:"hidden"`
+}
+
+type RatingStats struct {
+	Average float64 `json:"average"`
+	Count   int64   `json:"count"`
+}
@@ -9,6 +9,8 @@ type CreateRoleRequest struct {
 	Types         int64  `json:"types"`
 	Users         int64  `json:"users"`
 	Roles         int64  `json:"roles"`
+	// RequireTwoFactor grants the role only to the users with 2FA on
+	RequireTwoFactor bool `json:"require_two_factor"`
 }
 
 type UpdateRoleRequest struct {
@@ -20,4 +22,6 @@ type UpdateRoleRequest struct {
 	Types         int64  `json:"types"`
 	Users         int64  `json:"users"`
 	Roles         int64  `json:"roles"`
+	// RequireTwoFactor grants the role only to the users with 2FA on
+	RequireTwoFactor bool `json:"require_two_factor"`
 }
@@ -0,0 +1,58 @@
+package views
+
+import "github.com/Bayan2019/go-ozinshe/repositories/database"
+
+type Season struct {
+	ID        int64     `json:"id"`
+	ProjectID int64     `json:"project_id"`
+	Number    int64     `json:"number"`
+	Title     string    `json:"title"`
+	Synopsis  string    `json:"synopsis"`
+	Episodes  []Episode `json:"episodes"`
+}
+
+type Episode struct {
+	ID             int64          `json:"id"`
+	SeasonID       int64          `json:"season_id"`
+	Number         int64          `json:"number"`
+	Title          string         `json:"title"`
+	Synopsis       string         `json:"synopsis"`
+	DurationInSecs int64          `json:"duration_in_secs"`
+	ReleaseDate    string         `json:"release_date"`
+	Thumbnail      database.Image `json:"thumbnail"`
+	Video          database.Video `json:"video"`
+}
+
+type CreateSeasonRequest struct {
+	Number   int64  `json:"number"`
+	Title    string `json:"title"`
+	Synopsis string `json:"synopsis"`
+}
+
+type UpdateSeasonRequest struct {
+	Number   int64  `json:"number"`
+	Title    string `json:"title"`
+	Synopsis string `json:"synopsis"`
+}
+
+// CreateEpisodeRequest refers to an image and a video of the same project,
+// release_date is YYYY-MM-DD
+type CreateEpisodeRequest struct {
+	Number         int64  `json:"number"`
+	Title          string `json:"title"`
+	Synopsis       string `json:"synopsis"`
+	DurationInSecs int64  `json:"duration_in_secs"`
+	ReleaseDate    string `json:"release_date"`
+	ThumbnailID    string `json:"thumbnail_id"`
+	VideoID        string `json:"video_id"`
+}
+
+type UpdateEpisodeRequest struct {
+	Number         int64  `json:"number"`
+	Title          string `json:"title"`
+	Synopsis       string `json:"synopsis"`
+	DurationInSecs int64  `json:"duration_in_secs"`
+	ReleaseDate    string `json:"release_date"`
+	ThumbnailID    string `json:"thumbnail_id"`
+	VideoID        string `json:"video_id"`
+}
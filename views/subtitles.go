@@ -0,0 +1,17 @@
+package views
+
+// Subtitle is a WebVTT track of the video,
+// the player shows the default one first
+type Subtitle struct {
+	ID        int64  `json:"id"`
+	VideoID   string `json:"video_id"`
+	Language  string `json:"language"`
+	Label     string `json:"label"`
+	IsDefault bool   `json:"is_default"`
+}
+
+type UpdateSubtitleRequest struct {
+	Language  string `json:"language"`
+	Label     string `json:"label"`
+	IsDefault bool   `json:"is_default"`
+}
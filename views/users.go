@@ -34,6 +34,48 @@ type User struct {
 	DateOfBirth string          `json:"date_of_birth"`
 	Phone       string          `json:"phone"`
 	Roles       []database.Role `json:"roles"`
+	// EmailVerified is false until the link of the verification email is followed
+	EmailVerified bool `json:"email_verified"`
+	// TwoFactor is true when the sign in asks for a TOTP code
+	TwoFactor bool `json:"two_factor"`
+	// Session of the access token, empty for the tokens issued before the sessions
+	Session string `json:"-"`
+}
+
+// Session is a sign in of the user, it lasts while its refresh tokens are rotated
+type Session struct {
+	Id          string `json:"id"`
+	UserAgent   string `json:"user_agent"`
+	Ip          string `json:"ip"`
+	CreatedAt   string `json:"created_at"`
+	RefreshedAt string `json:"refreshed_at"`
+	ExpiresAt   string `json:"expires_at"`
+	// Current session of the access token
+	Current bool `json:"current"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	// Token from the email
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type VerifyEmailRequest struct {
+	// Token from the email
+	Token string `json:"token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
 }
 
 type SignInRequest struct {
@@ -45,3 +87,37 @@ type TokensResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 }
+
+// TwoFactorChallengeResponse is the answer of the sign in with 2FA on
+type TwoFactorChallengeResponse struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresAt      string `json:"expires_at"`
+}
+
+type TwoFactorSignInRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	// Code from the authenticator app or a recovery code
+	Code string `json:"code"`
+}
+
+type TwoFactorCodeRequest struct {
+	// Code from the authenticator app or a recovery code
+	Code string `json:"code"`
+}
+
+type DisableTwoFactorRequest struct {
+	CurrentPassword string `json:"current_password"`
+	// Code from the authenticator app or a recovery code
+	Code string `json:"code"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth:// URI of the QR code for the authenticator app
+	URI string `json:"uri"`
+}
+
+type RecoveryCodesResponse struct {
+	// RecoveryCodes are shown once, each of them replaces a code once
+	RecoveryCodes []string `json:"recovery_codes"`
+}